@@ -0,0 +1,77 @@
+package semchunk
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Versions of the splitting algorithm, see WithAlgorithmVersion
+const (
+	// AlgorithmV1 splits whitespace-separated text as the first release did: only ASCII
+	// whitespace separates words, sentence terminators are tried one at a time, every
+	// character of "..." and "?!" is split at, list item markers such as "1." may be split
+	// from their items and an overlap given in tokens is ignored, while a fractional overlap
+	// applies
+	AlgorithmV1 = "v1"
+	// AlgorithmV2 treats Unicode space separators as whitespace, splits after all sentence
+	// terminators in one pass, splits at multi-character terminators as a whole and keeps
+	// list item markers with their items
+	AlgorithmV2 = "v2"
+	// AlgorithmLatest is the version used when none is set
+	AlgorithmLatest = AlgorithmV2
+)
+
+// AlgorithmVersions returns the versions accepted by WithAlgorithmVersion, oldest first
+func AlgorithmVersions() []string {
+	return []string{AlgorithmV1, AlgorithmV2}
+}
+
+// WithAlgorithmVersion freezes the splitting algorithm at version, such as AlgorithmV1, so a
+// long-lived index keeps getting byte-identical chunks for new documents while the default
+// algorithm improves. It pins how text is cut by the recursive splitter; options enabled
+// explicitly still apply. An empty version uses AlgorithmLatest.
+func WithAlgorithmVersion(version string) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.AlgorithmVersion = version
+		opts.compileWhitespace()
+	}
+}
+
+// isAlgorithmVersion reports whether version is empty or one of AlgorithmVersions
+func isAlgorithmVersion(version string) bool {
+	if version == "" {
+		return true
+	}
+	for _, v := range AlgorithmVersions() {
+		if version == v {
+			return true
+		}
+	}
+	return false
+}
+
+// v1WhitespaceClass matches the whitespace of AlgorithmV1
+const v1WhitespaceClass = `[\s]`
+
+// v1PrecederTiers returns the punctuation tiers of AlgorithmV1, every terminator and
+// separator on its own
+func v1PrecederTiers(splitters []string) [][]string {
+	tiers := make([][]string, len(splitters))
+	for i, splitter := range splitters {
+		tiers[i] = []string{splitter}
+	}
+	return tiers
+}
+
+// v1SplitPunctuation splits text at every occurrence of splitter, as AlgorithmV1 does
+func v1SplitPunctuation(text string, splitter string, level SplitLevel) textSplit {
+	return textSplit{splitter: splitter, level: level, splits: strings.Split(text, splitter)}
+}
+
+// v1IsSpace reports whether r is whitespace to AlgorithmV1
+func v1IsSpace(r rune) bool {
+	return unicode.IsSpace(r)
+}
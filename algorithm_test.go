@@ -0,0 +1,82 @@
+package semchunk
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithAlgorithmVersion(t *testing.T) {
+	v1 := newWordSplitter(t, 10, 0, WithAlgorithmVersion(AlgorithmV1))
+	latest := newWordSplitter(t, 10, 0, WithAlgorithmVersion(AlgorithmLatest))
+	tests := []struct {
+		name       string
+		text       string
+		wantV1     []string
+		wantLatest []string
+	}{
+		{"terminators one at a time", "Is it? Yes. Done", []string{"Is it? Yes.", "Done"}, []string{"Is it?", "Yes.", "Done"}},
+		{"multi-character terminators", "Wait...what", []string{"Wait", "", "", "what"}, []string{"Wait", "what"}},
+		{"list item markers", "1. Buy milk. 2. Buy eggs.", []string{"1.", "Buy milk.", "2.", "Buy eggs."}, []string{"1. Buy milk.", "2. Buy eggs."}},
+		{"ASCII whitespace only", "one\u00a0two", []string{"o", "n", "e", "\u00a0", "t", "w", "o"}, []string{"one", "two"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantV1, semanticSplit(tt.text, v1.splitRules()).splits)
+			assert.Equal(t, tt.wantLatest, semanticSplit(tt.text, latest.splitRules()).splits)
+		})
+	}
+
+	// an unset version is the latest one
+	text := "1. Buy milk. 2. Buy eggs. Is it? Yes. Wait...what"
+	assert.Equal(t, latest.Split(text), newWordSplitter(t, 10, 0).Split(text))
+
+	_, err := NewTextSplitter(10, 0, func(string) int { return 1 }, WithAlgorithmVersion("v0"))
+	assert.ErrorContains(t, err, `unknown algorithm version "v0"`)
+
+	splitter, err := NewFromConfig(Config{ChunkSize: 10, SizeUnit: SizeWords, AlgorithmVersion: AlgorithmV1})
+	assert.NoError(t, err)
+	assert.Equal(t, AlgorithmV1, splitter.opts.AlgorithmVersion)
+}
+
+// TestAlgorithmV1Fixtures checks AlgorithmV1 against the chunks of the first release, which
+// testdata/v1/generate.go wrote
+func TestAlgorithmV1Fixtures(t *testing.T) {
+	data, err := os.ReadFile("testdata/v1/fixtures.json")
+	assert.NoError(t, err)
+	var fixtures struct {
+		Cases []struct {
+			Text      string   `json:"text"`
+			ChunkSize int      `json:"chunk_size"`
+			Counter   string   `json:"counter"`
+			Overlap   float64  `json:"overlap"`
+			Chunks    []string `json:"chunks"`
+		} `json:"cases"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &fixtures))
+	assert.NotEmpty(t, fixtures.Cases)
+
+	counters := map[string]func(string) int{
+		"words": func(text string) int { return len(strings.Fields(text)) },
+		"chars": utf8.RuneCountInString,
+	}
+	for i, tc := range fixtures.Cases {
+		t.Run(fmt.Sprintf("%d/%s/%d", i, tc.Counter, tc.ChunkSize), func(t *testing.T) {
+			var splitter *TextSplitter
+			var err error
+			if tc.Overlap == math.Trunc(tc.Overlap) {
+				splitter, err = NewTextSplitter(tc.ChunkSize, int(tc.Overlap), counters[tc.Counter], WithAlgorithmVersion(AlgorithmV1))
+			} else {
+				splitter, err = NewTextSplitter(tc.ChunkSize, float32(tc.Overlap), counters[tc.Counter], WithAlgorithmVersion(AlgorithmV1))
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Chunks, splitter.Split(tc.Text))
+		})
+	}
+}
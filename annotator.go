@@ -0,0 +1,106 @@
+package semchunk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ChunkAnnotator generates a summary or title for a chunk, typically by calling a language model
+type ChunkAnnotator func(ctx context.Context, chunk Chunk) (string, error)
+
+// WithChunkAnnotator sets an annotator invoked for every chunk by SplitChunksContext.
+// Its result is stored in the chunk's Metadata.Summary.
+func WithChunkAnnotator(annotator ChunkAnnotator) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.Annotator = annotator
+	}
+}
+
+// WithAnnotatorConcurrency sets how many annotator calls may run at the same time, default 1
+func WithAnnotatorConcurrency(n int) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.AnnotatorConcurrency = n
+	}
+}
+
+// SplitChunksContext splits text like SplitChunks and then runs the configured annotator
+// on every chunk. It stops at the first annotator error and returns it. Splitting itself is
+// aborted when ctx is done or a limit set by WithMaxSplitOps or WithTimeout is exceeded.
+func (c *TextSplitter) SplitChunksContext(ctx context.Context, text string) ([]Chunk, error) {
+	chunks, err := c.splitChunksAnnotated(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.summarize(ctx, chunks); err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+// summarize stores the summary of the ChunkAnnotator, if one is set, in every chunk
+func (c *TextSplitter) summarize(ctx context.Context, chunks []Chunk) error {
+	if c.opts.Annotator == nil {
+		return nil
+	}
+	return forEachChunk(ctx, chunks, c.opts.AnnotatorConcurrency, func(ctx context.Context, chunk *Chunk) error {
+		summary, err := c.opts.Annotator(ctx, *chunk)
+		if err != nil {
+			return fmt.Errorf("annotating chunk %d: %w", chunk.Index, err)
+		}
+		chunk.Metadata.Summary = summary
+		return nil
+	})
+}
+
+// forEachChunk calls fn for every chunk with at most concurrency calls in flight.
+// The first error cancels the context passed to the remaining calls and is returned.
+func forEachChunk(ctx context.Context, chunks []Chunk, concurrency int, fn func(ctx context.Context, chunk *Chunk) error) error {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, concurrency)
+	for i := range chunks {
+		select {
+		case <-ctx.Done():
+		case sem <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(chunk *Chunk) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := fn(ctx, chunk); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+			}
+		}(&chunks[i])
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,43 @@
+package semchunk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitChunksContextAnnotator(t *testing.T) {
+	var running, maxRunning int32
+	annotator := func(ctx context.Context, chunk Chunk) (string, error) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		return strings.ToUpper(strings.Fields(chunk.Text)[0]), nil
+	}
+
+	splitter := newWordSplitter(t, 3, 0, WithChunkAnnotator(annotator), WithAnnotatorConcurrency(2))
+	chunks, err := splitter.SplitChunksContext(context.Background(), "One two three. Four five six. Seven eight nine.")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ONE", "FOUR", "SEVEN"}, []string{
+		chunks[0].Metadata.Summary, chunks[1].Metadata.Summary, chunks[2].Metadata.Summary,
+	})
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(2))
+}
+
+func TestSplitChunksContextAnnotatorError(t *testing.T) {
+	failing := func(ctx context.Context, chunk Chunk) (string, error) {
+		return "", errors.New("model unavailable")
+	}
+	splitter := newWordSplitter(t, 3, 0, WithChunkAnnotator(failing))
+	_, err := splitter.SplitChunksContext(context.Background(), "One two three. Four five six.")
+	assert.ErrorContains(t, err, "model unavailable")
+}
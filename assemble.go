@@ -0,0 +1,267 @@
+package semchunk
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ContextOrder is the order in which AssembleContext places chunks
+type ContextOrder int
+
+const (
+	// OrderByScore places the chunks with the highest Metadata.Score first
+	OrderByScore ContextOrder = iota
+	// OrderByDocument places chunks in document order, documents in order of first appearance
+	OrderByDocument
+)
+
+// AssembleOption configures AssembleContext
+type AssembleOption struct {
+	Order ContextOrder
+	// Separator is put between chunks, default a blank line
+	Separator string
+	// Truncate cuts the first chunk that does not fit to the remaining budget instead of
+	// dropping it
+	Truncate bool
+}
+
+// WithContextOrder sets the order of the passages in the context, default OrderByScore
+func WithContextOrder(order ContextOrder) func(*AssembleOption) {
+	return func(opts *AssembleOption) {
+		if opts == nil {
+			opts = &AssembleOption{}
+		}
+		opts.Order = order
+	}
+}
+
+// WithContextSeparator sets the text put between passages, default a blank line
+func WithContextSeparator(separator string) func(*AssembleOption) {
+	return func(opts *AssembleOption) {
+		if opts == nil {
+			opts = &AssembleOption{}
+		}
+		opts.Separator = separator
+	}
+}
+
+// WithTruncation cuts the first chunk that does not fit to the remaining budget instead of
+// dropping it
+func WithTruncation(truncate bool) func(*AssembleOption) {
+	return func(opts *AssembleOption) {
+		if opts == nil {
+			opts = &AssembleOption{}
+		}
+		opts.Truncate = truncate
+	}
+}
+
+// AssembleContext builds a prompt context from retrieved chunks that fits within budget tokens.
+// Chunks are taken by descending Metadata.Score, and chunks that do not fit are dropped, or
+// with WithTruncation the first of them is cut to fit. Selected chunks of the same document
+// that overlap or follow each other are merged into one passage, then the passages are joined
+// in the configured order.
+func (c *TextSplitter) AssembleContext(chunks []Chunk, budget int, opts ...func(*AssembleOption)) string {
+	assembleOpts := &AssembleOption{Separator: "\n\n"}
+	for _, opt := range opts {
+		opt(assembleOpts)
+	}
+
+	priority := scoreOrder(chunks)
+	separatorTokens := c.countTokenFunc(assembleOpts.Separator)
+	used := 0
+	selected := make([]Chunk, 0)
+	for _, i := range priority {
+		chunk := chunks[i]
+		tokens := chunk.Tokens
+		if tokens == 0 {
+			tokens = c.countTokenFunc(chunk.Text)
+		}
+		if len(selected) > 0 {
+			tokens += separatorTokens
+		}
+		if used+tokens <= budget {
+			selected = append(selected, chunk)
+			used += tokens
+			continue
+		}
+		if !assembleOpts.Truncate {
+			continue
+		}
+		remaining := budget - used
+		if len(selected) > 0 {
+			remaining -= separatorTokens
+		}
+		if text := c.truncateTokens(chunk.Text, remaining); text != "" {
+			if exactChunk(chunk) {
+				chunk.End = chunk.Start + len(text)
+			}
+			chunk.Text = text
+			selected = append(selected, chunk)
+		}
+		break
+	}
+
+	passages := mergeAdjacent(selected, documentRanks(chunks), 0)
+	if assembleOpts.Order == OrderByScore {
+		sort.SliceStable(passages, func(a, b int) bool {
+			return passages[a].Metadata.Score > passages[b].Metadata.Score
+		})
+	}
+	texts := make([]string, len(passages))
+	for i, passage := range passages {
+		texts[i] = passage.Text
+	}
+	return strings.Join(texts, assembleOpts.Separator)
+}
+
+// TopKByScoreWithinBudget returns at most k chunks with the highest Metadata.Score whose
+// tokens add up to at most budget, highest score first. Chunks that do not fit in what is
+// left of the budget are skipped in favour of lower-scored chunks that do. Chunks with
+// Tokens unset are counted with the splitter's counter.
+func (c *TextSplitter) TopKByScoreWithinBudget(chunks []Chunk, k, budget int) []Chunk {
+	selected := make([]Chunk, 0)
+	used := 0
+	for _, i := range scoreOrder(chunks) {
+		if len(selected) >= k {
+			break
+		}
+		tokens := chunks[i].Tokens
+		if tokens == 0 {
+			tokens = c.countTokenFunc(chunks[i].Text)
+		}
+		if used+tokens <= budget {
+			selected = append(selected, chunks[i])
+			used += tokens
+		}
+	}
+	return selected
+}
+
+// scoreOrder returns the indexes of chunks by descending Metadata.Score, ties in input order
+func scoreOrder(chunks []Chunk) []int {
+	order := make([]int, len(chunks))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return chunks[order[a]].Metadata.Score > chunks[order[b]].Metadata.Score
+	})
+	return order
+}
+
+// documentRanks numbers the documents of chunks in order of first appearance
+func documentRanks(chunks []Chunk) map[string]int {
+	ranks := make(map[string]int)
+	for _, chunk := range chunks {
+		if _, ok := ranks[chunk.Metadata.DocumentID]; !ok {
+			ranks[chunk.Metadata.DocumentID] = len(ranks)
+		}
+	}
+	return ranks
+}
+
+// exactChunk reports whether the text of chunk is the span of the document its offsets name,
+// which is not the case once a context prefix was prepended
+func exactChunk(chunk Chunk) bool {
+	return len(chunk.Text) == chunk.End-chunk.Start
+}
+
+// CoalesceAdjacent merges retrieved chunks of the same document that overlap, are consecutive
+// or are at most maxGapBytes apart in the document into passages, so a prompt shows
+// contiguous text instead of overlapping fragments. Passages are in document order, documents
+// in order of first appearance, and keep the highest score of their chunks; their Tokens is
+// 0 since they are not counted. Consecutive chunks are joined with a space; the text between
+// other chunks is not known and is replaced by the marker " [...] ". Chunks whose text was
+// changed, for example by a prepended context prefix, are left alone.
+func CoalesceAdjacent(chunks []Chunk, maxGapBytes int) []Chunk {
+	return mergeAdjacent(chunks, documentRanks(chunks), maxGapBytes)
+}
+
+// gapMarker stands for the text between merged chunks that are not consecutive
+const gapMarker = " [...] "
+
+// mergeAdjacent sorts chunks into document order and merges chunks of the same document that
+// overlap, are consecutive or are at most maxGap bytes apart, keeping the highest score.
+// Consecutive chunks are joined with a space and other chunks with gapMarker, since the text
+// between them is not known.
+func mergeAdjacent(chunks []Chunk, ranks map[string]int, maxGap int) []Chunk {
+	sorted := append([]Chunk(nil), chunks...)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		ra, rb := ranks[sorted[a].Metadata.DocumentID], ranks[sorted[b].Metadata.DocumentID]
+		if ra != rb {
+			return ra < rb
+		}
+		return sorted[a].Start < sorted[b].Start
+	})
+
+	merged := make([]Chunk, 0, len(sorted))
+	// tails holds the last chunk merged into every passage
+	tails := make([]Chunk, 0, len(sorted))
+	for _, chunk := range sorted {
+		if len(merged) == 0 {
+			merged, tails = append(merged, chunk), append(tails, chunk)
+			continue
+		}
+		last, tail := &merged[len(merged)-1], &tails[len(tails)-1]
+		if tail.Metadata.DocumentID != chunk.Metadata.DocumentID || !exactChunk(*tail) || !exactChunk(chunk) {
+			merged, tails = append(merged, chunk), append(tails, chunk)
+			continue
+		}
+		switch {
+		case chunk.Start <= tail.End:
+			if chunk.End > tail.End {
+				last.Text += chunk.Text[tail.End-chunk.Start:]
+			}
+		case chunk.Index == tail.Index+1:
+			// the gap between consecutive chunks is whitespace, but not necessarily a space
+			last.Text += " " + chunk.Text
+		case chunk.Start-tail.End <= maxGap:
+			last.Text += gapMarker + chunk.Text
+		default:
+			merged, tails = append(merged, chunk), append(tails, chunk)
+			continue
+		}
+		if chunk.End > last.End {
+			last.End = chunk.End
+		}
+		last.Tokens = 0
+		if chunk.Metadata.Score > last.Metadata.Score {
+			last.Metadata.Score = chunk.Metadata.Score
+		}
+		if chunk.End > tail.End {
+			*tail = chunk
+		}
+	}
+	return merged
+}
+
+// truncateTokens returns the longest prefix of text ending at a word boundary that has at most
+// n tokens, or "" if not even the first word fits
+func (c *TextSplitter) truncateTokens(text string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	ends := make([]int, 0)
+	inWord := false
+	for i, r := range text {
+		space := unicode.IsSpace(r)
+		if space && inWord {
+			ends = append(ends, i)
+		}
+		inWord = !space
+	}
+	if inWord {
+		ends = append(ends, len(text))
+	}
+
+	// counts grow with the prefix, so search for the last end that fits
+	k := sort.Search(len(ends), func(i int) bool {
+		return c.countTokenFunc(text[:ends[i]]) > n
+	})
+	if k == 0 {
+		return ""
+	}
+	return text[:ends[k-1]]
+}
@@ -0,0 +1,116 @@
+package semchunk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssembleContext(t *testing.T) {
+	splitter := newWordSplitter(t, 100, 0)
+	doc := "Alpha beta gamma. Delta epsilon zeta. Eta theta iota. Kappa lambda mu."
+	chunk := func(doc string, index, start, end int, score float64) Chunk {
+		return Chunk{Index: index, Text: doc[start:end], Start: start, End: end, Tokens: len(strings.Fields(doc[start:end])), Metadata: Metadata{DocumentID: "a", Score: score}}
+	}
+	other := Chunk{Text: "Other document text.", Start: 0, End: 20, Metadata: Metadata{DocumentID: "b", Score: 0.7}}
+
+	tests := []struct {
+		name   string
+		chunks []Chunk
+		budget int
+		opts   []func(*AssembleOption)
+		want   string
+	}{
+		{
+			name:   "score order",
+			chunks: []Chunk{chunk(doc, 0, 0, 17, 0.2), chunk(doc, 2, 38, 53, 0.9), other},
+			budget: 100,
+			want:   "Eta theta iota.\n\nOther document text.\n\nAlpha beta gamma.",
+		},
+		{
+			name:   "document order",
+			chunks: []Chunk{chunk(doc, 2, 38, 53, 0.9), other, chunk(doc, 0, 0, 17, 0.2)},
+			budget: 100,
+			opts:   []func(*AssembleOption){WithContextOrder(OrderByDocument), WithContextSeparator("\n---\n")},
+			want:   "Alpha beta gamma.\n---\nEta theta iota.\n---\nOther document text.",
+		},
+		{
+			name:   "drop lowest scores over budget",
+			chunks: []Chunk{chunk(doc, 0, 0, 17, 0.2), chunk(doc, 2, 38, 53, 0.9), other},
+			budget: 7,
+			want:   "Eta theta iota.\n\nOther document text.",
+		},
+		{
+			name:   "truncate",
+			chunks: []Chunk{chunk(doc, 0, 0, 17, 0.2), chunk(doc, 2, 38, 53, 0.9)},
+			budget: 5,
+			opts:   []func(*AssembleOption){WithTruncation(true)},
+			want:   "Eta theta iota.\n\nAlpha beta",
+		},
+		{
+			name:   "merge overlapping and consecutive",
+			chunks: []Chunk{chunk(doc, 1, 18, 37, 0.5), other, chunk(doc, 0, 0, 23, 0.9), chunk(doc, 2, 38, 53, 0.1), chunk(doc, 4, 54, 70, 0.3)},
+			budget: 100,
+			want:   "Alpha beta gamma. Delta epsilon zeta. Eta theta iota.\n\nOther document text.\n\nKappa lambda mu.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitter.AssembleContext(tt.chunks, tt.budget, tt.opts...))
+		})
+	}
+}
+
+func TestCoalesceAdjacent(t *testing.T) {
+	doc := "Alpha beta gamma. Delta epsilon zeta. Eta theta iota. Kappa lambda mu."
+	chunk := func(index, start, end int, score float64) Chunk {
+		return Chunk{Index: index, Text: doc[start:end], Start: start, End: end, Tokens: 3, Metadata: Metadata{DocumentID: "a", Score: score}}
+	}
+	other := Chunk{Text: "Other document text.", Start: 0, End: 20, Tokens: 3, Metadata: Metadata{DocumentID: "b", Score: 0.7}}
+	chunks := []Chunk{chunk(4, 54, 70, 0.3), other, chunk(2, 38, 53, 0.9), chunk(0, 0, 23, 0.2), chunk(1, 18, 37, 0.5)}
+
+	passages := CoalesceAdjacent(chunks, 0)
+	assert.Equal(t, []string{"Alpha beta gamma. Delta epsilon zeta. Eta theta iota.", "Kappa lambda mu.", "Other document text."}, chunkTexts(passages))
+	assert.Equal(t, [2]int{0, 53}, [2]int{passages[0].Start, passages[0].End})
+	assert.Equal(t, 0.9, passages[0].Metadata.Score)
+	assert.Equal(t, 0, passages[0].Tokens)
+	assert.Equal(t, 3, passages[1].Tokens)
+
+	// the gap to the chunk after the next one is marked, since it may hold any text
+	passages = CoalesceAdjacent(chunks, 1)
+	assert.Equal(t, []string{"Alpha beta gamma. Delta epsilon zeta. Eta theta iota. [...] Kappa lambda mu.", "Other document text."}, chunkTexts(passages))
+	assert.Equal(t, [2]int{0, 70}, [2]int{passages[0].Start, passages[0].End})
+	assert.Equal(t, "Eta theta iota.", chunks[2].Text)
+}
+
+func TestTopKByScoreWithinBudget(t *testing.T) {
+	splitter := newWordSplitter(t, 100, 0)
+	chunks := []Chunk{
+		{Text: "low score", Tokens: 2, Metadata: Metadata{Score: 0.1}},
+		{Text: "best match here", Tokens: 3, Metadata: Metadata{Score: 0.9}},
+		{Text: "good but far too long", Metadata: Metadata{Score: 0.8}},
+		{Text: "fine", Tokens: 1, Metadata: Metadata{Score: 0.5}},
+	}
+	tests := []struct {
+		k, budget int
+		want      []string
+	}{
+		{4, 100, []string{"best match here", "good but far too long", "fine", "low score"}},
+		// the uncounted chunk is counted, 5 tokens, and skipped
+		{4, 6, []string{"best match here", "fine", "low score"}},
+		{2, 6, []string{"best match here", "fine"}},
+		{3, 0, []string{}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, chunkTexts(splitter.TopKByScoreWithinBudget(chunks, tt.k, tt.budget)))
+	}
+}
+
+func TestTruncateTokens(t *testing.T) {
+	splitter := newWordSplitter(t, 100, 0)
+	assert.Equal(t, "one two", splitter.truncateTokens("one two  three", 2))
+	assert.Equal(t, "one two  three", splitter.truncateTokens("one two  three", 3))
+	assert.Equal(t, "", splitter.truncateTokens("one two", 0))
+}
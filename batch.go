@@ -0,0 +1,146 @@
+package semchunk
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+)
+
+const (
+	// defaultTokenCacheEntries is the number of counts a token cache keeps when no limit is given
+	defaultTokenCacheEntries = 10000
+	// maxCachedTextBytes is the length above which texts are counted without being cached, long
+	// pieces rarely repeat and would keep large parts of their documents alive
+	maxCachedTextBytes = 4096
+)
+
+// Batch splits many documents with one splitter and a token count cache shared between them,
+// so text repeated across a corpus, such as headers, footers and legal boilerplate, is counted
+// once. Split may be called from several goroutines.
+type Batch struct {
+	splitter *TextSplitter
+}
+
+// NewBatch returns a Batch splitting with c. The cache holds at most maxEntries counts, 10000
+// if maxEntries is 0, and evicts the least recently used count when full. Texts longer than
+// 4 KiB are not cached.
+func (c *TextSplitter) NewBatch(maxEntries int) *Batch {
+	cache := newTokenCache(c, maxEntries)
+	splitter := *c
+	splitter.countTokenFunc = cache.CountTokens
+	if c.batchCounter != nil {
+		splitter.batchCounter = cache
+	}
+	return &Batch{splitter: &splitter}
+}
+
+// Split splits doc like SplitDocument
+func (b *Batch) Split(ctx context.Context, doc Document) ([]Chunk, error) {
+	return b.splitter.SplitDocument(ctx, doc)
+}
+
+// tokenCache memoizes token counts, safe for concurrent use
+type tokenCache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// order holds the tokenCacheEntry values, most recently used first
+	order *list.List
+	// max is the number of counts kept before the least recently used one is evicted
+	max   int
+	count func(text string) int
+	batch BatchTokenCounter
+}
+
+type tokenCacheEntry struct {
+	text string
+	n    int
+}
+
+func newTokenCache(c *TextSplitter, max int) *tokenCache {
+	if max <= 0 {
+		max = defaultTokenCacheEntries
+	}
+	return &tokenCache{entries: make(map[string]*list.Element), order: list.New(), max: max, count: c.countTokenFunc, batch: c.batchCounter}
+}
+
+// lookup returns the count of text, t.mu must be held
+func (t *tokenCache) lookup(text string) (int, bool) {
+	element, ok := t.entries[text]
+	if !ok {
+		return 0, false
+	}
+	t.order.MoveToFront(element)
+	return element.Value.(*tokenCacheEntry).n, true
+}
+
+// store records counts, t.mu must be held
+func (t *tokenCache) store(text string, n int) {
+	if len(text) > maxCachedTextBytes {
+		return
+	}
+	if element, ok := t.entries[text]; ok {
+		element.Value.(*tokenCacheEntry).n = n
+		t.order.MoveToFront(element)
+		return
+	}
+	// the text is usually a substring of a document, do not keep the document alive
+	text = strings.Clone(text)
+	t.entries[text] = t.order.PushFront(&tokenCacheEntry{text: text, n: n})
+	if t.order.Len() > t.max {
+		oldest := t.order.Back()
+		t.order.Remove(oldest)
+		delete(t.entries, oldest.Value.(*tokenCacheEntry).text)
+	}
+}
+
+func (t *tokenCache) CountTokens(text string) int {
+	t.mu.Lock()
+	n, ok := t.lookup(text)
+	t.mu.Unlock()
+	if ok {
+		return n
+	}
+	n = t.count(text)
+	t.mu.Lock()
+	t.store(text, n)
+	t.mu.Unlock()
+	return n
+}
+
+func (t *tokenCache) CountTokensBatch(texts []string) []int {
+	counts := make([]int, len(texts))
+	var missing []string
+	var missingAt []int
+	t.mu.Lock()
+	for i, text := range texts {
+		if n, ok := t.lookup(text); ok {
+			counts[i] = n
+		} else {
+			missing = append(missing, text)
+			missingAt = append(missingAt, i)
+		}
+	}
+	t.mu.Unlock()
+	if len(missing) == 0 {
+		return counts
+	}
+
+	var missingCounts []int
+	if t.batch != nil {
+		missingCounts = t.batch.CountTokensBatch(missing)
+	}
+	if len(missingCounts) != len(missing) {
+		missingCounts = make([]int, len(missing))
+		for i, text := range missing {
+			missingCounts[i] = t.count(text)
+		}
+	}
+	t.mu.Lock()
+	for j, i := range missingAt {
+		counts[i] = missingCounts[j]
+		t.store(missing[j], missingCounts[j])
+	}
+	t.mu.Unlock()
+	return counts
+}
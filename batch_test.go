@@ -0,0 +1,95 @@
+package semchunk
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatch(t *testing.T) {
+	var calls int64
+	splitter, err := NewTextSplitter(6, 0, func(text string) int {
+		atomic.AddInt64(&calls, 1)
+		return len(strings.Fields(text))
+	})
+	assert.NoError(t, err)
+
+	boilerplate := "Copyright Example Corp. All rights reserved."
+	docs := []Document{
+		{ID: "a", Text: "First document body text. " + boilerplate},
+		{ID: "b", Text: "Second document body text. " + boilerplate},
+	}
+	batch := splitter.NewBatch(0)
+	first, err := batch.Split(context.Background(), docs[0])
+	assert.NoError(t, err)
+	expected, err := splitter.SplitDocument(context.Background(), docs[0])
+	assert.NoError(t, err)
+	assert.Equal(t, expected, first)
+
+	// counting the same document again is answered from the cache
+	before := atomic.LoadInt64(&calls)
+	_, err = batch.Split(context.Background(), docs[0])
+	assert.NoError(t, err)
+	assert.Equal(t, before, atomic.LoadInt64(&calls))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(doc Document) {
+			defer wg.Done()
+			chunks, err := batch.Split(context.Background(), doc)
+			assert.NoError(t, err)
+			assert.Equal(t, doc.ID, chunks[0].Metadata.DocumentID)
+		}(docs[i%2])
+	}
+	wg.Wait()
+}
+
+func TestTokenCache(t *testing.T) {
+	var calls int64
+	cache := newTokenCache(&TextSplitter{countTokenFunc: func(text string) int {
+		atomic.AddInt64(&calls, 1)
+		return len(strings.Fields(text))
+	}}, 3)
+	assert.Equal(t, 2, cache.CountTokens("a b"))
+	assert.Equal(t, 2, cache.CountTokens("a b"))
+	assert.Equal(t, []int{2, 3, 1}, cache.CountTokensBatch([]string{"a b", "c d e", "f"}))
+	assert.Equal(t, int64(3), atomic.LoadInt64(&calls))
+
+	// the cache is full, "a b" was used last and "c d e" is evicted
+	assert.Equal(t, 2, cache.CountTokens("a b"))
+	assert.Equal(t, 1, cache.CountTokens("g"))
+	assert.Equal(t, 3, cache.order.Len())
+	assert.Contains(t, cache.entries, "a b")
+	assert.NotContains(t, cache.entries, "c d e")
+	assert.Equal(t, int64(4), atomic.LoadInt64(&calls))
+
+	// long texts are counted every time
+	long := strings.Repeat("word ", maxCachedTextBytes)
+	assert.Equal(t, maxCachedTextBytes, cache.CountTokens(long))
+	assert.NotContains(t, cache.entries, long)
+
+	// keys do not share memory with the document they were cut from
+	doc := "h i j k"
+	cache.CountTokens(doc[:3])
+	for key := range cache.entries {
+		if key == "h i" {
+			assert.NotSame(t, unsafe.StringData(doc), unsafe.StringData(key))
+		}
+	}
+}
+
+func TestTokenCacheDefaultLimit(t *testing.T) {
+	cache := newTokenCache(&TextSplitter{countTokenFunc: func(text string) int { return len(text) }}, 0)
+	for i := 0; i <= defaultTokenCacheEntries; i++ {
+		cache.CountTokens(strconv.Itoa(i))
+	}
+	assert.Equal(t, defaultTokenCacheEntries, cache.order.Len())
+	assert.NotContains(t, cache.entries, "0")
+}
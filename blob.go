@@ -0,0 +1,142 @@
+package semchunk
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// BlobMode sets how long base64 and hex blobs and data URIs are handled. They are common in
+// scraped pages and exported documents, carry no meaning for retrieval, and waste embedding
+// tokens.
+type BlobMode int
+
+const (
+	// BlobKeep splits blobs like the text around them
+	BlobKeep BlobMode = iota
+	// BlobDrop removes blobs before splitting
+	BlobDrop
+	// BlobTruncate cuts blobs to their first blobTruncateLength characters before splitting
+	BlobTruncate
+	// BlobIsolate splits text around blobs so every blob is in chunks of its own, flagged with
+	// "blob" in Metadata.Flags
+	BlobIsolate
+)
+
+// blobModeNames are the names BlobMode values are serialized as, in the order of the values
+var blobModeNames = []string{"keep", "drop", "truncate", "isolate"}
+
+func (m BlobMode) MarshalText() ([]byte, error) {
+	return marshalEnum(m, blobModeNames)
+}
+
+func (m *BlobMode) UnmarshalText(text []byte) error {
+	return unmarshalEnum(text, blobModeNames, m)
+}
+
+// defaultBlobMinLength is the minimum length of a blob if WithBlobs is given none. It is
+// longer than the hex digests, such as SHA-256, quoted in ordinary text.
+const defaultBlobMinLength = 100
+
+// blobTruncateLength is the number of characters BlobTruncate keeps of a blob
+const blobTruncateLength = 32
+
+// blobFlag marks the chunks of blobs isolated by BlobIsolate in Metadata.Flags
+const blobFlag = "blob"
+
+// WithBlobs sets how runs of at least minLength base64 or hex characters, and data URIs of at
+// least that length, are handled. A minLength of 0 uses a default of 100; with BlobTruncate
+// it must be longer than the 32 characters kept of every blob. Removed and cut blob
+// characters do not appear in chunk texts, but chunk offsets still refer to the original text.
+func WithBlobs(mode BlobMode, minLength int) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.Blobs = mode
+		opts.BlobMinLength = minLength
+	}
+}
+
+// blobCandidateRegex matches data URIs and runs of base64 characters, which include hex
+var blobCandidateRegex = regexp.MustCompile(`data:[\w.+-]+/[\w.+-]+(?:;[\w.+-]+=[\w.+-]+)*;base64,[A-Za-z0-9+/]+=*|[A-Za-z0-9+/_-]{16,}=*`)
+
+// findBlobs returns the byte ranges of the blobs in text of at least minLength bytes
+func findBlobs(text string, minLength int) [][2]int {
+	if minLength <= 0 {
+		minLength = defaultBlobMinLength
+	}
+	blobs := make([][2]int, 0)
+	for _, match := range blobCandidateRegex.FindAllStringIndex(text, -1) {
+		if match[1]-match[0] >= minLength && isBlob(text[match[0]:match[1]]) {
+			blobs = append(blobs, [2]int{match[0], match[1]})
+		}
+	}
+	return blobs
+}
+
+// isBlob reports whether a match of blobCandidateRegex looks like encoded data rather than a
+// long word, identifier or path: a data URI, hex digits, or base64 mixing upper and lower
+// case letters and digits with few slashes
+func isBlob(s string) bool {
+	if strings.HasPrefix(s, "data:") {
+		return true
+	}
+	s = strings.TrimRight(s, "=")
+	hex, upper, lower, digit := true, false, false, false
+	for _, r := range s {
+		switch {
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsUpper(r):
+			upper = true
+			hex = hex && r <= 'F'
+		case unicode.IsLower(r):
+			lower = true
+			hex = hex && r <= 'f'
+		default:
+			hex = false
+		}
+	}
+	if hex && digit {
+		return true
+	}
+	return upper && lower && digit && strings.Count(s, "/") <= len(s)/16
+}
+
+// removedBlobRanges returns the byte ranges of text removed before splitting by BlobDrop and
+// BlobTruncate
+func (c *TextSplitter) removedBlobRanges(text string) [][2]int {
+	if c.opts.Blobs != BlobDrop && c.opts.Blobs != BlobTruncate {
+		return nil
+	}
+	blobs := findBlobs(text, c.opts.BlobMinLength)
+	if c.opts.Blobs != BlobTruncate {
+		return blobs
+	}
+	cut := blobs[:0]
+	for _, blob := range blobs {
+		// blobs no longer than what is kept of them are kept whole
+		if blob[1]-blob[0] > blobTruncateLength {
+			cut = append(cut, [2]int{blob[0] + blobTruncateLength, blob[1]})
+		}
+	}
+	return cut
+}
+
+// splitBlobs splits text around the blobs in it, leaving whitespace out of the splits
+func splitBlobs(text string, minLength int) (textSplit, bool) {
+	blobs := findBlobs(text, minLength)
+	if len(blobs) == 0 {
+		return textSplit{}, false
+	}
+	ts := textSplit{splitter: "", isWhitespace: true, level: LevelPattern, separate: true}
+	ts.addAround(text, blobs)
+	return ts, true
+}
+
+// isBlobChunk reports whether text is a blob, or a piece of one, isolated by BlobIsolate
+func isBlobChunk(text string) bool {
+	match := blobCandidateRegex.FindString(text)
+	return match != "" && match == text && isBlob(match)
+}
@@ -0,0 +1,86 @@
+package semchunk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testBlob = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg=="
+
+func TestFindBlobs(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		blob bool
+	}{
+		{"base64", "see " + testBlob + " here", true},
+		{"hex", "digest " + strings.Repeat("3f9a0c7e", 13) + ".", true},
+		{"data URI", `<img src="data:image/png;base64,` + testBlob + `">`, true},
+		{"path", "/usr/local/lib/python3/site-packages/some/deeply/nested/module/path/with/many/parts/init.py", false},
+		{"identifier", strings.Repeat("snake_case_name_", 8) + "v2", false},
+		{"short", "token aGVsbG8gd29ybGQ=", false},
+	}
+	for _, tt := range tests {
+		blobs := findBlobs(tt.text, 64)
+		assert.Equal(t, tt.blob, len(blobs) == 1, tt.name)
+	}
+
+	blobs := findBlobs(`src="data:image/png;base64,`+testBlob+`"`, 64)
+	assert.Equal(t, [][2]int{{5, 5 + len("data:image/png;base64,") + len(testBlob)}}, blobs)
+}
+
+func TestWithBlobs(t *testing.T) {
+	text := "The logo follows.\n\n" + testBlob + "\n\nThat was the logo."
+	after := strings.Index(text, "That")
+
+	t.Run("keep", func(t *testing.T) {
+		splitter := newWordSplitter(t, 20, 0, WithBlobs(BlobKeep, 64))
+		assert.Equal(t, []string{text}, splitter.Split(text))
+	})
+
+	t.Run("drop", func(t *testing.T) {
+		splitter := newWordSplitter(t, 20, 0, WithBlobs(BlobDrop, 64))
+		chunks := splitter.SplitChunks(text)
+		assert.Equal(t, 1, len(chunks))
+		assert.NotContains(t, chunks[0].Text, testBlob[:8])
+		assert.Equal(t, 0, chunks[0].Start)
+		assert.Equal(t, len(text), chunks[0].End)
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		splitter := newWordSplitter(t, 20, 0, WithBlobs(BlobTruncate, 64))
+		chunks := splitter.SplitChunks(text)
+		assert.Equal(t, 1, len(chunks))
+		assert.Contains(t, chunks[0].Text, "\n"+testBlob[:blobTruncateLength]+"\n")
+		assert.NotContains(t, chunks[0].Text, testBlob)
+
+		// blobs must be longer than what is kept of them
+		_, err := NewTextSplitter(20, 0, nil, WithSizeUnit(SizeRunes), WithBlobs(BlobTruncate, 20))
+		assert.ErrorContains(t, err, "minimum blob length must be greater than 32")
+		splitter.opts.BlobMinLength = 20
+		short := "0123456789abcdef0123"
+		assert.Empty(t, splitter.removedBlobRanges("see "+short))
+	})
+
+	t.Run("isolate", func(t *testing.T) {
+		splitter := newWordSplitter(t, 20, 0, WithBlobs(BlobIsolate, 64))
+		chunks := splitter.SplitChunks(text)
+		assert.Equal(t, []string{"The logo follows.", testBlob, "That was the logo."}, chunkTexts(chunks))
+		assert.Nil(t, chunks[0].Metadata.Flags)
+		assert.Equal(t, []string{"blob"}, chunks[1].Metadata.Flags)
+		assert.Equal(t, after, chunks[2].Start)
+
+		var streamed []Chunk
+		assert.NoError(t, splitter.SplitTo(text, ChunkSinkFunc(func(chunk Chunk) error {
+			streamed = append(streamed, chunk)
+			return nil
+		})))
+		assert.Equal(t, chunkTexts(chunks), chunkTexts(streamed))
+		assert.Equal(t, []string{"blob"}, streamed[1].Metadata.Flags)
+	})
+
+	_, err := NewTextSplitter(4, 0, func(string) int { return 1 }, WithBlobs(BlobMode(9), 0))
+	assert.Error(t, err)
+}
@@ -0,0 +1,116 @@
+package semchunk
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LevelBoundary is the level of splits made at boundaries found by a BoundaryDetector
+const LevelBoundary SplitLevel = "boundary"
+
+// Boundary is a candidate chunk boundary: a byte offset in the text between two pieces,
+// with a score expressing how good a place to split it is
+type Boundary struct {
+	Offset int
+	Score  float64
+}
+
+// BoundaryDetector finds candidate boundaries in a text, for example with a statistical
+// sentence segmenter or rules specific to a domain. Offsets are relative to the given text,
+// which may be any piece of the document the splitter is working on.
+type BoundaryDetector interface {
+	Boundaries(text string) []Boundary
+}
+
+// BoundaryDetectorFunc adapts an ordinary function to the BoundaryDetector interface
+type BoundaryDetectorFunc func(text string) []Boundary
+
+func (f BoundaryDetectorFunc) Boundaries(text string) []Boundary {
+	return f(text)
+}
+
+// WithBoundaryDetector adds a separator level that splits at the boundaries found by detector
+// with a score of at least minScore. It is tried after page markers, line breaks, tabs and
+// preserve patterns, and before punctuation and whitespace, which still apply to pieces
+// that are too large. Boundaries inside a UTF-8 sequence are ignored. The scores of the
+// boundaries are handed to the Merger if it is a ScoredMerger, as the default one is.
+func WithBoundaryDetector(detector BoundaryDetector, minScore float64) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.BoundaryDetector = detector
+		opts.BoundaryMinScore = minScore
+	}
+}
+
+// ScoredMerger is a Merger that also takes the scores of the boundaries between the splits
+// when the splits were made at the boundaries of a BoundaryDetector, so it can prefer to end
+// chunks at the better ones. scores[i] is the score of the boundary before split i; scores[0]
+// is unused.
+type ScoredMerger interface {
+	Merger
+	MergeScored(splitSizes []int, scores []float64, separatorSize int, chunkSize int, overlap func(size int) int) [][2]int
+}
+
+// detectBoundaries splits text at the detected boundaries. Whitespace around a boundary
+// is left out of the pieces.
+func (r splitRules) detectBoundaries(text string) (textSplit, bool) {
+	if r.detector == nil {
+		return textSplit{}, false
+	}
+	boundaries := make([]Boundary, 0)
+	for _, boundary := range r.detector.Boundaries(text) {
+		if boundary.Score >= r.minScore && boundary.Offset > 0 && boundary.Offset < len(text) && utf8.RuneStart(text[boundary.Offset]) {
+			boundaries = append(boundaries, boundary)
+		}
+	}
+	sort.Slice(boundaries, func(i, j int) bool { return boundaries[i].Offset < boundaries[j].Offset })
+	offsets := make([]int, len(boundaries))
+	for i, boundary := range boundaries {
+		offsets[i] = boundary.Offset
+	}
+
+	ts, ok := splitAtOffsets(text, offsets, LevelBoundary)
+	if !ok {
+		return ts, false
+	}
+	// a split scores as the best boundary between it and the previous split
+	ts.scores = make([]float64, len(ts.splits))
+	b := 0
+	for i := 1; i < len(ts.splits); i++ {
+		for ; b < len(boundaries) && boundaries[b].Offset <= ts.starts[i]; b++ {
+			if boundaries[b].Offset > ts.starts[i-1] && boundaries[b].Score > ts.scores[i] {
+				ts.scores[i] = boundaries[b].Score
+			}
+		}
+	}
+	return ts, true
+}
+
+// splitAtOffsets splits text at the sorted offsets, trimming whitespace from the pieces and
+// dropping empty ones. It reports false if fewer than two pieces remain.
+func splitAtOffsets(text string, offsets []int, level SplitLevel) (textSplit, bool) {
+	ts := textSplit{splitter: "", isWhitespace: true, level: level}
+	last := 0
+	for _, offset := range append(offsets, len(text)) {
+		if offset <= last {
+			continue
+		}
+		piece := text[last:offset]
+		trimmed := strings.TrimLeftFunc(piece, unicode.IsSpace)
+		start := last + len(piece) - len(trimmed)
+		trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+		if trimmed != "" {
+			ts.splits = append(ts.splits, trimmed)
+			ts.starts = append(ts.starts, start)
+		}
+		last = offset
+	}
+	if len(ts.splits) < 2 {
+		return textSplit{}, false
+	}
+	return ts, true
+}
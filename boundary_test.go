@@ -0,0 +1,73 @@
+package semchunk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoundaryDetector(t *testing.T) {
+	// a detector that knows items are separated by " / " and weakly suggests "and"
+	detector := BoundaryDetectorFunc(func(text string) []Boundary {
+		boundaries := make([]Boundary, 0)
+		for i := 0; i < len(text); i++ {
+			if strings.HasPrefix(text[i:], " / ") {
+				boundaries = append(boundaries, Boundary{Offset: i, Score: 0.9})
+			}
+			if strings.HasPrefix(text[i:], " and ") {
+				boundaries = append(boundaries, Boundary{Offset: i, Score: 0.1})
+			}
+		}
+		return boundaries
+	})
+	text := "red apples and pears / green grapes / yellow bananas and lemons"
+
+	chunks := newWordSplitter(t, 5, 0, WithBoundaryDetector(detector, 0.5)).SplitChunks(text)
+	assert.Equal(t, []string{"red apples and pears", "/ green grapes", "/ yellow bananas and lemons"}, chunkTexts(chunks))
+	assert.Equal(t, LevelBoundary, chunks[0].Metadata.Level)
+	for _, chunk := range chunks {
+		assert.Equal(t, chunk.Text, text[chunk.Start:chunk.End])
+	}
+}
+
+func TestBoundaryDetectorInsideRune(t *testing.T) {
+	// every byte offset, most of them inside a two byte rune
+	detector := BoundaryDetectorFunc(func(text string) []Boundary {
+		boundaries := make([]Boundary, 0)
+		for i := 1; i < len(text); i++ {
+			boundaries = append(boundaries, Boundary{Offset: i, Score: 1})
+		}
+		return boundaries
+	})
+	splitter, err := NewTextSplitter(1, 0, nil, WithSizeUnit(SizeRunes), WithBoundaryDetector(detector, 0.5))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"é", "é", "é"}, splitter.Split("ééé"))
+}
+
+func TestBoundaryScores(t *testing.T) {
+	// strong boundaries between sentences, weak ones between the words of a sentence
+	detector := BoundaryDetectorFunc(func(text string) []Boundary {
+		boundaries := make([]Boundary, 0)
+		for i := 1; i < len(text); i++ {
+			if text[i-1] == ' ' {
+				score := 0.2
+				if text[i-2] == '.' {
+					score = 0.9
+				}
+				boundaries = append(boundaries, Boundary{Offset: i, Score: score})
+			}
+		}
+		return boundaries
+	})
+	text := "a b c. d e f g. h i"
+
+	// a merger that ignores the scores cuts at weak boundaries
+	plain := newWordSplitter(t, 5, 0, WithBoundaryDetector(detector, 0.1), WithMerger(BalancedMerger{}))
+	assert.Equal(t, []string{"a b c. d e", "f g. h i"}, plain.Split(text))
+
+	scored := newWordSplitter(t, 5, 0, WithBoundaryDetector(detector, 0.1))
+	chunks := scored.SplitChunks(text)
+	assert.Equal(t, []string{"a b c.", "d e f g.", "h i"}, chunkTexts(chunks))
+	assert.Equal(t, LevelBoundary, chunks[0].Metadata.Level)
+}
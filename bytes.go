@@ -0,0 +1,178 @@
+package semchunk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+	"unsafe"
+)
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// SplitBytes splits the contents of a file, such as data read or memory-mapped by the files
+// package. The encoding is detected from the byte order mark: UTF-8 and UTF-16 are supported,
+// and data without a BOM that is not valid UTF-8 is read as Latin-1. Chunk offsets are byte
+// offsets in data, not in the decoded text, and chunks do not refer to data, so it may be
+// released after.
+func (c *TextSplitter) SplitBytes(data []byte) ([]Chunk, error) {
+	chunks := make([]Chunk, 0)
+	err := c.splitBytes(data, func(text string, sink ChunkSink) error {
+		split, err := c.splitChunksAnnotated(context.Background(), text)
+		if err != nil {
+			return err
+		}
+		for _, chunk := range split {
+			if err := sink.Write(chunk); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, ChunkSinkFunc(func(chunk Chunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+// SplitBytesTo splits data like SplitBytes, but writes the chunks to sink as they are
+// produced, like SplitTo
+func (c *TextSplitter) SplitBytesTo(data []byte, sink ChunkSink) error {
+	return c.splitBytes(data, c.SplitTo, sink)
+}
+
+// splitBytes decodes data and splits its text with split. Every chunk is moved to offsets in
+// data and copied off data before it is written to sink.
+func (c *TextSplitter) splitBytes(data []byte, split func(text string, sink ChunkSink) error, sink ChunkSink) error {
+	text, positions, err := decodeFile(data)
+	if err != nil {
+		return err
+	}
+	return split(text, ChunkSinkFunc(func(chunk Chunk) error {
+		chunk = cloneChunk(chunk)
+		remapChunk(&chunk, positions)
+		return sink.Write(chunk)
+	}))
+}
+
+// cloneChunk returns chunk with copies of all strings that may point into the text it was
+// split from
+func cloneChunk(chunk Chunk) Chunk {
+	chunk.Text = strings.Clone(chunk.Text)
+	m := &chunk.Metadata
+	m.Context = strings.Clone(m.Context)
+	m.Summary = strings.Clone(m.Summary)
+	m.DocumentID = strings.Clone(m.DocumentID)
+	m.ContextPrefix = strings.Clone(m.ContextPrefix)
+	m.HeadingPath = cloneStrings(m.HeadingPath)
+	m.Speakers = cloneStrings(m.Speakers)
+	m.Flags = cloneStrings(m.Flags)
+	if m.Footnotes != nil {
+		footnotes := make([]Footnote, len(m.Footnotes))
+		for i, footnote := range m.Footnotes {
+			footnotes[i] = Footnote{Label: strings.Clone(footnote.Label), Text: strings.Clone(footnote.Text)}
+		}
+		m.Footnotes = footnotes
+	}
+	return chunk
+}
+
+// cloneStrings returns a copy of strs holding copies of its strings
+func cloneStrings(strs []string) []string {
+	if strs == nil {
+		return nil
+	}
+	clones := make([]string, len(strs))
+	for i, s := range strs {
+		clones[i] = strings.Clone(s)
+	}
+	return clones
+}
+
+// decodeFile converts data to UTF-8 text and returns a function mapping byte offsets in the
+// text back to byte offsets in data. Valid UTF-8 is not copied.
+func decodeFile(data []byte) (string, func(int) int, error) {
+	switch {
+	case bytes.HasPrefix(data, utf8BOM):
+		data = data[len(utf8BOM):]
+		if !utf8.Valid(data) {
+			return "", nil, fmt.Errorf("invalid UTF-8")
+		}
+		return bytesToString(data), func(i int) int { return i + len(utf8BOM) }, nil
+	case bytes.HasPrefix(data, utf16LEBOM):
+		return decodeUTF16(data, false)
+	case bytes.HasPrefix(data, utf16BEBOM):
+		return decodeUTF16(data, true)
+	case utf8.Valid(data):
+		return bytesToString(data), func(i int) int { return i }, nil
+	default:
+		return decodeLatin1(data)
+	}
+}
+
+func decodeUTF16(data []byte, bigEndian bool) (string, func(int) int, error) {
+	if len(data)%2 != 0 {
+		return "", nil, fmt.Errorf("truncated UTF-16")
+	}
+	units := make([]uint16, 0, len(data)/2-1)
+	for i := 2; i < len(data); i += 2 {
+		if bigEndian {
+			units = append(units, uint16(data[i])<<8|uint16(data[i+1]))
+		} else {
+			units = append(units, uint16(data[i+1])<<8|uint16(data[i]))
+		}
+	}
+
+	var b strings.Builder
+	// positions[i] is the file offset of the code unit that produced text byte i
+	positions := make([]int, 0, len(units)*3/2+1)
+	for i := 0; i < len(units); {
+		n, r := 1, rune(units[i])
+		if utf16.IsSurrogate(r) {
+			r = unicode.ReplacementChar
+			if i+1 < len(units) {
+				// a lone surrogate is replaced, the code unit after it is kept
+				if pair := utf16.DecodeRune(rune(units[i]), rune(units[i+1])); pair != unicode.ReplacementChar {
+					n, r = 2, pair
+				}
+			}
+		}
+		size, _ := b.WriteRune(r)
+		for j := 0; j < size; j++ {
+			positions = append(positions, 2+2*i)
+		}
+		i += n
+	}
+	positions = append(positions, len(data))
+	return b.String(), func(i int) int { return positions[i] }, nil
+}
+
+func decodeLatin1(data []byte) (string, func(int) int, error) {
+	var b strings.Builder
+	positions := make([]int, 0, len(data)+1)
+	for i, c := range data {
+		size, _ := b.WriteRune(rune(c))
+		for j := 0; j < size; j++ {
+			positions = append(positions, i)
+		}
+	}
+	positions = append(positions, len(data))
+	return b.String(), func(i int) int { return positions[i] }, nil
+}
+
+func bytesToString(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	return unsafe.String(&data[0], len(data))
+}
@@ -0,0 +1,81 @@
+package semchunk
+
+import (
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitBytes(t *testing.T) {
+	text := "Apples are red. Bananas are yellow. Cherries are dark."
+	expected := newWordSplitter(t, 3, 0).SplitChunks(text)
+
+	encodeUTF16LE := func(s string) []byte {
+		b := []byte{0xFF, 0xFE}
+		for _, u := range utf16.Encode([]rune(s)) {
+			b = append(b, byte(u), byte(u>>8))
+		}
+		return b
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		offsets func(int) int
+	}{
+		{"utf-8", []byte(text), func(i int) int { return i }},
+		{"utf-8 bom", append([]byte{0xEF, 0xBB, 0xBF}, text...), func(i int) int { return i + 3 }},
+		{"utf-16le", encodeUTF16LE(text), func(i int) int { return 2 + 2*i }},
+	}
+	for _, tt := range tests {
+		chunks, err := newWordSplitter(t, 3, 0).SplitBytes(tt.data)
+		assert.NoError(t, err, tt.name)
+		assert.Equal(t, len(expected), len(chunks), tt.name)
+		for i := range chunks {
+			assert.Equal(t, expected[i].Text, chunks[i].Text, tt.name)
+			assert.Equal(t, tt.offsets(expected[i].Start), chunks[i].Start, tt.name)
+			assert.Equal(t, tt.offsets(expected[i].End), chunks[i].End, tt.name)
+		}
+	}
+}
+
+func TestSplitBytesTo(t *testing.T) {
+	text := "Apples are red. Bananas are yellow. Cherries are dark red."
+	data := append([]byte{0xEF, 0xBB, 0xBF}, text...)
+
+	// flags are substrings of the text and must not refer to data
+	splitter := newWordSplitter(t, 3, 0, WithDenylist(DenylistMark, "red"))
+	expected, err := splitter.SplitBytes(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"red"}, expected[0].Metadata.Flags)
+
+	var chunks []Chunk
+	assert.NoError(t, splitter.SplitBytesTo(data, ChunkSinkFunc(func(chunk Chunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})))
+	for i := range data {
+		data[i] = 'x'
+	}
+	assert.Equal(t, chunkTexts(expected), chunkTexts(chunks))
+	last := chunks[len(chunks)-1]
+	assert.Equal(t, expected[len(expected)-1].Start, last.Start)
+	assert.Equal(t, []string{"red"}, last.Metadata.Flags)
+}
+
+func TestDecodeUTF16LoneSurrogate(t *testing.T) {
+	// a high surrogate followed by "a" instead of a low surrogate
+	text, positions, err := decodeFile([]byte{0xFF, 0xFE, 0x3D, 0xD8, 'a', 0, 'b', 0})
+	assert.NoError(t, err)
+	assert.Equal(t, "\ufffdab", text)
+	assert.Equal(t, 4, positions(len("\ufffd")))
+}
+
+func TestDecodeFileLatin1(t *testing.T) {
+	text, positions, err := decodeFile([]byte("caf\xe9 au lait"))
+	assert.NoError(t, err)
+	assert.Equal(t, "café au lait", text)
+	assert.Equal(t, 4, positions(len("café")))
+	assert.Equal(t, 12, positions(len(text)))
+}
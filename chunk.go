@@ -0,0 +1,185 @@
+package semchunk
+
+import "context"
+
+// Chunk is a piece of the input text together with its position and metadata
+type Chunk struct {
+	// Index is the position of the chunk in the output
+	Index int `json:"index"`
+	// Seq numbers the chunks delivered by SplitTo, SplitChan and SplitAllTo from 1 in delivery
+	// order, across all texts of the stream. It is 0 for chunks that were not streamed.
+	Seq  int64  `json:"seq,omitempty"`
+	Text string `json:"text"`
+	// Start and End are the byte offsets of the chunk in the input text
+	Start  int `json:"start"`
+	End    int `json:"end"`
+	Tokens int `json:"tokens"`
+
+	Metadata Metadata `json:"metadata"`
+}
+
+// Metadata holds information about a chunk computed while splitting
+type Metadata struct {
+	// NewTokens is the number of tokens not shared with the previous chunk through overlap,
+	// so summing it over all chunks does not count overlap regions twice
+	NewTokens int `json:"new_tokens"`
+	// OverlapBytes and OverlapTokens measure the part of the chunk repeated from the previous
+	// chunk. OverlapBytes counts bytes of the input text, so text[Start+OverlapBytes:End] is
+	// the chunk without the overlap; Text may differ from that span once a context prefix,
+	// table header or suffix was added or the chunk was redacted.
+	OverlapBytes  int `json:"overlap_bytes,omitempty"`
+	OverlapTokens int `json:"overlap_tokens,omitempty"`
+	// Level is the separator level at which the chunk's boundaries were produced
+	Level SplitLevel `json:"level"`
+	// Depth is the recursion depth at which the chunk was produced, 0 being the whole text
+	Depth int `json:"depth"`
+	// Quality is the heuristic ChunkQuality score of the chunk
+	Quality float64 `json:"quality"`
+	// Context is the text surrounding the chunk, see SplitSentenceWindows
+	Context string `json:"context,omitempty"`
+	// Summary is the text generated for the chunk by the ChunkAnnotator
+	Summary string `json:"summary,omitempty"`
+	// DocumentID is the ID of the Document the chunk belongs to
+	DocumentID string `json:"document_id,omitempty"`
+	// ContextPrefix is the text situating the chunk: the text generated by the
+	// ContextGenerator or the header of a ContextualSplitter, followed by the repeated header
+	// of the table the chunk is in, see WithNumericTables, separated by a blank line
+	ContextPrefix string `json:"context_prefix,omitempty"`
+	// Oversized is set on preserved matches and pieces kept whole by WithStopDescent that
+	// exceed the chunk size
+	Oversized bool `json:"oversized,omitempty"`
+	// HeadingPath holds the headings of the section the chunk is in, outermost first
+	HeadingPath []string `json:"heading_path,omitempty"`
+	// Footnotes are the footnotes referenced in the chunk, with their definitions
+	Footnotes []Footnote `json:"footnotes,omitempty"`
+	// Page and PageEnd are the first and last page the chunk is on, see WithPageMarkers
+	Page    int `json:"page,omitempty"`
+	PageEnd int `json:"page_end,omitempty"`
+	// Line and LineEnd are the first and last line of the chunk in the input text, from 1
+	Line    int `json:"line,omitempty"`
+	LineEnd int `json:"line_end,omitempty"`
+	// StartTime and EndTime are the times in seconds at which the speech in the chunk starts
+	// and ends, and Speakers are its speakers, see SplitTranscript
+	StartTime float64  `json:"start_time,omitempty"`
+	EndTime   float64  `json:"end_time,omitempty"`
+	Speakers  []string `json:"speakers,omitempty"`
+	// TokenStart and TokenEnd are the range of the chunk in the tokens of the whole text, see
+	// SplitTokenRanges
+	TokenStart int `json:"token_start,omitempty"`
+	TokenEnd   int `json:"token_end,omitempty"`
+	// PII lists the categories of personal data in the chunk, see WithPIIDetection
+	PII []PIICategory `json:"pii,omitempty"`
+	// Flags are the texts in the chunk matching the denylist, lower-cased, see WithDenylist
+	Flags []string `json:"flags,omitempty"`
+	// TOC is set on chunks that look like a table of contents, see WithTOCDetection
+	TOC bool `json:"toc,omitempty"`
+	// Highlights are the [start, end) byte ranges in the input text of the matches the chunk
+	// was extracted for, see SplitHighlights
+	Highlights [][2]int `json:"highlights,omitempty"`
+	// Score is the relevance a retriever assigned to the chunk, see AssembleContext
+	Score float64 `json:"score,omitempty"`
+}
+
+// SplitChunks splits text like Split, but returns chunks with their offsets and metadata
+func (c *TextSplitter) SplitChunks(text string) []Chunk {
+	chunks, _ := c.splitChunksAnnotated(context.Background(), text)
+	return chunks
+}
+
+// SplitSpans splits text like Split, but returns only the [start, end) byte ranges of the
+// chunks in text. No metadata is computed and no chunk text is kept.
+func (c *TextSplitter) SplitSpans(text string) [][2]int {
+	chunks, err := c.splitChunksContext(context.Background(), text, false)
+	if err != nil {
+		return nil
+	}
+	spans := make([][2]int, len(chunks))
+	for i, chunk := range chunks {
+		spans[i] = [2]int{chunk.Start, chunk.End}
+	}
+	return spans
+}
+
+func (c *TextSplitter) splitChunksAnnotated(ctx context.Context, text string) ([]Chunk, error) {
+	return c.splitChunksContext(ctx, text, true)
+}
+
+// annotate fills in the index, token counts and metadata of chunks
+func (c *TextSplitter) annotate(chunks []Chunk) {
+	c.annotateFrom(chunks, 0, 0)
+}
+
+// annotateFrom annotates chunks that follow index earlier chunks ending at most at prevEnd,
+// and returns the new prevEnd, so a stream of chunks can be annotated batch by batch
+func (c *TextSplitter) annotateFrom(chunks []Chunk, index int, prevEnd int) int {
+	texts := make([]string, len(chunks))
+	newTexts := make([]string, 0)
+	overlapping := make([]int, 0)
+	overlaps := make([]int, 0)
+
+	for i, chunk := range chunks {
+		texts[i] = chunk.Text
+		if (index > 0 || i > 0) && chunk.Start < prevEnd {
+			overlapBytes := prevEnd - chunk.Start
+			// a repeated table header before the span is repeated text as well
+			skip := len(chunk.Text) - (chunk.End - chunk.Start)
+			if skip < 0 || chunk.Metadata.ContextPrefix == "" {
+				skip = 0
+			}
+			if skip+overlapBytes > len(chunk.Text) {
+				overlapBytes = len(chunk.Text) - skip
+			}
+			overlapping = append(overlapping, i)
+			overlaps = append(overlaps, overlapBytes)
+			newTexts = append(newTexts, chunk.Text[skip+overlapBytes:])
+		}
+		if chunk.End > prevEnd {
+			prevEnd = chunk.End
+		}
+	}
+
+	counts := c.countTokensBatch(texts)
+	for i := range chunks {
+		chunks[i].Index = index + i
+		chunks[i].Tokens = counts[i]
+		chunks[i].Metadata.NewTokens = counts[i]
+		chunks[i].Metadata.Quality = ChunkQuality(chunks[i].Text)
+		if c.opts != nil && c.opts.TOC == TOCMark {
+			chunks[i].Metadata.TOC = IsTOC(chunks[i].Text)
+		}
+		if c.opts != nil && c.opts.DenylistMode == DenylistMark && len(c.opts.Denylist) > 0 {
+			chunks[i].Metadata.Flags = c.denylistMatches(chunks[i].Text)
+		}
+		if c.opts != nil && c.opts.Blobs == BlobIsolate && isBlobChunk(chunks[i].Text) {
+			chunks[i].Metadata.Flags = append(chunks[i].Metadata.Flags, blobFlag)
+		}
+	}
+
+	newCounts := c.countTokensBatch(newTexts)
+	for j, i := range overlapping {
+		chunks[i].Metadata.NewTokens = newCounts[j]
+		chunks[i].Metadata.OverlapBytes = overlaps[j]
+		if overlap := chunks[i].Tokens - newCounts[j]; overlap > 0 {
+			chunks[i].Metadata.OverlapTokens = overlap
+		}
+	}
+	return prevEnd
+}
+
+// addContextPrefix adds prefix in front of the context prefix of chunk, which situates the
+// chunk in something larger than what the present prefix does
+func addContextPrefix(chunk *Chunk, prefix string) {
+	if chunk.Metadata.ContextPrefix == "" || prefix == "" {
+		chunk.Metadata.ContextPrefix += prefix
+		return
+	}
+	chunk.Metadata.ContextPrefix = prefix + "\n\n" + chunk.Metadata.ContextPrefix
+}
+
+func chunkTexts(chunks []Chunk) []string {
+	texts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		texts[i] = chunk.Text
+	}
+	return texts
+}
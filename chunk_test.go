@@ -0,0 +1,104 @@
+package semchunk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newWordSplitter(t *testing.T, chunkSize int, overlap int, opts ...func(*TextSplitterOption)) *TextSplitter {
+	t.Helper()
+	splitter, err := NewTextSplitter(chunkSize, overlap, func(text string) int {
+		return len(strings.Fields(text))
+	}, opts...)
+	assert.NoError(t, err)
+	return splitter
+}
+
+func TestSplitChunks(t *testing.T) {
+	text := "This is a test sentence. This is another test sentence."
+	splitter := newWordSplitter(t, 3, 1)
+
+	chunks := splitter.SplitChunks(text)
+	assert.Equal(t, splitter.Split(text), chunkTexts(chunks))
+
+	newTokens := 0
+	for i, chunk := range chunks {
+		assert.Equal(t, i, chunk.Index)
+		assert.Equal(t, text[chunk.Start:chunk.End], chunk.Text)
+		assert.Equal(t, len(strings.Fields(chunk.Text)), chunk.Tokens)
+		newTokens += chunk.Metadata.NewTokens
+	}
+	assert.Equal(t, []string{"This is a", "a test sentence.", "This is another", "another test sentence."}, chunkTexts(chunks))
+	assert.Equal(t, []int{3, 2, 3, 2}, []int{
+		chunks[0].Metadata.NewTokens, chunks[1].Metadata.NewTokens,
+		chunks[2].Metadata.NewTokens, chunks[3].Metadata.NewTokens,
+	})
+	assert.Equal(t, len(strings.Fields(text)), newTokens)
+
+	assert.Equal(t, []int{0, 1, 0, 7}, []int{
+		chunks[0].Metadata.OverlapBytes, chunks[1].Metadata.OverlapBytes,
+		chunks[2].Metadata.OverlapBytes, chunks[3].Metadata.OverlapBytes,
+	})
+	for _, chunk := range chunks {
+		assert.Equal(t, chunk.Tokens, chunk.Metadata.NewTokens+chunk.Metadata.OverlapTokens)
+	}
+	assert.Equal(t, " test sentence.", chunks[1].Text[chunks[1].Metadata.OverlapBytes:])
+}
+
+func TestSplitChunksLevelAndDepth(t *testing.T) {
+	text := "First paragraph is here. It has two sentences.\n\nSecond one."
+	splitter := newWordSplitter(t, 5, 0)
+
+	chunks := splitter.SplitChunks(text)
+	assert.Equal(t, []string{"First paragraph is here.", "It has two sentences.", "Second one."}, chunkTexts(chunks))
+	assert.Equal(t, LevelSentence, chunks[0].Metadata.Level)
+	assert.Equal(t, 1, chunks[0].Metadata.Depth)
+	assert.Equal(t, LevelParagraph, chunks[2].Metadata.Level)
+	assert.Equal(t, 0, chunks[2].Metadata.Depth)
+}
+
+func TestSplitSentenceWindows(t *testing.T) {
+	text := "One is first. Two follows! Three is here?\n\nFour ends it."
+	splitter := newWordSplitter(t, 10, 0)
+
+	chunks := splitter.SplitSentenceWindows(text, 1)
+	assert.Equal(t, []string{"One is first.", "Two follows!", "Three is here?", "Four ends it."}, chunkTexts(chunks))
+	assert.Equal(t, "One is first. Two follows!", chunks[0].Metadata.Context)
+	assert.Equal(t, "Two follows! Three is here?\n\nFour ends it.", chunks[2].Metadata.Context)
+	assert.Equal(t, "Three is here?\n\nFour ends it.", chunks[3].Metadata.Context)
+	for _, chunk := range chunks {
+		assert.Equal(t, text[chunk.Start:chunk.End], chunk.Text)
+	}
+
+	// a negative window is no window
+	chunks = splitter.SplitSentenceWindows(text, -2)
+	assert.Len(t, chunks, 4)
+	for _, chunk := range chunks {
+		assert.Equal(t, chunk.Text, chunk.Metadata.Context)
+	}
+}
+
+func TestRelativeOverlap(t *testing.T) {
+	// the first window is short because the fourth split does not fit next to it
+	sizes := []int{1, 1, 1, 8}
+
+	absolute := newWordSplitter(t, 10, 5)
+	assert.Equal(t, [][2]int{{0, 3}, {1, 4}}, absolute.mergeWindows(sizes, 0, 10, absolute.overlapFor))
+
+	relative := newWordSplitter(t, 10, 5, WithRelativeOverlap(0.5))
+	assert.Equal(t, [][2]int{{0, 3}, {2, 4}}, relative.mergeWindows(sizes, 0, 10, relative.overlapFor))
+}
+
+func TestSplitSpans(t *testing.T) {
+	text := "Apples are red. Bananas are yellow.\n\nCherries are dark."
+	splitter := newWordSplitter(t, 3, 0)
+
+	spans := splitter.SplitSpans(text)
+	texts := make([]string, len(spans))
+	for i, span := range spans {
+		texts[i] = text[span[0]:span[1]]
+	}
+	assert.Equal(t, splitter.Split(text), texts)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/sanbaiw/semtxtsplitter/eval"
+)
+
+// runAnalyze implements the analyze subcommand, which reports how many query spans of a test
+// set land within a single chunk, for one configuration or with -sweep for a grid of them
+func runAnalyze(args []string, countTokens func(string) int) error {
+	fs := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	corpus := fs.String("corpus", "", "JSONL file of documents {\"id\", \"text\"}, or a directory of text files")
+	queries := fs.String("queries", "", "JSONL file of queries {\"query\", \"document_id\", \"start\", \"end\"} or with \"text\" instead of offsets")
+	chunkSize := fs.Int("chunk-size", 100, "Maximum number of tokens per chunk")
+	overlap := fs.Float64("overlap", 0, "Overlap ratio between chunks (0-1)")
+	sweep := fs.Bool("sweep", false, "Evaluate every combination of -sizes and -overlaps")
+	sizes := fs.String("sizes", "64,128,256,512", "Comma-separated chunk sizes for -sweep")
+	overlaps := fs.String("overlaps", "0,0.1,0.2", "Comma-separated overlap ratios for -sweep")
+	output := fs.String("output", "text", "Output format: text or jsonl")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *corpus == "" || *queries == "" {
+		return fmt.Errorf("analyze needs -corpus and -queries")
+	}
+
+	docs, err := readCorpus(*corpus)
+	if err != nil {
+		return err
+	}
+	var qs []eval.Query
+	if err := readJSONL(*queries, &qs); err != nil {
+		return err
+	}
+
+	settings := []eval.Setting{{ChunkSize: *chunkSize, Overlap: float32(*overlap)}}
+	if *sweep {
+		var sizeList []int
+		for _, s := range strings.Split(*sizes, ",") {
+			size, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				return fmt.Errorf("invalid size %q", s)
+			}
+			sizeList = append(sizeList, size)
+		}
+		var overlapList []float32
+		for _, s := range strings.Split(*overlaps, ",") {
+			o, err := strconv.ParseFloat(strings.TrimSpace(s), 32)
+			if err != nil {
+				return fmt.Errorf("invalid overlap %q", s)
+			}
+			overlapList = append(overlapList, float32(o))
+		}
+		settings = eval.Grid(sizeList, overlapList)
+	}
+
+	results, err := eval.Evaluate(context.Background(), docs, qs, countTokens, settings)
+	if err != nil {
+		return err
+	}
+	return writeResults(os.Stdout, results, *output)
+}
+
+// writeResults prints evaluation results as a table or as JSON lines
+func writeResults(w io.Writer, results []eval.Result, output string) error {
+	bw := bufio.NewWriter(w)
+	if output == "jsonl" {
+		enc := json.NewEncoder(bw)
+		for _, result := range results {
+			if err := enc.Encode(result); err != nil {
+				return err
+			}
+		}
+		return bw.Flush()
+	}
+	fmt.Fprintf(bw, "%10s %8s %8s %10s %10s %10s\n", "chunk_size", "overlap", "chunks", "coverage", "fragments", "tokens")
+	for _, r := range results {
+		fmt.Fprintf(bw, "%10d %8.2f %8d %9.1f%% %10.2f %10.1f\n", r.ChunkSize, r.Overlap, r.Chunks, r.Coverage*100, r.Fragments, r.MeanTokens)
+	}
+	return bw.Flush()
+}
+
+// readCorpus reads documents from a JSONL file or from the regular files of a directory,
+// which are identified by their path relative to it
+func readCorpus(path string) ([]eval.Document, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	var docs []eval.Document
+	if !info.IsDir() {
+		err := readJSONL(path, &docs)
+		return docs, err
+	}
+	err = filepath.WalkDir(path, func(name string, d os.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		content, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		id, err := filepath.Rel(path, name)
+		if err != nil {
+			return err
+		}
+		docs = append(docs, eval.Document{ID: filepath.ToSlash(id), Text: string(content)})
+		return nil
+	})
+	return docs, err
+}
+
+// readJSONL decodes one JSON value per line of a file into the slice pointed to by v
+func readJSONL[T any](path string, v *[]T) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	dec := json.NewDecoder(f)
+	for dec.More() {
+		var item T
+		if err := dec.Decode(&item); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		*v = append(*v, item)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	semchunk "github.com/sanbaiw/semtxtsplitter"
+	"github.com/sanbaiw/semtxtsplitter/files"
+)
+
+// goldenPath returns the golden file of the document id in dir, named after the base name of
+// id and a hash of its path, so documents with the same name in different directories do not
+// share a golden file
+func goldenPath(dir string, id string) string {
+	sum := sha256.Sum256([]byte(filepath.ToSlash(filepath.Clean(id))))
+	return filepath.Join(dir, fmt.Sprintf("%s-%x.jsonl", filepath.Base(id), sum[:4]))
+}
+
+// checkGolden compares chunks with the golden file of the document id in dir and reports the
+// drift to w. It writes the golden file instead if there is none or update is set. It returns
+// whether the chunks drifted.
+func checkGolden(w io.Writer, dir string, id string, chunks []semchunk.Chunk, update bool) (bool, error) {
+	path := goldenPath(dir, id)
+	drift, err := files.CompareGolden(chunks, path)
+	if update || errors.Is(err, os.ErrNotExist) {
+		if err := files.WriteGolden(path, chunks); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(w, "Wrote %d golden chunks to %s\n", len(chunks), path)
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if !drift.Drifted() {
+		fmt.Fprintf(w, "No drift from %s (%d chunks)\n", path, len(chunks))
+		return false, nil
+	}
+	fmt.Fprintf(w, "Drift from %s: %s\n", path, drift)
+	for _, chunk := range drift.Removed {
+		fmt.Fprintf(w, "- [%d, %d) %s\n", chunk.Start, chunk.End, strings.ReplaceAll(chunk.Text, "\n", `\n`))
+	}
+	for _, chunk := range drift.Added {
+		fmt.Fprintf(w, "+ [%d, %d) %s\n", chunk.Start, chunk.End, strings.ReplaceAll(chunk.Text, "\n", `\n`))
+	}
+	return true, nil
+}
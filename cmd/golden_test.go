@@ -0,0 +1,49 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	semchunk "github.com/sanbaiw/semtxtsplitter"
+	"github.com/sanbaiw/semtxtsplitter/files"
+)
+
+func TestCheckGolden(t *testing.T) {
+	dir := t.TempDir()
+	chunks := []semchunk.Chunk{{Text: "one two", Start: 0, End: 7}, {Text: "three", Start: 8, End: 13}}
+
+	var b strings.Builder
+	drifted, err := checkGolden(&b, dir, "docs/a.txt", chunks, false)
+	assert.NoError(t, err)
+	assert.False(t, drifted)
+	assert.Equal(t, "Wrote 2 golden chunks to "+goldenPath(dir, "docs/a.txt")+"\n", b.String())
+	assert.Equal(t, goldenPath(dir, "docs/a.txt"), goldenPath(dir, "./docs//a.txt"))
+	assert.NotEqual(t, goldenPath(dir, "docs/a.txt"), goldenPath(dir, "notes/a.txt"))
+	assert.True(t, strings.HasPrefix(filepath.Base(goldenPath(dir, "docs/a.txt")), "a.txt-"))
+
+	b.Reset()
+	drifted, err = checkGolden(&b, dir, "docs/a.txt", chunks, false)
+	assert.NoError(t, err)
+	assert.False(t, drifted)
+	assert.Contains(t, b.String(), "No drift")
+
+	b.Reset()
+	moved := []semchunk.Chunk{{Text: "one", Start: 0, End: 3}, {Text: "two three", Start: 4, End: 13}}
+	drifted, err = checkGolden(&b, dir, "docs/a.txt", moved, false)
+	assert.NoError(t, err)
+	assert.True(t, drifted)
+	assert.Contains(t, b.String(), "2 chunks removed, 2 chunks added")
+	assert.Contains(t, b.String(), "- [0, 7) one two\n")
+	assert.Contains(t, b.String(), "+ [4, 13) two three\n")
+
+	b.Reset()
+	drifted, err = checkGolden(&b, dir, "docs/a.txt", moved, true)
+	assert.NoError(t, err)
+	assert.False(t, drifted)
+	golden, err := files.ReadGolden(goldenPath(dir, "docs/a.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, moved, golden)
+}
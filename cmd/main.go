@@ -2,26 +2,121 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	semchunk "github.com/sanbaiw/semtxtsplitter"
+	"github.com/sanbaiw/semtxtsplitter/files"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "analyze" {
+		err := runAnalyze(os.Args[2:], func(text string) int { return len(strings.Fields(text)) })
+		if err == flag.ErrHelp {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Define command line flags
 	chunkSize := flag.Int("chunk-size", 100, "Maximum number of tokens per chunk")
 	overlap := flag.Float64("overlap", 0.1, "Overlap ratio between chunks (0-1)")
 	preserveURLs := flag.Bool("preserve-urls", true, "Preserve URLs in chunks")
 	preservePatterns := flag.String("preserve-patterns", "", "Comma-separated list of patterns to preserve")
+	output := flag.String("output", "text", "Output format: text, jsonl, annotated (the input text with chunk boundaries marked) or html-report (a standalone page visualizing the chunks)")
+	format := flag.String("format", "text", "Input format: text, auto, docx, epub or one of "+strings.Join(semchunk.Formats(), ", ")+
+		"; docx and epub read the file named by the argument, auto also reads it if it names a file")
+	preset := flag.String("preset", "", "Pipeline preset: "+strings.Join(semchunk.Presets(), ", ")+
+		"; sets the chunk size, overlap and preserve patterns, -chunk-size and -overlap override it")
+	sourceMap := flag.String("source-map", "", "Also write a JSON source map relating every chunk to its byte span, lines and section to this file")
+	parents := flag.String("parents", "", "Also write the input document, keyed by the document ID of its chunks, to this file as a JSON line, for parent-document retrieval")
+	golden := flag.String("golden", "", "Compare the chunks with the golden chunks of the input stored in this directory and report drift, exiting with status 1 if any; the golden chunks are written if there are none")
+	updateGolden := flag.Bool("update-golden", false, "With -golden, overwrite the golden chunks with the current ones")
+	stdio := flag.Bool("stdio", false, "Serve JSON-RPC requests (initialize, split, shutdown) on stdin and stdout, one per line")
 	flag.Parse()
 
-	// Get input text from arguments or stdin
+	// Create token counter function (simple word count for demonstration)
+	countTokens := func(text string) int {
+		return len(strings.Fields(text))
+	}
+
+	cfg := splitterConfig{ChunkSize: *chunkSize, Overlap: *overlap, PreserveURLs: *preserveURLs}
+	if *preservePatterns != "" {
+		cfg.PreservePatterns = strings.Split(*preservePatterns, ",")
+	}
+	if *preset != "" {
+		p, err := semchunk.Preset(*preset)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.Preset, cfg.ChunkSize, cfg.Overlap = p.Name, p.ChunkSize, float64(p.Overlap)
+		// explicitly set flags override the preset
+		flag.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "chunk-size":
+				cfg.ChunkSize = *chunkSize
+			case "overlap":
+				cfg.Overlap = *overlap
+			}
+		})
+	}
+
+	if *stdio {
+		server := &stdioServer{defaults: cfg, countTokens: countTokens}
+		if err := server.serve(os.Stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error serving stdio: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Get input text from a file, the arguments or stdin
 	var text string
-	if len(flag.Args()) > 0 {
+	fromFile := false
+	if *format == "auto" && len(flag.Args()) == 1 {
+		if binary := binaryFormat(flag.Arg(0)); binary != "" {
+			// DOCX and EPUB files are read by the splitter below
+			*format = binary
+		} else if info, err := os.Stat(flag.Arg(0)); err == nil && info.Mode().IsRegular() {
+			// Detect the format of the named file
+			content, err := os.ReadFile(flag.Arg(0))
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+				os.Exit(1)
+			}
+			*format = semchunk.Detect(flag.Arg(0), string(content))
+			text, fromFile = string(content), true
+		}
+	}
+
+	known := *format == "text" || *format == "auto" || *format == semchunk.FormatDOCX || *format == semchunk.FormatEPUB
+	for _, name := range semchunk.Formats() {
+		known = known || *format == name
+	}
+	if !known {
+		fmt.Fprintf(os.Stderr, "Error: unknown input format %q\n", *format)
+		os.Exit(1)
+	}
+	fileInput := *format == semchunk.FormatDOCX || *format == semchunk.FormatEPUB
+	if fileInput && len(flag.Args()) != 1 {
+		fmt.Fprintf(os.Stderr, "Error: -format %s needs exactly one file argument\n", *format)
+		os.Exit(1)
+	}
+
+	if fileInput || fromFile {
+		// read by the splitter or above
+	} else if len(flag.Args()) > 0 {
 		text = strings.Join(flag.Args(), " ")
 	} else {
 		// Read from stdin
@@ -46,26 +141,79 @@ func main() {
 		}
 	}
 
-	// Create token counter function (simple word count for demonstration)
-	countTokens := func(text string) int {
-		return len(strings.Fields(text))
+	// Create text splitter
+	splitter, err := cfg.newSplitter(countTokens)
+	if err != nil {
+		fmt.Printf("Error creating text splitter: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Create options
-	var opts []func(*semchunk.TextSplitterOption)
-	if *preserveURLs {
-		opts = append(opts, semchunk.WithPreserveURLs(true))
+	if *output != "text" && *output != "jsonl" && *output != "annotated" && *output != "html-report" {
+		fmt.Fprintf(os.Stderr, "Error: unknown output format %q\n", *output)
+		os.Exit(1)
 	}
-	if *preservePatterns != "" {
-		patterns := strings.Split(*preservePatterns, ",")
-		opts = append(opts, semchunk.WithPreservePatterns(patterns...))
+
+	if *format != "text" || *output == "annotated" || *output == "html-report" || *sourceMap != "" || *parents != "" || *golden != "" {
+		// Split along the document structure
+		var chunks []semchunk.Chunk
+		text, chunks, err = splitDocument(splitter, *format, flag.Arg(0), text)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error splitting %s: %v\n", *format, err)
+			os.Exit(1)
+		}
+		doc := semchunk.Document{ID: "stdin", Text: text}
+		if fileInput || fromFile {
+			doc.ID = flag.Arg(0)
+		} else if len(flag.Args()) > 0 {
+			doc.ID = "args"
+		}
+		if fileInput || fromFile || *parents != "" {
+			for i := range chunks {
+				chunks[i].Metadata.DocumentID = doc.ID
+			}
+		}
+		if *parents != "" {
+			if err := writeParent(*parents, doc, len(chunks)); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing parent document: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if *sourceMap != "" {
+			if err := writeSourceMap(*sourceMap, chunks); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing source map: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if *golden != "" {
+			drifted, err := checkGolden(os.Stdout, *golden, doc.ID, chunks, *updateGolden)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error comparing golden chunks: %v\n", err)
+				os.Exit(1)
+			}
+			if drifted {
+				os.Exit(1)
+			}
+			return
+		}
+		if err := printChunks(text, chunks, *output); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing chunks: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	// Create text splitter
-	splitter, err := semchunk.NewTextSplitter(*chunkSize, float32(*overlap), countTokens, opts...)
-	if err != nil {
-		fmt.Printf("Error creating text splitter: %v\n", err)
-		os.Exit(1)
+	if *output == "jsonl" {
+		// Stream one JSON object per chunk
+		w := bufio.NewWriter(os.Stdout)
+		if err := splitter.SplitTo(text, semchunk.NewJSONLSink(w)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing chunks: %v\n", err)
+			os.Exit(1)
+		}
+		if err := w.Flush(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing chunks: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
 
 	// Split the text
@@ -78,3 +226,101 @@ func main() {
 		fmt.Printf("Chunk %d (%d tokens): %s\n", i+1, countTokens(chunk), chunk)
 	}
 }
+
+// splitDocument splits the document text, or the file path for DOCX and EPUB, along its
+// structure in format. It returns the chunks and the text their offsets refer to, which for
+// documents converted to Markdown is the Markdown text.
+func splitDocument(splitter *semchunk.TextSplitter, format string, path string, text string) (string, []semchunk.Chunk, error) {
+	var err error
+	switch format {
+	case semchunk.FormatDOCX:
+		text, err = files.ReadDOCX(path)
+		format = semchunk.FormatMarkdown
+	case semchunk.FormatEPUB:
+		text, err = files.ReadEPUB(path)
+		format = semchunk.FormatMarkdown
+	case semchunk.FormatHTML:
+		text, err = semchunk.ReadHTML(text)
+		format = semchunk.FormatMarkdown
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	var chunks []semchunk.Chunk
+	if format == "text" {
+		chunks, err = splitter.SplitChunksContext(context.Background(), text)
+	} else {
+		chunks, err = splitter.SplitFormat(context.Background(), format, text)
+	}
+	return text, chunks, err
+}
+
+// binaryFormat returns the format of the DOCX or EPUB file name, which Detect does not
+// recognize because SplitFormat can not split them, or "" for other files
+func binaryFormat(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".docx":
+		return semchunk.FormatDOCX
+	case ".epub":
+		return semchunk.FormatEPUB
+	}
+	return ""
+}
+
+// writeSourceMap writes the source map of chunks to the file name
+func writeSourceMap(name string, chunks []semchunk.Chunk) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := semchunk.WriteSourceMap(f, chunks); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeParent writes the parent document record of doc to the file name
+func writeParent(name string, doc semchunk.Document, chunks int) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(f).Encode(semchunk.ParentDocument{Document: doc, Chunks: chunks}); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// printChunks writes chunks to stdout as JSON lines, as text annotated with the chunk
+// boundaries, as an HTML report or as text with their heading paths
+func printChunks(text string, chunks []semchunk.Chunk, output string) error {
+	w := bufio.NewWriter(os.Stdout)
+	switch output {
+	case "annotated":
+		if err := writeAnnotated(w, text, chunks); err != nil {
+			return err
+		}
+		return w.Flush()
+	case "html-report":
+		if err := writeHTMLReport(w, text, chunks); err != nil {
+			return err
+		}
+		return w.Flush()
+	case "jsonl":
+		sink := semchunk.NewJSONLSink(w)
+		for _, chunk := range chunks {
+			if err := sink.Write(chunk); err != nil {
+				return err
+			}
+		}
+		return w.Flush()
+	}
+
+	fmt.Fprintf(w, "Split into %d chunks:\n", len(chunks))
+	for _, chunk := range chunks {
+		fmt.Fprintf(w, "Chunk %d (%d tokens) [%s]: %s\n", chunk.Index+1, chunk.Tokens, strings.Join(chunk.Metadata.HeadingPath, " > "), chunk.Text)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+
+	semchunk "github.com/sanbaiw/semtxtsplitter"
+)
+
+// boundary is the start or end of a chunk within the input text
+type boundary struct {
+	offset int
+	end    bool
+	chunk  semchunk.Chunk
+}
+
+// chunkBoundaries returns the starts and ends of chunks ordered by offset. At the same offset
+// ends come before starts, so adjacent chunks read as closed before the next opens.
+func chunkBoundaries(chunks []semchunk.Chunk) []boundary {
+	boundaries := make([]boundary, 0, 2*len(chunks))
+	for _, chunk := range chunks {
+		boundaries = append(boundaries, boundary{offset: chunk.Start, chunk: chunk}, boundary{offset: chunk.End, end: true, chunk: chunk})
+	}
+	sort.SliceStable(boundaries, func(i, j int) bool {
+		if boundaries[i].offset != boundaries[j].offset {
+			return boundaries[i].offset < boundaries[j].offset
+		}
+		return boundaries[i].end && !boundaries[j].end
+	})
+	return boundaries
+}
+
+// writeAnnotated writes text with a marker ⟦n: t tokens⟧ where chunk n starts and ⟦/n⟧ where
+// it ends. Overlapping chunks show as interleaved markers.
+func writeAnnotated(w io.Writer, text string, chunks []semchunk.Chunk) error {
+	pos := 0
+	for _, b := range chunkBoundaries(chunks) {
+		if b.offset < pos || b.offset > len(text) {
+			continue
+		}
+		if _, err := io.WriteString(w, text[pos:b.offset]); err != nil {
+			return err
+		}
+		pos = b.offset
+		var err error
+		if b.end {
+			_, err = fmt.Fprintf(w, "⟦/%d⟧", b.chunk.Index+1)
+		} else {
+			_, err = fmt.Fprintf(w, "⟦%d: %d tokens⟧", b.chunk.Index+1, b.chunk.Tokens)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, text[pos:])
+	return err
+}
+
+// segment is a stretch of the input text covered by the same set of chunks
+type segment struct {
+	Text   string
+	Chunks []int
+}
+
+// Color returns the background of the segment, one hue per chunk, none outside chunks
+func (s segment) Color() template.CSS {
+	if len(s.Chunks) == 0 {
+		return "transparent"
+	}
+	// golden angle steps keep neighbouring chunks apart
+	return template.CSS(fmt.Sprintf("hsl(%d, 70%%, 85%%)", s.Chunks[0]*137%360))
+}
+
+// Title lists the chunks covering the segment
+func (s segment) Title() string {
+	title := ""
+	for i, chunk := range s.Chunks {
+		if i > 0 {
+			title += ", "
+		}
+		title += fmt.Sprintf("chunk %d", chunk+1)
+	}
+	return title
+}
+
+// bucket is one bar of the chunk size histogram
+type bucket struct {
+	Low, High int
+	Count     int
+	Percent   int
+}
+
+type report struct {
+	Chunks    []semchunk.Chunk
+	Segments  []segment
+	Histogram []bucket
+	Tokens    int
+	Overlap   int
+}
+
+// histogramBuckets is the number of bars in the size histogram
+const histogramBuckets = 10
+
+// segments cuts text at every chunk boundary and records the chunks covering each piece
+func segments(text string, chunks []semchunk.Chunk) []segment {
+	var segs []segment
+	var open []int
+	pos := 0
+	for _, b := range chunkBoundaries(chunks) {
+		if b.offset > len(text) {
+			break
+		}
+		if b.offset > pos {
+			segs = append(segs, segment{Text: text[pos:b.offset], Chunks: append([]int(nil), open...)})
+			pos = b.offset
+		}
+		if b.end {
+			for i, index := range open {
+				if index == b.chunk.Index {
+					open = append(open[:i], open[i+1:]...)
+					break
+				}
+			}
+		} else {
+			open = append(open, b.chunk.Index)
+		}
+	}
+	if pos < len(text) {
+		segs = append(segs, segment{Text: text[pos:]})
+	}
+	return segs
+}
+
+// histogram buckets the token counts of chunks into equally wide ranges
+func histogram(chunks []semchunk.Chunk) []bucket {
+	if len(chunks) == 0 {
+		return nil
+	}
+	largest := 0
+	for _, chunk := range chunks {
+		if chunk.Tokens > largest {
+			largest = chunk.Tokens
+		}
+	}
+	width := largest/histogramBuckets + 1
+	buckets := make([]bucket, largest/width+1)
+	for i := range buckets {
+		buckets[i].Low, buckets[i].High = i*width, (i+1)*width-1
+	}
+	most := 0
+	for _, chunk := range chunks {
+		b := &buckets[chunk.Tokens/width]
+		b.Count++
+		if b.Count > most {
+			most = b.Count
+		}
+	}
+	for i := range buckets {
+		buckets[i].Percent = buckets[i].Count * 100 / most
+	}
+	return buckets
+}
+
+// writeHTMLReport writes a standalone HTML page showing text with every chunk in its own color,
+// overlapping text outlined, a histogram of chunk sizes and a table of the chunks
+func writeHTMLReport(w io.Writer, text string, chunks []semchunk.Chunk) error {
+	r := report{Chunks: chunks, Segments: segments(text, chunks), Histogram: histogram(chunks)}
+	for _, chunk := range chunks {
+		r.Tokens += chunk.Tokens
+	}
+	for _, seg := range r.Segments {
+		if len(seg.Chunks) > 1 {
+			r.Overlap += len(seg.Text)
+		}
+	}
+	return reportTemplate.Execute(w, r)
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Chunking report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+.text { white-space: pre-wrap; font-family: monospace; line-height: 1.6; border: 1px solid #ccc; padding: 1em; }
+.overlap { outline: 2px dashed #c00; }
+.histogram td { padding: 2px 6px; }
+.bar { background: #69c; height: 1em; }
+table.chunks { border-collapse: collapse; }
+table.chunks td, table.chunks th { border: 1px solid #ccc; padding: 2px 8px; text-align: right; }
+</style>
+</head>
+<body>
+<h1>Chunking report</h1>
+<p>{{len .Chunks}} chunks, {{.Tokens}} tokens, {{.Overlap}} bytes in overlaps.</p>
+<h2>Chunk sizes</h2>
+<table class="histogram">
+{{range .Histogram}}<tr><td>{{.Low}}–{{.High}} tokens</td><td>{{.Count}}</td><td style="width: 20em"><div class="bar" style="width: {{.Percent}}%"></div></td></tr>
+{{end}}</table>
+<h2>Text</h2>
+<div class="text">{{range .Segments}}<span{{if gt (len .Chunks) 1}} class="overlap"{{end}} style="background: {{.Color}}" title="{{.Title}}">{{.Text}}</span>{{end}}</div>
+<h2>Chunks</h2>
+<table class="chunks">
+<tr><th>Chunk</th><th>Tokens</th><th>Start</th><th>End</th><th>Level</th></tr>
+{{range .Chunks}}<tr><td>{{inc .Index}}</td><td>{{.Tokens}}</td><td>{{.Start}}</td><td>{{.End}}</td><td>{{.Metadata.Level}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
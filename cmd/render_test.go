@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	semchunk "github.com/sanbaiw/semtxtsplitter"
+)
+
+func TestWriteAnnotated(t *testing.T) {
+	text := "one two three four five"
+	chunks := []semchunk.Chunk{
+		{Index: 0, Start: 0, End: 13, Tokens: 3},
+		{Index: 1, Start: 8, End: 18, Tokens: 2},
+		{Index: 2, Start: 19, End: 23, Tokens: 1},
+	}
+	var b strings.Builder
+	assert.NoError(t, writeAnnotated(&b, text, chunks))
+	assert.Equal(t, "⟦1: 3 tokens⟧one two ⟦2: 2 tokens⟧three⟦/1⟧ four⟦/2⟧ ⟦3: 1 tokens⟧five⟦/3⟧", b.String())
+}
+
+func TestSegments(t *testing.T) {
+	text := "one two three four five"
+	chunks := []semchunk.Chunk{
+		{Index: 0, Start: 0, End: 13, Tokens: 3},
+		{Index: 1, Start: 8, End: 18, Tokens: 2},
+	}
+	assert.Equal(t, []segment{
+		{Text: "one two ", Chunks: []int{0}},
+		{Text: "three", Chunks: []int{0, 1}},
+		{Text: " four", Chunks: []int{1}},
+		{Text: " five"},
+	}, segments(text, chunks))
+}
+
+func TestHistogram(t *testing.T) {
+	buckets := histogram([]semchunk.Chunk{{Tokens: 3}, {Tokens: 25}, {Tokens: 28}})
+	assert.Len(t, buckets, 10)
+	assert.Equal(t, bucket{Low: 0, High: 2, Count: 0}, buckets[0])
+	assert.Equal(t, bucket{Low: 3, High: 5, Count: 1, Percent: 100}, buckets[1])
+	assert.Equal(t, bucket{Low: 24, High: 26, Count: 1, Percent: 100}, buckets[8])
+	assert.Equal(t, bucket{Low: 27, High: 29, Count: 1, Percent: 100}, buckets[9])
+	assert.Nil(t, histogram(nil))
+}
+
+func TestWriteHTMLReport(t *testing.T) {
+	var b strings.Builder
+	chunks := []semchunk.Chunk{{Index: 0, Start: 0, End: 7, Tokens: 2}, {Index: 1, Start: 4, End: 11, Tokens: 2}}
+	assert.NoError(t, writeHTMLReport(&b, "a<b c&d e f", chunks))
+	assert.Contains(t, b.String(), "2 chunks, 4 tokens, 3 bytes in overlaps.")
+	assert.Contains(t, b.String(), `class="overlap"`)
+	assert.Contains(t, b.String(), "a&lt;b ")
+}
+
+func TestWriteAnnotatedHTML(t *testing.T) {
+	splitter, err := semchunk.NewTextSplitter(100, 0, func(text string) int { return len(strings.Fields(text)) })
+	assert.NoError(t, err)
+	text, chunks, err := splitDocument(splitter, semchunk.FormatHTML, "", "<html><body><h1>Title</h1><p>Some <b>bold</b> text.</p></body></html>")
+	assert.NoError(t, err)
+	assert.Equal(t, "# Title\n\nSome bold text.", text)
+
+	// boundaries are marked in the converted text the offsets refer to
+	var b strings.Builder
+	assert.NoError(t, writeAnnotated(&b, text, chunks))
+	assert.Equal(t, "⟦1: 5 tokens⟧# Title\n\nSome bold text.⟦/1⟧", b.String())
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	semchunk "github.com/sanbaiw/semtxtsplitter"
+)
+
+// JSON-RPC 2.0 error codes
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+	// rpcNotInitialized is returned for split before initialize, as in LSP
+	rpcNotInitialized = -32002
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// splitterConfig holds the settings of the splitter, from the flags or the initialize params
+type splitterConfig struct {
+	ChunkSize        int      `json:"chunk_size"`
+	Overlap          float64  `json:"overlap"`
+	PreserveURLs     bool     `json:"preserve_urls"`
+	PreservePatterns []string `json:"preserve_patterns"`
+	// Preset adds the options of a pipeline preset, see semchunk.Presets
+	Preset string `json:"preset"`
+}
+
+func (cfg splitterConfig) newSplitter(countTokens func(string) int) (*semchunk.TextSplitter, error) {
+	var opts []func(*semchunk.TextSplitterOption)
+	if cfg.Preset != "" {
+		preset, err := semchunk.Preset(cfg.Preset)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, preset.Options...)
+	}
+	if cfg.PreserveURLs {
+		opts = append(opts, semchunk.WithPreserveURLs(true))
+	}
+	if len(cfg.PreservePatterns) > 0 {
+		opts = append(opts, semchunk.WithPreservePatterns(cfg.PreservePatterns...))
+	}
+	return semchunk.NewTextSplitter(cfg.ChunkSize, float32(cfg.Overlap), countTokens, opts...)
+}
+
+type splitParams struct {
+	Text string `json:"text"`
+	// Format is plain by default, see semchunk.Formats
+	Format string `json:"format"`
+}
+
+// stdioServer answers JSON-RPC requests, one JSON object per line, keeping one splitter warm
+// between requests. initialize creates the splitter, optionally overriding the command line
+// settings, split splits a text and shutdown ends the session.
+type stdioServer struct {
+	defaults    splitterConfig
+	countTokens func(string) int
+	splitter    *semchunk.TextSplitter
+}
+
+// serve reads requests from r until shutdown or EOF and writes the responses to w
+func (s *stdioServer) serve(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1<<30)
+	out := bufio.NewWriter(w)
+	enc := json.NewEncoder(out)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var req rpcRequest
+		resp := rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null")}
+		if err := json.Unmarshal([]byte(line), &req); err != nil {
+			resp.Error = &rpcError{Code: rpcParseError, Message: err.Error()}
+		} else {
+			if req.ID != nil {
+				resp.ID = req.ID
+			}
+			resp.Result, resp.Error = s.handle(req)
+		}
+		// notifications get no response
+		if req.ID != nil || resp.Error != nil && resp.Error.Code == rpcParseError {
+			if err := enc.Encode(resp); err != nil {
+				return err
+			}
+			if err := out.Flush(); err != nil {
+				return err
+			}
+		}
+		if req.Method == "shutdown" && resp.Error == nil {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *stdioServer) handle(req rpcRequest) (any, *rpcError) {
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return nil, &rpcError{Code: rpcInvalidRequest, Message: "invalid request"}
+	}
+	switch req.Method {
+	case "initialize":
+		cfg := s.defaults
+		if len(req.Params) > 0 {
+			if err := json.Unmarshal(req.Params, &cfg); err != nil {
+				return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+			}
+		}
+		splitter, err := cfg.newSplitter(s.countTokens)
+		if err != nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+		}
+		s.splitter = splitter
+		return map[string]any{"formats": semchunk.Formats()}, nil
+	case "split":
+		if s.splitter == nil {
+			return nil, &rpcError{Code: rpcNotInitialized, Message: "not initialized"}
+		}
+		var params splitParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, &rpcError{Code: rpcInvalidParams, Message: err.Error()}
+		}
+		if params.Format == "" {
+			params.Format = semchunk.FormatPlain
+		}
+		chunks, err := s.splitter.SplitFormat(context.Background(), params.Format, params.Text)
+		if err != nil {
+			code := rpcInternalError
+			if errors.Is(err, semchunk.ErrUnknownFormat) {
+				code = rpcInvalidParams
+			}
+			return nil, &rpcError{Code: code, Message: err.Error()}
+		}
+		if chunks == nil {
+			chunks = []semchunk.Chunk{}
+		}
+		return map[string]any{"chunks": chunks}, nil
+	case "shutdown":
+		return struct{}{}, nil
+	default:
+		return nil, &rpcError{Code: rpcMethodNotFound, Message: "method not found: " + req.Method}
+	}
+}
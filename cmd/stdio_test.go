@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStdioServer(t *testing.T) {
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"split","params":{"text":"a b"}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"initialize","params":{"chunk_size":2}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"split","params":{"text":"one two. three four."}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"split","params":{"text":"x","format":"nope"}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"bogus"}`,
+		`not json`,
+		`{"jsonrpc":"2.0","method":"split","params":{"text":"notification"}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","id":7,"method":"split","params":{"text":"after shutdown"}}`,
+	}, "\n")
+	server := &stdioServer{
+		defaults:    splitterConfig{ChunkSize: 100},
+		countTokens: func(text string) int { return len(strings.Fields(text)) },
+	}
+	var out bytes.Buffer
+	assert.NoError(t, server.serve(strings.NewReader(in), &out))
+
+	type response struct {
+		ID     any `json:"id"`
+		Result struct {
+			Chunks []struct {
+				Text string `json:"text"`
+			} `json:"chunks"`
+		} `json:"result"`
+		Error *rpcError `json:"error"`
+	}
+	var responses []response
+	dec := json.NewDecoder(&out)
+	for dec.More() {
+		var resp response
+		assert.NoError(t, dec.Decode(&resp))
+		responses = append(responses, resp)
+	}
+	if !assert.Len(t, responses, 7) {
+		return
+	}
+	assert.Equal(t, rpcNotInitialized, responses[0].Error.Code)
+	assert.Nil(t, responses[1].Error)
+	if assert.Len(t, responses[2].Result.Chunks, 2) {
+		assert.Equal(t, "one two.", responses[2].Result.Chunks[0].Text)
+	}
+	assert.Equal(t, rpcInvalidParams, responses[3].Error.Code)
+	assert.Equal(t, rpcMethodNotFound, responses[4].Error.Code)
+	assert.Equal(t, rpcParseError, responses[5].Error.Code)
+	assert.Nil(t, responses[5].ID)
+	assert.Equal(t, float64(6), responses[6].ID)
+	assert.Nil(t, responses[6].Error)
+}
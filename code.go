@@ -0,0 +1,40 @@
+package semchunk
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// splitCode splits source code so that each top level block, such as a function or a class
+// with the comments above it, stays in one chunk unless it exceeds the chunk size. Blocks
+// that do are split along blank lines and lines like plain text.
+func (c *TextSplitter) splitCode(ctx context.Context, text string) ([]Chunk, error) {
+	return c.splitParsed(ctx, text, true, parseCode)
+}
+
+// parseCode finds the top level blocks of source code: a block starts at an unindented line
+// after a blank line and ends at the last non-blank line before the next block
+func parseCode(text string) documentStructure {
+	doc := documentStructure{sections: []Section{{Start: 0, End: len(text)}}}
+	start, end := -1, 0
+	blank := true
+	for _, line := range textLines(text) {
+		if strings.TrimSpace(line.text) == "" {
+			blank = true
+			continue
+		}
+		if blank && !unicode.IsSpace(rune(line.text[0])) {
+			if start >= 0 {
+				doc.blocks = append(doc.blocks, [2]int{start, end})
+			}
+			start = line.start
+		}
+		blank = false
+		end = line.end
+	}
+	if start >= 0 {
+		doc.blocks = append(doc.blocks, [2]int{start, end})
+	}
+	return doc
+}
@@ -0,0 +1,28 @@
+package semchunk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitCode(t *testing.T) {
+	text := "package main\n\n// add adds\nfunc add(a, b int) int {\n\tsum := a + b\n\n\treturn sum\n}\n\nfunc main() {\n\tprintln(add(1, 2))\n}\n"
+	splitter := newWordSplitter(t, 17, 0)
+
+	// plain text splitting cuts the function at its blank line
+	plain, err := splitter.SplitFormat(context.Background(), FormatPlain, text)
+	assert.NoError(t, err)
+	assert.Equal(t, "package main\n\n// add adds\nfunc add(a, b int) int {\n\tsum := a + b", plain[0].Text)
+
+	chunks, err := splitter.SplitFormat(context.Background(), FormatCode, text)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"package main",
+		"// add adds\nfunc add(a, b int) int {\n\tsum := a + b\n\n\treturn sum\n}",
+		"func main() {\n\tprintln(add(1, 2))\n}",
+	}, chunkTexts(chunks))
+
+	assert.Equal(t, [][2]int{{0, 12}, {14, 79}, {81, 116}}, parseCode(text).blocks)
+}
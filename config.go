@@ -0,0 +1,223 @@
+package semchunk
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Config holds the settings of a TextSplitter as a plain struct that can be read
+// from JSON or YAML, for services configured by files. Every field mirrors an option; zero
+// values leave the option unset. Fields holding functions or interfaces cannot be serialized
+// and must be set in code.
+type Config struct {
+	ChunkSize int `json:"chunk_size" yaml:"chunk_size"`
+	// Overlap is the overlap as a fraction of the chunk size; OverlapTokens gives it in tokens
+	Overlap       float64 `json:"overlap,omitempty" yaml:"overlap,omitempty"`
+	OverlapTokens int     `json:"overlap_tokens,omitempty" yaml:"overlap_tokens,omitempty"`
+	// Counter counts tokens, see NewTextSplitterWithCounter. It is not needed with a SizeUnit.
+	Counter  TokenCounter `json:"-" yaml:"-"`
+	SizeUnit SizeUnit     `json:"size_unit,omitempty" yaml:"size_unit,omitempty"`
+
+	SafetyMargin            float64 `json:"safety_margin,omitempty" yaml:"safety_margin,omitempty"`
+	RelativeOverlap         float64 `json:"relative_overlap,omitempty" yaml:"relative_overlap,omitempty"`
+	MinOverlap              int     `json:"min_overlap,omitempty" yaml:"min_overlap,omitempty"`
+	NoParagraphBreakOverlap bool    `json:"no_paragraph_break_overlap,omitempty" yaml:"no_paragraph_break_overlap,omitempty"`
+	CheckCounter            bool    `json:"check_counter,omitempty" yaml:"check_counter,omitempty"`
+	// EstimationTolerance enables WithTokenEstimation if not 0
+	EstimationTolerance float64 `json:"estimation_tolerance,omitempty" yaml:"estimation_tolerance,omitempty"`
+
+	PreserveURLs bool `json:"preserve_urls,omitempty" yaml:"preserve_urls,omitempty"`
+	// PreservePatterns are literal strings, see WithPreservePatterns
+	PreservePatterns    []string          `json:"preserve_patterns,omitempty" yaml:"preserve_patterns,omitempty"`
+	PreservePresets     []PreservePreset  `json:"preserve_presets,omitempty" yaml:"preserve_presets,omitempty"`
+	PatternResolution   PatternResolution `json:"pattern_resolution,omitempty" yaml:"pattern_resolution,omitempty"`
+	OversizedMatches    OversizedPolicy   `json:"oversized_matches,omitempty" yaml:"oversized_matches,omitempty"`
+	WholeMatch          WholeMatchPolicy  `json:"whole_match,omitempty" yaml:"whole_match,omitempty"`
+	WholeMatchWrap      int               `json:"whole_match_wrap,omitempty" yaml:"whole_match_wrap,omitempty"`
+	Blobs               BlobMode          `json:"blobs,omitempty" yaml:"blobs,omitempty"`
+	BlobMinLength       int               `json:"blob_min_length,omitempty" yaml:"blob_min_length,omitempty"`
+	SentenceTerminators []string          `json:"sentence_terminators,omitempty" yaml:"sentence_terminators,omitempty"`
+	ClauseSeparators    []string          `json:"clause_separators,omitempty" yaml:"clause_separators,omitempty"`
+	NoClauseSplitting   bool              `json:"no_clause_splitting,omitempty" yaml:"no_clause_splitting,omitempty"`
+	ChunkSuffix         string            `json:"chunk_suffix,omitempty" yaml:"chunk_suffix,omitempty"`
+	NumericTables       bool              `json:"numeric_tables,omitempty" yaml:"numeric_tables,omitempty"`
+	RepeatTableHeader   bool              `json:"repeat_table_header,omitempty" yaml:"repeat_table_header,omitempty"`
+	OversizedThreshold  int               `json:"oversized_threshold,omitempty" yaml:"oversized_threshold,omitempty"`
+	AlgorithmVersion    string            `json:"algorithm_version,omitempty" yaml:"algorithm_version,omitempty"`
+
+	Whitespace         string        `json:"whitespace,omitempty" yaml:"whitespace,omitempty"`
+	InvisibleChars     InvisibleMode `json:"invisible_chars,omitempty" yaml:"invisible_chars,omitempty"`
+	Dividers           bool          `json:"dividers,omitempty" yaml:"dividers,omitempty"`
+	ScriptSegmentation bool          `json:"script_segmentation,omitempty" yaml:"script_segmentation,omitempty"`
+	NewlineTiers       *NewlineTiers `json:"newline_tiers,omitempty" yaml:"newline_tiers,omitempty"`
+	PageMarkers        []string      `json:"page_markers,omitempty" yaml:"page_markers,omitempty"`
+	// RecordSeparator is a regular expression, see WithRecords
+	RecordSeparator     string   `json:"record_separator,omitempty" yaml:"record_separator,omitempty"`
+	KeepRecordSeparator bool     `json:"keep_record_separator,omitempty" yaml:"keep_record_separator,omitempty"`
+	HardBoundaries      []string `json:"hard_boundaries,omitempty" yaml:"hard_boundaries,omitempty"`
+
+	RepairWindow  int     `json:"repair_window,omitempty" yaml:"repair_window,omitempty"`
+	SnapTolerance int     `json:"snap_tolerance,omitempty" yaml:"snap_tolerance,omitempty"`
+	PullHeadings  bool    `json:"pull_headings,omitempty" yaml:"pull_headings,omitempty"`
+	TOC           TOCMode `json:"toc,omitempty" yaml:"toc,omitempty"`
+
+	LangChain      *LangChainOptions `json:"langchain,omitempty" yaml:"langchain,omitempty"`
+	SemchunkCompat bool              `json:"semchunk_compat,omitempty" yaml:"semchunk_compat,omitempty"`
+
+	AnnotatorConcurrency int           `json:"annotator_concurrency,omitempty" yaml:"annotator_concurrency,omitempty"`
+	SplitConcurrency     int           `json:"split_concurrency,omitempty" yaml:"split_concurrency,omitempty"`
+	MinContentRunes      int           `json:"min_content_runes,omitempty" yaml:"min_content_runes,omitempty"`
+	MaxChunkBytes        int           `json:"max_chunk_bytes,omitempty" yaml:"max_chunk_bytes,omitempty"`
+	MaxSplitOps          int           `json:"max_split_ops,omitempty" yaml:"max_split_ops,omitempty"`
+	Timeout              time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	ContextExcerptBytes  int           `json:"context_excerpt_bytes,omitempty" yaml:"context_excerpt_bytes,omitempty"`
+	PrependContext       bool          `json:"prepend_context,omitempty" yaml:"prepend_context,omitempty"`
+	ContextBudget        int           `json:"context_budget,omitempty" yaml:"context_budget,omitempty"`
+
+	// DetectPII enables WithPIIDetection for PIICategories, all categories if empty
+	DetectPII     bool          `json:"detect_pii,omitempty" yaml:"detect_pii,omitempty"`
+	PIICategories []PIICategory `json:"pii_categories,omitempty" yaml:"pii_categories,omitempty"`
+	// Denylist holds keywords and DenylistPatterns regular expressions, see WithDenylist
+	Denylist         []string     `json:"denylist,omitempty" yaml:"denylist,omitempty"`
+	DenylistPatterns []string     `json:"denylist_patterns,omitempty" yaml:"denylist_patterns,omitempty"`
+	DenylistMode     DenylistMode `json:"denylist_mode,omitempty" yaml:"denylist_mode,omitempty"`
+
+	Annotator        ChunkAnnotator   `json:"-" yaml:"-"`
+	ContextGenerator ContextGenerator `json:"-" yaml:"-"`
+	BoundaryDetector BoundaryDetector `json:"-" yaml:"-"`
+	BoundaryMinScore float64          `json:"boundary_min_score,omitempty" yaml:"boundary_min_score,omitempty"`
+	// Merger overrides MergeStrategy, MergeSeed seeds MergeBalanced
+	Merger        Merger                              `json:"-" yaml:"-"`
+	MergeStrategy MergeStrategy                       `json:"merge_strategy,omitempty" yaml:"merge_strategy,omitempty"`
+	MergeSeed     int64                               `json:"merge_seed,omitempty" yaml:"merge_seed,omitempty"`
+	StopDescent   func(piece string, tokens int) bool `json:"-" yaml:"-"`
+	SectionBudget func(section Section) int           `json:"-" yaml:"-"`
+	Redactor      func(text string) string            `json:"-" yaml:"-"`
+}
+
+// NewFromConfig creates a TextSplitter from cfg, like NewTextSplitterWithCounter with the
+// options cfg describes
+func NewFromConfig(cfg Config) (*TextSplitter, error) {
+	opts, err := cfg.Options()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.OverlapTokens > 0 {
+		return NewTextSplitterWithCounter(cfg.ChunkSize, cfg.OverlapTokens, cfg.Counter, opts...)
+	}
+	return NewTextSplitterWithCounter(cfg.ChunkSize, float32(cfg.Overlap), cfg.Counter, opts...)
+}
+
+// Options returns the functional options equivalent to cfg, leaving out chunk size, overlap
+// and counter. It fails if a regular expression in cfg does not compile.
+func (cfg Config) Options() ([]func(*TextSplitterOption), error) {
+	opts := make([]func(*TextSplitterOption), 0)
+	add := func(enabled bool, opt func(*TextSplitterOption)) {
+		if enabled {
+			opts = append(opts, opt)
+		}
+	}
+
+	add(cfg.SizeUnit != SizeTokens, WithSizeUnit(cfg.SizeUnit))
+	add(cfg.SafetyMargin != 0, WithSafetyMargin(cfg.SafetyMargin))
+	add(cfg.RelativeOverlap != 0, WithRelativeOverlap(cfg.RelativeOverlap))
+	add(cfg.MinOverlap != 0, WithMinOverlap(cfg.MinOverlap))
+	add(cfg.NoParagraphBreakOverlap, WithParagraphBreakOverlap(false))
+	add(cfg.CheckCounter, WithCounterCheck())
+	add(cfg.EstimationTolerance != 0, WithTokenEstimation(cfg.EstimationTolerance))
+
+	add(cfg.PreserveURLs, WithPreserveURLs(true))
+	add(len(cfg.PreservePatterns) > 0, WithPreservePatterns(cfg.PreservePatterns...))
+	add(len(cfg.PreservePresets) > 0, WithPreservePresets(cfg.PreservePresets...))
+	add(cfg.PatternResolution != ResolveFirstPattern, WithPatternResolution(cfg.PatternResolution))
+	add(cfg.OversizedMatches != OversizedEmitWhole || cfg.OversizedThreshold != 0,
+		WithOversizedMatches(cfg.OversizedMatches, cfg.OversizedThreshold))
+
+	add(cfg.WholeMatch != WholeMatchDefault || cfg.WholeMatchWrap != 0, WithWholeMatch(cfg.WholeMatch, cfg.WholeMatchWrap))
+	add(cfg.Blobs != BlobKeep || cfg.BlobMinLength != 0, WithBlobs(cfg.Blobs, cfg.BlobMinLength))
+	add(cfg.SentenceTerminators != nil, WithSentenceTerminators(cfg.SentenceTerminators...))
+	add(cfg.ClauseSeparators != nil, WithClauseSeparators(cfg.ClauseSeparators...))
+	add(cfg.NoClauseSplitting, WithClauseSplitting(false))
+	add(cfg.ChunkSuffix != "", WithChunkSuffix(cfg.ChunkSuffix))
+	add(cfg.NumericTables || cfg.RepeatTableHeader, WithNumericTables(cfg.RepeatTableHeader))
+	add(cfg.Whitespace != "", WithWhitespace(cfg.Whitespace))
+	add(cfg.AlgorithmVersion != "", WithAlgorithmVersion(cfg.AlgorithmVersion))
+	add(cfg.InvisibleChars != InvisibleKeep, WithInvisibleChars(cfg.InvisibleChars))
+	add(cfg.Dividers, WithDividers(true))
+	add(cfg.ScriptSegmentation, WithScriptSegmentation(true))
+	if cfg.NewlineTiers != nil {
+		add(true, WithNewlineTiers(cfg.NewlineTiers.Paragraphs, cfg.NewlineTiers.Lines))
+	}
+	add(len(cfg.PageMarkers) > 0, WithPageMarkers(cfg.PageMarkers...))
+	if cfg.RecordSeparator != "" {
+		separator, err := regexp.Compile(cfg.RecordSeparator)
+		if err != nil {
+			return nil, fmt.Errorf("record separator: %w", err)
+		}
+		add(true, WithRecords(separator, cfg.KeepRecordSeparator))
+	}
+	add(len(cfg.HardBoundaries) > 0, WithHardBoundaries(cfg.HardBoundaries...))
+
+	add(cfg.RepairWindow != 0, WithBoundaryRepair(cfg.RepairWindow))
+	add(cfg.SnapTolerance != 0, WithSentenceSnap(cfg.SnapTolerance))
+	add(cfg.PullHeadings, WithHeadingPullForward(true))
+	add(cfg.TOC != TOCKeep, WithTOCDetection(cfg.TOC))
+
+	if cfg.LangChain != nil {
+		add(true, WithLangChainCompat(*cfg.LangChain))
+	}
+	add(cfg.SemchunkCompat, WithSemchunkCompat())
+
+	add(cfg.AnnotatorConcurrency != 0, WithAnnotatorConcurrency(cfg.AnnotatorConcurrency))
+	add(cfg.SplitConcurrency != 0, WithSplitConcurrency(cfg.SplitConcurrency))
+	add(cfg.MinContentRunes != 0, WithMinContentRunes(cfg.MinContentRunes))
+	add(cfg.MaxChunkBytes != 0, WithMaxChunkBytes(cfg.MaxChunkBytes))
+	add(cfg.MaxSplitOps != 0, WithMaxSplitOps(cfg.MaxSplitOps))
+	add(cfg.Timeout != 0, WithTimeout(cfg.Timeout))
+	add(cfg.ContextExcerptBytes != 0, WithContextExcerpt(cfg.ContextExcerptBytes))
+	add(cfg.ContextBudget != 0, WithContextBudget(cfg.ContextBudget))
+
+	add(cfg.DetectPII, WithPIIDetection(cfg.PIICategories...))
+	add(len(cfg.Denylist) > 0, WithDenylist(cfg.DenylistMode, cfg.Denylist...))
+	if len(cfg.DenylistPatterns) > 0 {
+		patterns := make([]*regexp.Regexp, len(cfg.DenylistPatterns))
+		for i, pattern := range cfg.DenylistPatterns {
+			var err error
+			if patterns[i], err = regexp.Compile(pattern); err != nil {
+				return nil, fmt.Errorf("denylist pattern: %w", err)
+			}
+		}
+		add(true, WithDenylistPatterns(cfg.DenylistMode, patterns...))
+	}
+
+	add(cfg.Annotator != nil, WithChunkAnnotator(cfg.Annotator))
+	add(cfg.ContextGenerator != nil || cfg.PrependContext, WithContextGenerator(cfg.ContextGenerator, cfg.PrependContext))
+	add(cfg.BoundaryDetector != nil, WithBoundaryDetector(cfg.BoundaryDetector, cfg.BoundaryMinScore))
+	add(cfg.Merger == nil && cfg.MergeStrategy != MergeGreedy, WithMerger(cfg.MergeStrategy.merger(cfg.MergeSeed)))
+	add(cfg.Merger != nil, WithMerger(cfg.Merger))
+	add(cfg.StopDescent != nil, WithStopDescent(cfg.StopDescent))
+	add(cfg.SectionBudget != nil, WithSectionBudget(cfg.SectionBudget))
+	add(cfg.Redactor != nil, WithRedactor(cfg.Redactor))
+	return opts, nil
+}
+
+// marshalEnum returns the name of value, its index in names
+func marshalEnum[E ~int](value E, names []string) ([]byte, error) {
+	if value < 0 || int(value) >= len(names) {
+		return nil, fmt.Errorf("unknown %T %d", value, value)
+	}
+	return []byte(names[value]), nil
+}
+
+// unmarshalEnum sets value to the index of text in names
+func unmarshalEnum[E ~int](text []byte, names []string, value *E) error {
+	for i, name := range names {
+		if string(text) == name {
+			*value = E(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown %T %q, want one of %s", *value, text, strings.Join(names, ", "))
+}
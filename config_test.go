@@ -0,0 +1,83 @@
+package semchunk
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFromConfig(t *testing.T) {
+	text := "Visit https://example.com/a.b today. ==> Record two is here. ==> Buy cheap pills now."
+	var cfg Config
+	err := json.Unmarshal([]byte(`{
+		"chunk_size": 4,
+		"overlap_tokens": 0,
+		"preserve_urls": true,
+		"record_separator": "==>",
+		"denylist": ["cheap"],
+		"denylist_mode": "drop"
+	}`), &cfg)
+	assert.NoError(t, err)
+	cfg.Counter = TokenCounterFunc(func(text string) int { return len(strings.Fields(text)) })
+
+	splitter, err := NewFromConfig(cfg)
+	assert.NoError(t, err)
+	want := newWordSplitter(t, 4, 0, WithPreserveURLs(true), WithRecords(regexp.MustCompile("==>"), false),
+		WithDenylist(DenylistDrop, "cheap")).Split(text)
+	assert.Equal(t, want, splitter.Split(text))
+	assert.Equal(t, []string{"Visit https://example.com/a.b today.", "Record two is here."}, want)
+
+	// the counter is not serialized
+	data, err := json.Marshal(cfg)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "counter")
+
+	cfg.RecordSeparator = "("
+	_, err = NewFromConfig(cfg)
+	assert.ErrorContains(t, err, "record separator")
+
+	_, err = NewFromConfig(Config{ChunkSize: 10})
+	assert.Error(t, err)
+	splitter, err = NewFromConfig(Config{ChunkSize: 10, SizeUnit: SizeWords})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"one two three"}, splitter.Split("one two three"))
+}
+
+func TestConfigEnums(t *testing.T) {
+	cfg := Config{
+		ChunkSize:     10,
+		SizeUnit:      SizeDisplayCells,
+		WholeMatch:    WholeMatchWrap,
+		Blobs:         BlobIsolate,
+		TOC:           TOCDrop,
+		MergeStrategy: MergeBalanced,
+		LangChain:     &LangChainOptions{KeepSeparator: KeepSeparatorNone},
+	}
+	data, err := json.Marshal(cfg)
+	assert.NoError(t, err)
+	for _, field := range []string{`"size_unit":"display_cells"`, `"whole_match":"wrap"`, `"blobs":"isolate"`,
+		`"toc":"drop"`, `"merge_strategy":"balanced"`, `"keep_separator":"none"`} {
+		assert.Contains(t, string(data), field)
+	}
+	// defaults are left out
+	assert.NotContains(t, string(data), "denylist_mode")
+
+	var decoded Config
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, cfg, decoded)
+
+	err = json.Unmarshal([]byte(`{"size_unit": "pages"}`), &decoded)
+	assert.ErrorContains(t, err, `unknown semchunk.SizeUnit "pages", want one of tokens, runes`)
+	_, err = json.Marshal(Config{SizeUnit: SizeUnit(42)})
+	assert.Error(t, err)
+
+	text := "one two three four five six seven"
+	cfg = Config{ChunkSize: 3, SizeUnit: SizeWords, MergeStrategy: MergeBalanced}
+	splitter, err := NewFromConfig(cfg)
+	assert.NoError(t, err)
+	want := newWordSplitter(t, 3, 0, WithMerger(BalancedMerger{})).Split(text)
+	assert.Equal(t, want, splitter.Split(text))
+}
@@ -0,0 +1,47 @@
+package semchunk
+
+import "unicode"
+
+// WithMinContentRunes leaves out chunks with fewer than n letters and digits, such as chunks
+// that are only a divider line like "---" or a stray "。" left between sections
+func WithMinContentRunes(n int) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.MinContentRunes = n
+	}
+}
+
+// contentRunes counts the letters and digits of text, up to limit
+func contentRunes(text string, limit int) int {
+	n := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			if n++; n >= limit {
+				break
+			}
+		}
+	}
+	return n
+}
+
+// dropSparse leaves out the chunks with too little content, see WithMinContentRunes
+func (c *TextSplitter) dropSparse(chunks []Chunk) []Chunk {
+	min := c.opts.MinContentRunes
+	if min <= 0 {
+		return chunks
+	}
+	kept := chunks[:0]
+	for _, chunk := range chunks {
+		if contentRunes(chunk.Text, min) >= min {
+			kept = append(kept, chunk)
+		}
+	}
+	return kept
+}
+
+// dropChunks applies the filters that leave chunks out of the output
+func (c *TextSplitter) dropChunks(chunks []Chunk) []Chunk {
+	return c.dropSparse(c.dropDenied(c.dropTOC(chunks)))
+}
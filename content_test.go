@@ -0,0 +1,21 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMinContentRunes(t *testing.T) {
+	text := "First part of the text.\n\n---\n\nSecond part of the text.\n\n* * *\n\nThe end is near now."
+
+	assert.Equal(t, []string{"First part of the text.", "---", "Second part of the text.", "* * *", "The end is near now."},
+		newWordSplitter(t, 5, 0).Split(text))
+	assert.Equal(t, []string{"First part of the text.", "Second part of the text.", "The end is near now."},
+		newWordSplitter(t, 5, 0, WithMinContentRunes(1)).Split(text))
+	assert.Equal(t, []string{"First part of the text.", "Second part of the text."},
+		newWordSplitter(t, 5, 0, WithMinContentRunes(18)).Split(text))
+
+	assert.Equal(t, 0, contentRunes("。——…", 5))
+	assert.Equal(t, 3, contentRunes("第3章", 5))
+}
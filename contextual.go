@@ -0,0 +1,88 @@
+package semchunk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// DocumentInfo describes the document a ContextualSplitter splits
+type DocumentInfo struct {
+	Title  string `json:"title,omitempty"`
+	Source string `json:"source,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+// header returns the lines naming the fields of info that are set
+func (info DocumentInfo) header() string {
+	lines := make([]string, 0, 3)
+	for _, field := range []struct{ name, value string }{
+		{"Title", info.Title}, {"Source", info.Source}, {"URL", info.URL},
+	} {
+		if field.value != "" {
+			lines = append(lines, field.name+": "+field.value)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ContextualSplitter splits the text of one document and attaches a header describing the
+// document, such as "Title: Annual report", to every chunk, so chunks retrieved on their
+// own still say where they come from
+type ContextualSplitter struct {
+	splitter *TextSplitter
+	header   string
+	prepend  bool
+}
+
+// NewContextualSplitter returns a ContextualSplitter for the document described by info. The
+// header is stored in Metadata.ContextPrefix and, if prepend is true, also prepended to the
+// chunk text, in which case the chunk size is reduced by the size of the header so chunks
+// with the header still fit.
+func NewContextualSplitter(splitter *TextSplitter, info DocumentInfo, prepend bool) *ContextualSplitter {
+	return &ContextualSplitter{splitter: splitter, header: info.header(), prepend: prepend}
+}
+
+// Split splits text like TextSplitter.Split, with the header prepended to the chunks if so
+// configured. It returns nil if splitting fails.
+func (s *ContextualSplitter) Split(text string) []string {
+	chunks, err := s.SplitChunksContext(context.Background(), text)
+	if err != nil {
+		return nil
+	}
+	return chunkTexts(chunks)
+}
+
+// SplitChunksContext splits text like TextSplitter.SplitChunksContext and attaches the header
+// to every chunk. Offsets keep referring to text, and Tokens counts the header if it is
+// prepended. It fails if the header alone does not fit the chunk size.
+func (s *ContextualSplitter) SplitChunksContext(ctx context.Context, text string) ([]Chunk, error) {
+	splitter := s.splitter
+	prefix := s.header + "\n\n"
+	if s.prepend && s.header != "" {
+		size := splitter.chunkSize - splitter.countTokenFunc(prefix)
+		if size <= 0 {
+			return nil, fmt.Errorf("document header does not fit in chunk size %d", splitter.chunkSize)
+		}
+		splitter = splitter.withChunkSize(size)
+	}
+
+	chunks, err := splitter.SplitChunksContext(ctx, text)
+	if err != nil || s.header == "" {
+		return chunks, err
+	}
+	texts := make([]string, len(chunks))
+	for i := range chunks {
+		addContextPrefix(&chunks[i], s.header)
+		if s.prepend {
+			chunks[i].Text = prefix + chunks[i].Text
+		}
+		texts[i] = chunks[i].Text
+	}
+	if s.prepend {
+		for i, count := range splitter.countTokensBatch(texts) {
+			chunks[i].Tokens = count
+		}
+	}
+	return chunks, nil
+}
@@ -0,0 +1,37 @@
+package semchunk
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextualSplitter(t *testing.T) {
+	text := "One two three. Four five six. Seven eight nine."
+	splitter := newWordSplitter(t, 8, 0)
+	info := DocumentInfo{Title: "Numbers", URL: "https://example.com/n"}
+
+	chunks, err := NewContextualSplitter(splitter, info, true).SplitChunksContext(context.Background(), text)
+	assert.NoError(t, err)
+	header := "Title: Numbers\nURL: https://example.com/n\n\n"
+	// the header takes 4 of the 8 tokens
+	assert.Equal(t, []string{header + "One two three.", header + "Four five six.", header + "Seven eight nine."}, chunkTexts(chunks))
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, chunk.Tokens, 8)
+		assert.Equal(t, strings.TrimSuffix(header, "\n\n"), chunk.Metadata.ContextPrefix)
+		assert.Equal(t, chunk.Text[len(header):], text[chunk.Start:chunk.End])
+	}
+
+	attached := NewContextualSplitter(splitter, info, false)
+	assert.Equal(t, splitter.Split(text), attached.Split(text))
+	chunks, err = attached.SplitChunksContext(context.Background(), text)
+	assert.NoError(t, err)
+	assert.Equal(t, "Title: Numbers\nURL: https://example.com/n", chunks[0].Metadata.ContextPrefix)
+
+	assert.Equal(t, splitter.Split(text), NewContextualSplitter(splitter, DocumentInfo{}, true).Split(text))
+
+	_, err = NewContextualSplitter(newWordSplitter(t, 3, 0), info, true).SplitChunksContext(context.Background(), text)
+	assert.Error(t, err)
+}
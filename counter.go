@@ -0,0 +1,295 @@
+package semchunk
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// TokenCounter counts the number of tokens in a piece of text
+type TokenCounter interface {
+	CountTokens(text string) int
+}
+
+// BatchTokenCounter is a TokenCounter that can count several texts in one call.
+// The splitter uses it to count all splits of a level at once, which matters for
+// counters that are expensive per call, such as remote tokenization services.
+type BatchTokenCounter interface {
+	TokenCounter
+	CountTokensBatch(texts []string) []int
+}
+
+// TokenCounterFunc adapts an ordinary function to the TokenCounter interface
+type TokenCounterFunc func(text string) int
+
+func (f TokenCounterFunc) CountTokens(text string) int {
+	return f(text)
+}
+
+// TokenEncoder is implemented by counters that can turn text into token ids and back.
+// When the counter of a splitter implements it, chunks that still exceed the budget after
+// merging are cut by slicing their token sequence exactly at chunkSize tokens.
+type TokenEncoder interface {
+	Encode(text string) []int
+	Decode(tokens []int) string
+}
+
+// SizeUnit is the unit in which chunk sizes are measured
+type SizeUnit int
+
+const (
+	// SizeTokens measures chunks with the token counter of the splitter
+	SizeTokens SizeUnit = iota
+	// SizeRunes measures chunks in Unicode code points
+	SizeRunes
+	// SizeBytes measures chunks in bytes of UTF-8
+	SizeBytes
+	// SizeWords measures chunks in whitespace-separated words
+	SizeWords
+	// SizeDisplayCells measures chunks in monospace display cells, counting wide characters
+	// such as CJK as two cells and combining marks as none, for paginating chat and terminal UIs
+	SizeDisplayCells
+	// SizeReadingMillis measures chunks in the estimated time to read them in milliseconds, at
+	// 230 words or 260 CJK characters per minute
+	SizeReadingMillis
+)
+
+// sizeUnitNames are the names SizeUnit values are serialized as, in the order of the values
+var sizeUnitNames = []string{"tokens", "runes", "bytes", "words", "display_cells", "reading_millis"}
+
+func (u SizeUnit) MarshalText() ([]byte, error) {
+	return marshalEnum(u, sizeUnitNames)
+}
+
+func (u *SizeUnit) UnmarshalText(text []byte) error {
+	return unmarshalEnum(text, sizeUnitNames, u)
+}
+
+// WithSizeUnit measures chunk size and overlap in unit instead of tokens, for stores and APIs
+// limited by characters or bytes, or UIs limited by screen space or reading time. The token
+// counter passed to the constructor is ignored and may be nil, and Chunk.Tokens holds the
+// size in unit.
+func WithSizeUnit(unit SizeUnit) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.SizeUnit = unit
+	}
+}
+
+// counter returns the function measuring text in unit, nil for SizeTokens
+func (unit SizeUnit) counter() func(text string) int {
+	switch unit {
+	case SizeRunes:
+		return utf8.RuneCountInString
+	case SizeBytes:
+		return func(text string) int { return len(text) }
+	case SizeWords:
+		return func(text string) int { return len(strings.Fields(text)) }
+	case SizeDisplayCells:
+		return displayCells
+	case SizeReadingMillis:
+		return readingMillis
+	}
+	return nil
+}
+
+// WithCounterCheck makes the constructor run the token counter on a few probe texts and fail
+// if it returns a negative count, zero for a non-empty text, or fewer tokens for a text than
+// for a prefix of it. Misconfigured counters otherwise show up as bizarre chunking.
+func WithCounterCheck() func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.CheckCounter = true
+	}
+}
+
+// counterProbes are texts whose successive entries extend the previous one at a word boundary
+var counterProbes = [][]string{
+	{"a", "a quick", "a quick brown fox", "a quick brown fox jumps over the lazy dog.",
+		"a quick brown fox jumps over the lazy dog. It was not amused, and said so at length."},
+	{"你", "你好", "你好，世界", "你好，世界。今天天气很好，我们去公园散步吧。"},
+	{"x", "x := 1", "x := 1\nfmt.Println(x)", "x := 1\nfmt.Println(x)\n// see https://example.com/docs?id=42"},
+}
+
+// checkCounter runs count on the probe texts, see WithCounterCheck
+func checkCounter(count func(text string) int) error {
+	for _, probes := range counterProbes {
+		previous, previousText := 0, ""
+		for _, probe := range probes {
+			n := count(probe)
+			switch {
+			case n < 0:
+				return fmt.Errorf("token counter returned %d for %q", n, probe)
+			case n == 0:
+				return fmt.Errorf("token counter returned 0 for the non-empty text %q", probe)
+			case n < previous:
+				return fmt.Errorf("token counter returned %d for %q but %d for its prefix %q", n, probe, previous, previousText)
+			}
+			previous, previousText = n, probe
+		}
+	}
+	return nil
+}
+
+// NewTextSplitterWithCounter creates a new TextSplitter that counts tokens with counter.
+// If counter also implements BatchTokenCounter, splits are counted in batches, and if it
+// implements TokenEncoder, oversized chunks are cut at exact token boundaries.
+func NewTextSplitterWithCounter[K int | float32](chunkSize int, overlap K, counter TokenCounter, opts ...func(*TextSplitterOption)) (*TextSplitter, error) {
+	var countTokens func(text string) int
+	if counter != nil {
+		countTokens = counter.CountTokens
+	}
+	ts, err := NewTextSplitter(chunkSize, overlap, countTokens, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if ts.opts.SizeUnit != SizeTokens {
+		// the counter is replaced by the size unit
+		return ts, nil
+	}
+	if batch, ok := counter.(BatchTokenCounter); ok {
+		ts.batchCounter = batch
+	}
+	if encoder, ok := counter.(TokenEncoder); ok {
+		ts.encoder = encoder
+	}
+	return ts, nil
+}
+
+// fitTokens cuts chunks that exceed chunkSize at exact token boundaries, keeping the
+// configured overlap between the pieces of a cut chunk
+func (c *TextSplitter) fitTokens(chunks []Chunk, chunkSize int) []Chunk {
+	if c.encoder == nil {
+		return chunks
+	}
+
+	// cut pieces are chunkSize tokens, except the last one
+	stride := chunkSize - c.overlapFor(chunkSize)
+	if stride <= 0 {
+		stride = chunkSize
+	}
+
+	result := make([]Chunk, 0, len(chunks))
+	for _, chunk := range chunks {
+		if chunk.Metadata.Oversized {
+			// preserved matches are emitted whole on purpose
+			result = append(result, chunk)
+			continue
+		}
+		tokens := c.encoder.Encode(chunk.Text)
+		if len(tokens) <= chunkSize {
+			result = append(result, chunk)
+			continue
+		}
+		for start := 0; start < len(tokens); start += stride {
+			end := start + chunkSize
+			if end > len(tokens) {
+				end = len(tokens)
+			}
+			// offsets are exact as long as decoding token prefixes reproduces the text
+			piece := Chunk{
+				Text:     c.encoder.Decode(tokens[start:end]),
+				Start:    chunk.Start + len(c.encoder.Decode(tokens[:start])),
+				End:      chunk.Start + len(c.encoder.Decode(tokens[:end])),
+				Metadata: Metadata{Level: LevelToken, Depth: chunk.Metadata.Depth + 1},
+			}
+			if piece.End > chunk.End {
+				piece.End = chunk.End
+			}
+			if piece.Start > piece.End {
+				piece.Start = piece.End
+			}
+			result = append(result, piece)
+			if end == len(tokens) {
+				break
+			}
+		}
+	}
+	return result
+}
+
+// countTokensBatch counts the tokens of every text, using the batch counter if there is one
+func (c *TextSplitter) countTokensBatch(texts []string) []int {
+	if c.batchCounter != nil && len(texts) > 1 {
+		if counts := c.batchCounter.CountTokensBatch(texts); len(counts) == len(texts) {
+			return counts
+		}
+	}
+	counts := make([]int, len(texts))
+	for i, text := range texts {
+		counts[i] = c.countTokenFunc(text)
+	}
+	return counts
+}
+
+// WithTokenEstimation enables a two-pass mode for expensive counters. The whole text is
+// counted once and split sizes are estimated proportionally to their length in characters.
+// Only chunks whose estimate is within tolerance (a fraction of chunkSize) of the limit
+// are counted exactly, and those that turn out to be too large are split again exactly.
+func WithTokenEstimation(tolerance float64) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.EstimateTokens = true
+		opts.EstimationTolerance = tolerance
+	}
+}
+
+// splitEstimated splits text using proportional token estimates and verifies chunks near the limit
+func (c *TextSplitter) splitEstimated(text string, chunkSize int) []Chunk {
+	runes := utf8.RuneCountInString(text)
+	total := c.countTokenFunc(text)
+	if total <= chunkSize || runes == 0 {
+		return c.split(text, 0, chunkSize, 0)
+	}
+
+	ratio := float64(total) / float64(runes)
+	estimate := func(s string) int {
+		return int(math.Ceil(ratio * float64(utf8.RuneCountInString(s))))
+	}
+	estimator := *c
+	estimator.batchCounter = nil
+	estimator.countTokenFunc = estimate
+	chunks := estimator.split(text, 0, chunkSize, 0)
+
+	// count the chunks whose estimate is close to the limit
+	limit := float64(chunkSize) * (1 - c.opts.EstimationTolerance)
+	near := make([]int, 0)
+	nearTexts := make([]string, 0)
+	for i, chunk := range chunks {
+		if float64(estimate(chunk.Text)) >= limit {
+			near = append(near, i)
+			nearTexts = append(nearTexts, chunk.Text)
+		}
+	}
+	if len(near) == 0 {
+		return chunks
+	}
+	counts := c.countTokensBatch(nearTexts)
+
+	oversized := make(map[int]bool)
+	for j, i := range near {
+		if counts[j] > chunkSize {
+			oversized[i] = true
+		}
+	}
+	if len(oversized) == 0 {
+		return chunks
+	}
+
+	result := make([]Chunk, 0, len(chunks)+len(oversized))
+	for i, chunk := range chunks {
+		if oversized[i] {
+			result = append(result, c.split(chunk.Text, chunk.Start, chunkSize, 0)...)
+			continue
+		}
+		result = append(result, chunk)
+	}
+	return result
+}
@@ -0,0 +1,159 @@
+package semchunk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// batchWordCounter counts words and records how it was called
+type batchWordCounter struct {
+	calls      int
+	batchCalls int
+}
+
+func (c *batchWordCounter) CountTokens(text string) int {
+	c.calls++
+	return len(strings.Fields(text))
+}
+
+func (c *batchWordCounter) CountTokensBatch(texts []string) []int {
+	c.batchCalls++
+	counts := make([]int, len(texts))
+	for i, text := range texts {
+		counts[i] = len(strings.Fields(text))
+	}
+	return counts
+}
+
+func TestNewTextSplitterWithCounter(t *testing.T) {
+	var _ TokenCounter = NewSimpleTokenCounter(1)
+
+	counter := &batchWordCounter{}
+	splitter, err := NewTextSplitterWithCounter(4, 0, counter)
+	assert.NoError(t, err)
+
+	got := splitter.Split("one two three. four five six. seven eight nine.")
+	assert.Equal(t, []string{"one two three.", "four five six.", "seven eight nine."}, got)
+	assert.Equal(t, 1, counter.batchCalls)
+
+	plain, err := NewTextSplitterWithCounter(4, 0, TokenCounterFunc(func(text string) int {
+		return len(strings.Fields(text))
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, got, plain.Split("one two three. four five six. seven eight nine."))
+}
+
+func TestTokenEstimation(t *testing.T) {
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 200)
+
+	calls := 0
+	countWords := func(text string) int {
+		calls++
+		return len(strings.Fields(text))
+	}
+
+	exact, err := NewTextSplitter(50, 0, countWords)
+	assert.NoError(t, err)
+	exact.Split(text)
+	exactCalls := calls
+
+	calls = 0
+	estimated, err := NewTextSplitter(50, 0, countWords, WithTokenEstimation(0.1))
+	assert.NoError(t, err)
+	chunks := estimated.Split(text)
+
+	assert.Less(t, calls*4, exactCalls)
+	assert.Equal(t, text, strings.Join(chunks, " "))
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, len(strings.Fields(chunk)), 50)
+	}
+}
+
+// runeEncoder treats every rune as a token
+type runeEncoder struct{}
+
+func (runeEncoder) CountTokens(text string) int { return len([]rune(text)) }
+
+func (runeEncoder) Encode(text string) []int {
+	tokens := make([]int, 0, len(text))
+	for _, r := range text {
+		tokens = append(tokens, int(r))
+	}
+	return tokens
+}
+
+func (runeEncoder) Decode(tokens []int) string {
+	runes := make([]rune, len(tokens))
+	for i, token := range tokens {
+		runes[i] = rune(token)
+	}
+	return string(runes)
+}
+
+func TestExactTokenBoundaries(t *testing.T) {
+	splitter, err := NewTextSplitterWithCounter(4, 0, runeEncoder{}, WithPreservePatterns("abcdefghij"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abcd", "efgh", "ij"}, splitter.Split("abcdefghij"))
+
+	overlapping, err := NewTextSplitterWithCounter(4, 1, runeEncoder{}, WithPreservePatterns("abcdefghij"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abcd", "defg", "ghij"}, overlapping.Split("abcdefghij"))
+}
+
+func TestWithSizeUnit(t *testing.T) {
+	text := "Größe zählt. Ünïcode wörds hère."
+	tests := []struct {
+		unit      SizeUnit
+		chunkSize int
+		want      []string
+	}{
+		{SizeRunes, 20, []string{"Größe zählt.", "Ünïcode wörds hère."}},
+		{SizeBytes, 20, []string{"Größe zählt.", "Ünïcode wörds", "hère."}},
+		{SizeWords, 3, []string{"Größe zählt.", "Ünïcode wörds hère."}},
+	}
+	for _, tt := range tests {
+		splitter, err := NewTextSplitter(tt.chunkSize, 0, nil, WithSizeUnit(tt.unit))
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, splitter.Split(text))
+
+		splitter, err = NewTextSplitterWithCounter(tt.chunkSize, 0, nil, WithSizeUnit(tt.unit))
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, splitter.Split(text))
+	}
+
+	_, err := NewTextSplitter(10, 0, nil)
+	assert.Error(t, err)
+}
+
+func TestWithCounterCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   func(text string) int
+		wantErr string
+	}{
+		{"words", func(text string) int { return len(strings.Fields(text)) }, ""},
+		{"bytes", func(text string) int { return len(text) }, ""},
+		{"negative", func(text string) int { return -1 }, "returned -1"},
+		{"zero", func(text string) int { return 0 }, "returned 0"},
+		{"ascii only", func(text string) int {
+			return len(strings.FieldsFunc(text, func(r rune) bool { return r > 127 || r == ' ' }))
+		}, "returned 0"},
+		{"not monotonic", func(text string) int { return 100 - len(text) }, "for its prefix"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTextSplitter(10, 0, tt.count, WithCounterCheck())
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	// without the check the counter is not called
+	_, err := NewTextSplitter(10, 0, func(text string) int { return -1 })
+	assert.NoError(t, err)
+}
@@ -0,0 +1,90 @@
+// Package heuristic provides approximate token counters for when no tokenizer is available.
+//
+// The counters estimate tokens from the number of characters, using typical ratios of BPE
+// tokenizers such as cl100k_base: about 4 characters per token for English prose and about
+// 1.7 characters per token for Chinese, Japanese and Korean. They are far better than counting
+// words with strings.Fields, which sees a whole CJK paragraph as one word, but they are
+// estimates:
+//
+//   - English prose is usually within ±15% of the real count. Code, URLs, numbers and rare
+//     words use more tokens per character and are underestimated by up to 50%.
+//   - CJK text is usually within ±25%; kana-heavy Japanese and Korean vary more than Chinese.
+//   - Other scripts, such as Cyrillic, Arabic or Devanagari, often need two or three times the
+//     English estimate and should use a real tokenizer.
+//
+// Combine an estimate with a safety margin on the chunk size when chunks must stay below a hard
+// limit of the model.
+package heuristic
+
+import (
+	"fmt"
+	"math"
+	"unicode"
+)
+
+const (
+	// EnglishCharsPerToken is the number of characters per token assumed for English
+	EnglishCharsPerToken = 4.0
+	// CJKCharsPerToken is the number of characters per token assumed for CJK scripts
+	CJKCharsPerToken = 1.7
+)
+
+// Counter estimates token counts from character counts.
+// It implements semchunk.TokenCounter.
+type Counter struct {
+	charsPerToken    float64
+	cjkCharsPerToken float64
+}
+
+// English returns a counter assuming 4 characters per token for all text
+func English() *Counter {
+	return &Counter{charsPerToken: EnglishCharsPerToken, cjkCharsPerToken: EnglishCharsPerToken}
+}
+
+// CJK returns a counter assuming 1.7 characters per token for all text
+func CJK() *Counter {
+	return &Counter{charsPerToken: CJKCharsPerToken, cjkCharsPerToken: CJKCharsPerToken}
+}
+
+// Mixed returns a counter that counts CJK characters at 1.7 and all other characters at 4
+// characters per token, for documents mixing both
+func Mixed() *Counter {
+	return &Counter{charsPerToken: EnglishCharsPerToken, cjkCharsPerToken: CJKCharsPerToken}
+}
+
+// New returns a counter with custom ratios of characters per token for CJK and other text.
+// Both ratios must be positive and finite.
+func New(charsPerToken, cjkCharsPerToken float64) (*Counter, error) {
+	for _, ratio := range []struct {
+		name  string
+		value float64
+	}{
+		{"characters per token", charsPerToken},
+		{"CJK characters per token", cjkCharsPerToken},
+	} {
+		if !(ratio.value > 0) || math.IsInf(ratio.value, 1) {
+			return nil, fmt.Errorf("%s must be positive and finite, got %v", ratio.name, ratio.value)
+		}
+	}
+	return &Counter{charsPerToken: charsPerToken, cjkCharsPerToken: cjkCharsPerToken}, nil
+}
+
+// CountTokens returns the estimated number of tokens in text, rounded up, so that only the
+// empty text has no tokens
+func (c *Counter) CountTokens(text string) int {
+	other, cjk := 0, 0
+	for _, r := range text {
+		if isCJK(r) {
+			cjk++
+		} else {
+			other++
+		}
+	}
+	return int(math.Ceil(float64(other)/c.charsPerToken + float64(cjk)/c.cjkCharsPerToken))
+}
+
+// isCJK reports whether r is a Han, kana or Hangul character or CJK punctuation
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		r >= 0x3000 && r <= 0x303f || r >= 0xff00 && r <= 0xffef
+}
@@ -0,0 +1,49 @@
+package heuristic
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	semchunk "github.com/sanbaiw/semtxtsplitter"
+)
+
+func TestCountTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		counter *Counter
+		text    string
+		want    int
+	}{
+		{"empty", Mixed(), "", 0},
+		{"english", English(), "The quick brown fox jumps.", 7},
+		{"english rounds up", English(), "a", 1},
+		{"cjk", CJK(), "这是一个测试句子。", 6},
+		{"cjk counted as english", English(), "这是一个测试句子。", 3},
+		{"mixed", Mixed(), "Hello 世界", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.counter.CountTokens(tt.text))
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	counter, err := New(2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, counter.CountTokens("abcd字"))
+
+	for _, ratios := range [][2]float64{{0, 1}, {2, -1}, {math.NaN(), 1}, {2, math.Inf(1)}} {
+		_, err := New(ratios[0], ratios[1])
+		assert.ErrorContains(t, err, "must be positive and finite", ratios)
+	}
+}
+
+func TestCounterWithSplitter(t *testing.T) {
+	var _ semchunk.TokenCounter = Mixed()
+	splitter, err := semchunk.NewTextSplitterWithCounter(6, 0, Mixed())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"这是第一句话", "这是第二句话。"}, splitter.Split("这是第一句话。这是第二句话。"))
+}
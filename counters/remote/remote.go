@@ -0,0 +1,328 @@
+// Package remote provides a token counter backed by an HTTP tokenization endpoint.
+//
+// The endpoint receives a POST with a JSON body of the form
+//
+//	{"texts": ["first", "second"]}
+//
+// and must answer with the token count of every text, in the same order:
+//
+//	{"counts": [1, 1]}
+//
+// Texts are batched, requests run with a bounded concurrency, failed requests are
+// retried with exponential backoff and every count is cached in memory, so the
+// splitter does not make one HTTP call per split.
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Client counts tokens by calling a remote tokenization endpoint.
+// It implements semchunk.TokenCounter and semchunk.BatchTokenCounter.
+type Client struct {
+	endpoint    string
+	httpClient  *http.Client
+	header      http.Header
+	batchSize   int
+	concurrency int
+	maxRetries  int
+	backoff     time.Duration
+	timeout     time.Duration
+	cacheSize   int
+	fallback    func(text string) int
+
+	mu      sync.Mutex
+	cache   map[string]int
+	lastErr error
+}
+
+type Option func(*Client)
+
+// WithHTTPClient sets the http client used for requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithHeader adds a header, e.g. Authorization, to every request
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.header.Add(key, value)
+	}
+}
+
+// WithBatchSize sets the maximum number of texts sent in one request
+func WithBatchSize(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.batchSize = n
+		}
+	}
+}
+
+// WithConcurrency sets the maximum number of requests in flight
+func WithConcurrency(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.concurrency = n
+		}
+	}
+}
+
+// WithRetries sets how many times a failed request is retried and the initial backoff,
+// which doubles after every attempt
+func WithRetries(n int, backoff time.Duration) Option {
+	return func(c *Client) {
+		if n >= 0 {
+			c.maxRetries = n
+		}
+		c.backoff = backoff
+	}
+}
+
+// WithTimeout sets the timeout of a single request attempt
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithCacheSize sets the maximum number of cached counts, 0 disables the cache
+func WithCacheSize(n int) Option {
+	return func(c *Client) {
+		if n >= 0 {
+			c.cacheSize = n
+		}
+	}
+}
+
+// WithFallback sets the counter used when the endpoint can not be reached.
+// The default counts runes, which overestimates tokens and keeps chunks within budget.
+func WithFallback(fallback func(text string) int) Option {
+	return func(c *Client) {
+		c.fallback = fallback
+	}
+}
+
+// New creates a Client for the given endpoint URL
+func New(endpoint string, opts ...Option) *Client {
+	c := &Client{
+		endpoint:    endpoint,
+		httpClient:  http.DefaultClient,
+		header:      http.Header{},
+		batchSize:   64,
+		concurrency: 4,
+		maxRetries:  3,
+		backoff:     100 * time.Millisecond,
+		timeout:     10 * time.Second,
+		cacheSize:   100000,
+		fallback:    utf8.RuneCountInString,
+		cache:       make(map[string]int),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// CountTokens counts the tokens in text. If the endpoint fails, the fallback counter is used
+// and the error is available from Err.
+func (c *Client) CountTokens(text string) int {
+	return c.CountTokensBatch([]string{text})[0]
+}
+
+// CountTokensBatch counts the tokens of every text. Texts whose request failed are counted
+// with the fallback counter and the error is available from Err.
+func (c *Client) CountTokensBatch(texts []string) []int {
+	counts, err := c.Count(context.Background(), texts)
+	if err != nil {
+		c.mu.Lock()
+		c.lastErr = err
+		c.mu.Unlock()
+	}
+	return counts
+}
+
+// Err returns the most recent error encountered by CountTokens or CountTokensBatch
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastErr
+}
+
+// Count counts the tokens of every text, batching and caching requests.
+// Counts of texts whose request failed are filled in by the fallback counter
+// and the first error is returned.
+func (c *Client) Count(ctx context.Context, texts []string) ([]int, error) {
+	counts := make([]int, len(texts))
+
+	// look up the cache and collect the distinct texts that still need counting
+	pending := make(map[string][]int)
+	missing := make([]string, 0)
+	c.mu.Lock()
+	for i, text := range texts {
+		if text == "" {
+			continue
+		}
+		if n, ok := c.cache[text]; ok {
+			counts[i] = n
+			continue
+		}
+		if _, ok := pending[text]; !ok {
+			missing = append(missing, text)
+		}
+		pending[text] = append(pending[text], i)
+	}
+	c.mu.Unlock()
+
+	if len(missing) == 0 {
+		return counts, nil
+	}
+
+	var (
+		wg       sync.WaitGroup
+		errMu    sync.Mutex
+		firstErr error
+	)
+	sem := make(chan struct{}, c.concurrency)
+	for start := 0; start < len(missing); start += c.batchSize {
+		end := start + c.batchSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+		batch := missing[start:end]
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			batchCounts, err := c.requestWithRetry(ctx, batch)
+			if err != nil {
+				errMu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				errMu.Unlock()
+				batchCounts = make([]int, len(batch))
+				for i, text := range batch {
+					batchCounts[i] = c.fallback(text)
+				}
+			}
+
+			c.mu.Lock()
+			for i, text := range batch {
+				for _, idx := range pending[text] {
+					counts[idx] = batchCounts[i]
+				}
+				if err == nil {
+					c.store(text, batchCounts[i])
+				}
+			}
+			c.mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return counts, firstErr
+}
+
+// store caches a count, clearing the cache when it is full. Must be called with mu held.
+func (c *Client) store(text string, n int) {
+	if c.cacheSize == 0 {
+		return
+	}
+	if len(c.cache) >= c.cacheSize {
+		c.cache = make(map[string]int)
+	}
+	c.cache[text] = n
+}
+
+func (c *Client) requestWithRetry(ctx context.Context, texts []string) ([]int, error) {
+	backoff := c.backoff
+	var err error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		var counts []int
+		var retryable bool
+		counts, retryable, err = c.request(ctx, texts)
+		if err == nil {
+			return counts, nil
+		}
+		if !retryable {
+			break
+		}
+	}
+	return nil, err
+}
+
+type countRequest struct {
+	Texts []string `json:"texts"`
+}
+
+type countResponse struct {
+	Counts []int `json:"counts"`
+}
+
+// request sends one batch and reports whether a failure is worth retrying
+func (c *Client) request(ctx context.Context, texts []string) ([]int, bool, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	body, err := json.Marshal(countRequest{Texts: texts})
+	if err != nil {
+		return nil, false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, values := range c.header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, true, fmt.Errorf("remote: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return nil, retryable, fmt.Errorf("remote: unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+
+	var decoded countResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, true, fmt.Errorf("remote: decoding response: %w", err)
+	}
+	if len(decoded.Counts) != len(texts) {
+		return nil, false, fmt.Errorf("remote: got %d counts for %d texts", len(decoded.Counts), len(texts))
+	}
+	return decoded.Counts, false, nil
+}
@@ -0,0 +1,68 @@
+package remote
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newWordCountServer(t *testing.T, failures int32) (*httptest.Server, *int32) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if n <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var req countRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decoding request: %v", err)
+			return
+		}
+		resp := countResponse{Counts: make([]int, len(req.Texts))}
+		for i, text := range req.Texts {
+			resp.Counts[i] = len(strings.Fields(text))
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func TestCountTokensBatch(t *testing.T) {
+	srv, calls := newWordCountServer(t, 0)
+	c := New(srv.URL, WithBatchSize(2), WithConcurrency(1))
+
+	got := c.CountTokensBatch([]string{"a b", "c", "a b", "d e f", ""})
+	assert.Equal(t, []int{2, 1, 2, 3, 0}, got)
+	// three distinct non-empty texts in batches of two
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+	assert.NoError(t, c.Err())
+
+	// everything is cached now
+	assert.Equal(t, 3, c.CountTokens("d e f"))
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestCountTokensRetry(t *testing.T) {
+	srv, calls := newWordCountServer(t, 2)
+	c := New(srv.URL, WithRetries(3, time.Millisecond))
+
+	assert.Equal(t, 2, c.CountTokens("hello world"))
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+	assert.NoError(t, c.Err())
+}
+
+func TestCountTokensFallback(t *testing.T) {
+	srv, _ := newWordCountServer(t, 100)
+	c := New(srv.URL, WithRetries(1, time.Millisecond), WithFallback(func(text string) int { return 42 }))
+
+	assert.Equal(t, 42, c.CountTokens("hello world"))
+	assert.Error(t, c.Err())
+}
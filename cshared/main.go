@@ -0,0 +1,123 @@
+// Command cshared builds the splitter as a C shared library, so pipelines written in other
+// languages can call the same chunker through FFI:
+//
+//	go build -buildmode=c-shared -o libsemchunk.so ./cshared
+//
+// SplitText takes a JSON request and returns a JSON response, both NUL-terminated UTF-8
+// strings. The response must be released with FreeString. SplitTextWithCounter additionally
+// takes a callback counting the tokens of a text, so the caller's tokenizer is used.
+package main
+
+/*
+#include <stdlib.h>
+
+typedef int (*semchunk_token_counter)(const char *text, size_t len);
+
+static int semchunk_count(semchunk_token_counter counter, const char *text, size_t len) {
+	return counter(text, len);
+}
+*/
+import "C"
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+
+	semchunk "github.com/sanbaiw/semtxtsplitter"
+)
+
+// request is the JSON accepted by SplitText
+type request struct {
+	Text      string  `json:"text"`
+	ChunkSize int     `json:"chunk_size"`
+	Overlap   float32 `json:"overlap"`
+	// Counter is "words" (default) or "chars", ignored by SplitTextWithCounter
+	Counter      string   `json:"counter"`
+	Format       string   `json:"format"`
+	PreserveURLs bool     `json:"preserve_urls"`
+	Preserve     []string `json:"preserve_patterns"`
+}
+
+// response is the JSON returned by SplitText, Error is set instead of Chunks on failure
+type response struct {
+	Chunks []semchunk.Chunk `json:"chunks,omitempty"`
+	Error  string           `json:"error,omitempty"`
+}
+
+//export SplitText
+func SplitText(req *C.char) *C.char {
+	return C.CString(string(splitJSON([]byte(C.GoString(req)), nil)))
+}
+
+//export SplitTextWithCounter
+func SplitTextWithCounter(req *C.char, counter C.semchunk_token_counter) *C.char {
+	countTokens := func(text string) int {
+		if text == "" {
+			return int(C.semchunk_count(counter, nil, 0))
+		}
+		cs := C.CString(text)
+		defer C.free(unsafe.Pointer(cs))
+		return int(C.semchunk_count(counter, cs, C.size_t(len(text))))
+	}
+	return C.CString(string(splitJSON([]byte(C.GoString(req)), countTokens)))
+}
+
+//export FreeString
+func FreeString(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+// splitJSON decodes a request, splits its text and encodes the response. countTokens overrides
+// the counter named in the request if not nil.
+func splitJSON(data []byte, countTokens func(string) int) []byte {
+	var req request
+	if err := json.Unmarshal(data, &req); err != nil {
+		return encode(response{Error: "decoding request: " + err.Error()})
+	}
+	if req.ChunkSize == 0 {
+		req.ChunkSize = 100
+	}
+	if req.Format == "" {
+		req.Format = semchunk.FormatPlain
+	}
+	if countTokens == nil {
+		switch req.Counter {
+		case "", "words":
+			countTokens = func(text string) int { return len(strings.Fields(text)) }
+		case "chars":
+			countTokens = utf8.RuneCountInString
+		default:
+			return encode(response{Error: "unknown counter " + req.Counter})
+		}
+	}
+
+	var opts []func(*semchunk.TextSplitterOption)
+	if req.PreserveURLs {
+		opts = append(opts, semchunk.WithPreserveURLs(true))
+	}
+	if len(req.Preserve) > 0 {
+		opts = append(opts, semchunk.WithPreservePatterns(req.Preserve...))
+	}
+	splitter, err := semchunk.NewTextSplitter(req.ChunkSize, req.Overlap, countTokens, opts...)
+	if err != nil {
+		return encode(response{Error: err.Error()})
+	}
+	chunks, err := splitter.SplitFormat(context.Background(), req.Format, req.Text)
+	if err != nil {
+		return encode(response{Error: err.Error()})
+	}
+	return encode(response{Chunks: chunks})
+}
+
+func encode(resp response) []byte {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		data, _ = json.Marshal(response{Error: err.Error()})
+	}
+	return data
+}
+
+func main() {}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitJSON(t *testing.T) {
+	var resp response
+	data := splitJSON([]byte(`{"text":"one two three four. five six seven eight.","chunk_size":4}`), nil)
+	assert.NoError(t, json.Unmarshal(data, &resp))
+	assert.Empty(t, resp.Error)
+	if assert.Len(t, resp.Chunks, 2) {
+		assert.Equal(t, "one two three four.", resp.Chunks[0].Text)
+		assert.Equal(t, 4, resp.Chunks[1].Tokens)
+	}
+
+	resp = response{}
+	assert.NoError(t, json.Unmarshal(splitJSON([]byte(`{"text":"abc","counter":"bytes"}`), nil), &resp))
+	assert.Equal(t, "unknown counter bytes", resp.Error)
+
+	resp = response{}
+	assert.NoError(t, json.Unmarshal(splitJSON([]byte(`{`), nil), &resp))
+	assert.Contains(t, resp.Error, "decoding request")
+}
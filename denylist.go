@@ -0,0 +1,104 @@
+package semchunk
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// DenylistMode decides what happens to chunks matching the denylist
+type DenylistMode int
+
+const (
+	// DenylistMark lists the matches of the denylist in Metadata.Flags
+	DenylistMark DenylistMode = iota
+	// DenylistDrop leaves chunks matching the denylist out of the output
+	DenylistDrop
+)
+
+// denylistModeNames are the names DenylistMode values are serialized as, in the order of the values
+var denylistModeNames = []string{"mark", "drop"}
+
+func (m DenylistMode) MarshalText() ([]byte, error) {
+	return marshalEnum(m, denylistModeNames)
+}
+
+func (m *DenylistMode) UnmarshalText(text []byte) error {
+	return unmarshalEnum(text, denylistModeNames, m)
+}
+
+// WithDenylist marks or drops, according to mode, chunks containing any of keywords, matched
+// case-insensitively as whole words. Combined with WithDenylistPatterns, the mode of the
+// last option applies.
+func WithDenylist(mode DenylistMode, keywords ...string) func(*TextSplitterOption) {
+	patterns := make([]*regexp.Regexp, 0, len(keywords))
+	for _, keyword := range keywords {
+		pattern := regexp.QuoteMeta(keyword)
+		if r, _ := utf8.DecodeRuneInString(keyword); isASCIIWord(r) {
+			pattern = `\b` + pattern
+		}
+		if r, _ := utf8.DecodeLastRuneInString(keyword); isASCIIWord(r) {
+			pattern += `\b`
+		}
+		patterns = append(patterns, regexp.MustCompile(`(?i)`+pattern))
+	}
+	return WithDenylistPatterns(mode, patterns...)
+}
+
+// WithDenylistPatterns marks or drops, according to mode, chunks matching any of patterns,
+// for curating training or retrieval corpora while chunking
+func WithDenylistPatterns(mode DenylistMode, patterns ...*regexp.Regexp) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.DenylistMode = mode
+		opts.Denylist = append(opts.Denylist, patterns...)
+	}
+}
+
+// isASCIIWord reports whether r is a character matched by \w
+func isASCIIWord(r rune) bool {
+	return r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+// denylistMatches returns the distinct texts matching the denylist in text, lower-cased, in
+// the order of the denylist
+func (c *TextSplitter) denylistMatches(text string) []string {
+	var matches []string
+	seen := make(map[string]bool)
+	for _, pattern := range c.opts.Denylist {
+		for _, match := range pattern.FindAllString(text, -1) {
+			match = strings.ToLower(match)
+			if !seen[match] {
+				seen[match] = true
+				matches = append(matches, match)
+			}
+		}
+	}
+	return matches
+}
+
+// dropDenied leaves out the chunks matching the denylist if DenylistDrop is configured
+func (c *TextSplitter) dropDenied(chunks []Chunk) []Chunk {
+	if c.opts.DenylistMode != DenylistDrop || len(c.opts.Denylist) == 0 {
+		return chunks
+	}
+	kept := chunks[:0]
+	for _, chunk := range chunks {
+		if !c.denied(chunk.Text) {
+			kept = append(kept, chunk)
+		}
+	}
+	return kept
+}
+
+// denied reports whether text matches the denylist
+func (c *TextSplitter) denied(text string) bool {
+	for _, pattern := range c.opts.Denylist {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package semchunk
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDenylist(t *testing.T) {
+	text := "The weather is nice. Buy CHEAP pills now. Cheapest prices ever. Call 555-0199 today."
+
+	chunks := newWordSplitter(t, 4, 0, WithDenylist(DenylistMark, "cheap"),
+		WithDenylistPatterns(DenylistMark, regexp.MustCompile(`\d{3}-\d{4}`))).SplitChunks(text)
+	assert.Equal(t, []string{"The weather is nice.", "Buy CHEAP pills now.", "Cheapest prices ever.", "Call 555-0199 today."}, chunkTexts(chunks))
+	assert.Empty(t, chunks[0].Metadata.Flags)
+	assert.Equal(t, []string{"cheap"}, chunks[1].Metadata.Flags)
+	// keywords match whole words only
+	assert.Empty(t, chunks[2].Metadata.Flags)
+	assert.Equal(t, []string{"555-0199"}, chunks[3].Metadata.Flags)
+
+	dropping := newWordSplitter(t, 4, 0, WithDenylist(DenylistDrop, "cheap", "555-0199"))
+	assert.Equal(t, []string{"The weather is nice.", "Cheapest prices ever."}, dropping.Split(text))
+	assert.Equal(t, []string{"The weather is nice.", "Cheapest prices ever."}, chunkTexts(dropping.SplitChunks(text)))
+	for _, chunk := range dropping.SplitChunks(text) {
+		assert.Empty(t, chunk.Metadata.Flags)
+	}
+}
@@ -0,0 +1,25 @@
+package semchunk
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// closingQuotes are the quotation marks that end quoted speech
+const closingQuotes = `"'”’»」』`
+
+// continuesQuote reports whether text ends with quoted speech, such as `"Stop!"`, that rest
+// continues on the same line with a dialogue attribution such as `she cried.`, which starts
+// with a lowercase letter. The quote and its attribution form one sentence.
+func continuesQuote(text string, rest string) bool {
+	if strings.TrimRight(text, closingQuotes) == text {
+		return false
+	}
+	attribution := strings.TrimLeftFunc(rest, unicode.IsSpace)
+	if strings.ContainsAny(rest[:len(rest)-len(attribution)], "\r\n") {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(attribution)
+	return unicode.IsLower(r)
+}
@@ -0,0 +1,32 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContinuesQuote(t *testing.T) {
+	tests := []struct {
+		text, rest string
+		want       bool
+	}{
+		{`"Stop!"`, ` she cried.`, true},
+		{`“Are you sure?”`, ` asked Tom.`, true},
+		{`"Stop!"`, ` She ran.`, false},
+		{`"Stop!"`, "\nshe cried.", false},
+		{`Stop!`, ` she cried.`, false},
+		{`"Stop!"`, ``, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, continuesQuote(tt.text, tt.rest), tt.text+tt.rest)
+	}
+}
+
+func TestDialogueAttribution(t *testing.T) {
+	text := `"Stop!" she cried. "Why?" He ran.`
+	assert.Equal(t, []int{18, 25, 33}, sentenceEnds(text, sentenceTerminators))
+
+	chunks := newWordSplitter(t, 20, 0).SplitSentenceWindows(`"Stop!" she cried. He ran.`, 0)
+	assert.Equal(t, []string{`"Stop!" she cried.`, `He ran.`}, chunkTexts(chunks))
+}
@@ -0,0 +1,76 @@
+package semchunk
+
+import (
+	"math"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Reading speeds assumed by SizeReadingMillis, typical of silent reading by adults
+const (
+	readingWordsPerMinute    = 230
+	readingCJKCharsPerMinute = 260
+)
+
+// wideRanges are the ranges of wide and full-width runes outside the CJK scripts, such as
+// CJK punctuation, full-width forms and emoji
+var wideRanges = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x2e80, Hi: 0x303e, Stride: 1},
+		{Lo: 0x3041, Hi: 0x33ff, Stride: 1},
+		{Lo: 0xa000, Hi: 0xa4cf, Stride: 1},
+		{Lo: 0xf900, Hi: 0xfaff, Stride: 1},
+		{Lo: 0xfe30, Hi: 0xfe4f, Stride: 1},
+		{Lo: 0xff00, Hi: 0xff60, Stride: 1},
+		{Lo: 0xffe0, Hi: 0xffe6, Stride: 1},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f300, Hi: 0x1f64f, Stride: 1},
+		{Lo: 0x1f900, Hi: 0x1f9ff, Stride: 1},
+	},
+}
+
+// runeCells returns the number of terminal or monospace display cells r takes: 2 for wide
+// and full-width runes such as CJK characters, 0 for combining marks, format and control
+// characters, and 1 for all others. It approximates Unicode East Asian Width.
+func runeCells(r rune) int {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf, unicode.Cc):
+		return 0
+	case isUnspacedScript(r) || unicode.Is(wideRanges, r):
+		return 2
+	}
+	return 1
+}
+
+// displayCells returns the number of display cells of text, see SizeDisplayCells
+func displayCells(text string) int {
+	cells := 0
+	for _, r := range text {
+		cells += runeCells(r)
+	}
+	return cells
+}
+
+// readingMillis returns the estimated time in milliseconds to read text, see
+// SizeReadingMillis. Every CJK character is read as a word of its own.
+func readingMillis(text string) int {
+	words, cjk := 0, 0
+	inWord := false
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		switch {
+		case isUnspacedScript(r):
+			cjk++
+			inWord = false
+		case unicode.IsSpace(r):
+			inWord = false
+		case !inWord:
+			words++
+			inWord = true
+		}
+	}
+	ms := float64(words)*60000/readingWordsPerMinute + float64(cjk)*60000/readingCJKCharsPerMinute
+	return int(math.Ceil(ms))
+}
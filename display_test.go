@@ -0,0 +1,46 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisplayCells(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"hello", 5},
+		{"你好", 4},
+		{"こんにちは、世界", 16},
+		{"ｈｉ", 4},
+		{"e\u0301\u200b", 1},
+		{"ok 👍", 5},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, displayCells(tt.text), tt.text)
+	}
+}
+
+func TestReadingMillis(t *testing.T) {
+	assert.Equal(t, 0, readingMillis(" \n"))
+	// 23 words take 6 seconds at 230 words per minute
+	assert.Equal(t, 6000, readingMillis("a b c d e f g h i j k l m n o p q r s t u v w"))
+	// 13 characters take 3 seconds at 260 characters per minute
+	assert.Equal(t, 3000, readingMillis("一二三四五六七八九十一二三"))
+	assert.Equal(t, readingMillis("Go")+readingMillis("语言"), readingMillis("Go语言"))
+}
+
+func TestSizeDisplayCells(t *testing.T) {
+	splitter, err := NewTextSplitter(10, 0, nil, WithSizeUnit(SizeDisplayCells))
+	assert.NoError(t, err)
+	chunks := splitter.SplitChunks("你好世界。再见朋友。Hi there.")
+	assert.Equal(t, []string{"你好世界", "再见朋友", "Hi there."}, chunkTexts(chunks))
+	assert.Equal(t, 8, chunks[0].Tokens)
+
+	splitter, err = NewTextSplitter(2000, 0, nil, WithSizeUnit(SizeReadingMillis))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"One two three four five.", "Six seven eight nine ten."}, splitter.Split("One two three four five. Six seven eight nine ten."))
+}
@@ -0,0 +1,62 @@
+package semchunk
+
+import (
+	"regexp"
+	"strings"
+)
+
+// WithDividers makes lines that are only a horizontal rule or divider, such as "---", "***",
+// "____" or "====", split text before blank lines between paragraphs. Dividers are left out
+// of the chunks rather than emitted or glued to the text next to them, so no chunk spans a
+// divider. A line of "-" or "=" directly under text is a setext heading underline, and lines
+// in fenced code blocks are code, not dividers.
+func WithDividers(enabled bool) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.Dividers = enabled
+	}
+}
+
+// dividerRegex matches a line of at least three of the same divider character, optionally
+// separated by spaces. "~~~" opens a code block and "###" is an empty heading, so neither is
+// a divider.
+var dividerRegex = regexp.MustCompile(`^[ \t]*(?:(?:-[ \t]*){3,}|(?:\*[ \t]*){3,}|(?:_[ \t]*){3,}|(?:=[ \t]*){3,})$`)
+
+// splitDividers splits text at divider lines, leaving them out of the splits
+func splitDividers(text string) (textSplit, bool) {
+	ts := textSplit{splitter: "", isWhitespace: true, level: LevelParagraph, separate: true}
+	found := false
+	last := 0
+
+	lines := textLines(text)
+	fence := ""
+	for i, line := range lines {
+		// lines in fenced code blocks are code, not dividers
+		if fence != "" {
+			if closesFence(line.text, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if m := codeFenceRegex.FindStringSubmatch(line.text); m != nil {
+			fence = m[1]
+			continue
+		}
+		if !dividerRegex.MatchString(line.text) {
+			continue
+		}
+		if c := strings.TrimSpace(line.text)[0]; (c == '-' || c == '=') && i > 0 && strings.TrimSpace(lines[i-1].text) != "" {
+			// a setext heading underline
+			continue
+		}
+		ts.addTrimmed(text, last, line.start)
+		last, found = line.end, true
+	}
+	if !found {
+		return textSplit{}, false
+	}
+	ts.addTrimmed(text, last, len(text))
+	return ts, true
+}
@@ -0,0 +1,28 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDividers(t *testing.T) {
+	text := "Intro text is here.\n\n---\n\nSecond part is here.\n* * *\nThird part is here.\n\n====\n"
+
+	assert.Equal(t, []string{"Intro text is here.\n\n---", "Second part is here.\n* * *", "Third part is here.", "====\n"},
+		newWordSplitter(t, 10, 0).Split(text))
+
+	chunks := newWordSplitter(t, 10, 0, WithDividers(true)).SplitChunks(text)
+	assert.Equal(t, []string{"Intro text is here.", "Second part is here.", "Third part is here."}, chunkTexts(chunks))
+	for _, chunk := range chunks {
+		assert.Equal(t, chunk.Text, text[chunk.Start:chunk.End])
+	}
+
+	// setext heading underlines are not dividers
+	heading := "Title\n=====\n\nSome text here."
+	assert.Equal(t, []string{"Title\n=====", "Some text here."}, newWordSplitter(t, 3, 0, WithDividers(true)).Split(heading))
+
+	// nor are code fences and lines inside code blocks
+	code := "Config follows.\n\n~~~\nkey: value\n---\nkey: other\n~~~\n\n###\n\nThe end."
+	assert.Equal(t, []string{code}, newWordSplitter(t, 20, 0, WithDividers(true)).Split(code))
+}
@@ -0,0 +1,171 @@
+package semchunk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Document is a text together with information identifying where it came from
+type Document struct {
+	ID     string `json:"id"`
+	Title  string `json:"title,omitempty"`
+	Source string `json:"source,omitempty"`
+	Text   string `json:"text"`
+}
+
+// ContextRequest is the payload handed to a ContextGenerator for one chunk
+type ContextRequest struct {
+	Title  string
+	Source string
+	// Excerpt is the part of the document surrounding the chunk
+	Excerpt string
+	// Section is the text of the Markdown section the chunk starts in, with its heading, and
+	// HeadingPath holds the headings of the section and its ancestors, outermost first
+	Section     string
+	HeadingPath []string
+	Chunk       Chunk
+}
+
+// ContextGenerator returns a short text situating a chunk within its document, as in
+// contextual retrieval, where a language model writes a prefix for every chunk before indexing
+type ContextGenerator func(ctx context.Context, req ContextRequest) (string, error)
+
+// WithContextGenerator sets the generator SplitDocument calls for every chunk. The generated
+// text is stored in Metadata.ContextPrefix and, if prepend is true, also prepended to the chunk
+// text; WithContextBudget keeps such chunks within the chunk size. Offsets keep referring to
+// the original document.
+func WithContextGenerator(generator ContextGenerator, prepend bool) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.ContextGenerator = generator
+		opts.PrependContext = prepend
+	}
+}
+
+// WithContextExcerpt sets how many bytes of the document before and after a chunk are
+// handed to the ContextGenerator, default 2000
+func WithContextExcerpt(bytes int) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.ContextExcerptBytes = bytes
+	}
+}
+
+// WithContextBudget reserves tokens of the chunk size for the text prepended by the
+// ContextGenerator, including the blank line after it: chunks are split that much smaller, and
+// SplitDocument fails if a generated prefix is larger
+func WithContextBudget(tokens int) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.ContextBudget = tokens
+	}
+}
+
+const defaultContextExcerptBytes = 2000
+
+// SplitDocument splits the text of doc, tags every chunk with the document ID and runs the
+// configured annotator and context generator. Tokens counts the generated context if it is
+// prepended.
+func (c *TextSplitter) SplitDocument(ctx context.Context, doc Document) ([]Chunk, error) {
+	splitter := c
+	if budget := c.opts.ContextBudget; c.opts.ContextGenerator != nil && c.opts.PrependContext && budget > 0 {
+		if budget >= c.chunkSize {
+			return nil, fmt.Errorf("context budget of %d tokens does not fit in chunk size %d", budget, c.chunkSize)
+		}
+		splitter = c.withChunkSize(c.chunkSize - budget)
+	}
+	chunks, err := splitter.SplitChunksContext(ctx, doc.Text)
+	if err != nil {
+		return nil, err
+	}
+	for i := range chunks {
+		chunks[i].Metadata.DocumentID = doc.ID
+	}
+	if c.opts.ContextGenerator == nil {
+		return chunks, nil
+	}
+
+	excerptBytes := c.opts.ContextExcerptBytes
+	if excerptBytes <= 0 {
+		excerptBytes = defaultContextExcerptBytes
+	}
+	sections := parseMarkdown(doc.Text).sections
+	err = forEachChunk(ctx, chunks, c.opts.AnnotatorConcurrency, func(ctx context.Context, chunk *Chunk) error {
+		section := sections[sectionAt(sections, chunk.Start)]
+		prefix, err := c.opts.ContextGenerator(ctx, ContextRequest{
+			Title:       doc.Title,
+			Source:      doc.Source,
+			Excerpt:     excerpt(doc.Text, chunk.Start, chunk.End, excerptBytes),
+			Section:     doc.Text[section.Start:section.End],
+			HeadingPath: section.Path,
+			Chunk:       *chunk,
+		})
+		if err != nil {
+			return fmt.Errorf("generating context for chunk %d: %w", chunk.Index, err)
+		}
+		addContextPrefix(chunk, prefix)
+		if c.opts.PrependContext && prefix != "" {
+			prefix += "\n\n"
+			if budget := c.opts.ContextBudget; budget > 0 {
+				if n := c.countTokenFunc(prefix); n > budget {
+					return fmt.Errorf("context for chunk %d of %d tokens exceeds the context budget of %d", chunk.Index, n, budget)
+				}
+			}
+			chunk.Text = prefix + chunk.Text
+			chunk.Tokens = c.countTokenFunc(chunk.Text)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+// sectionAt returns the index of the last of sections, which cover the text in order, that
+// starts at or before offset
+func sectionAt(sections []Section, offset int) int {
+	i := sort.Search(len(sections), func(i int) bool { return sections[i].Start > offset })
+	if i > 0 {
+		i--
+	}
+	return i
+}
+
+// excerpt returns the text from about n bytes before start to about n bytes after end,
+// widened to the nearest whitespace so words are not cut
+func excerpt(text string, start, end, n int) string {
+	lo, hi := start-n, end+n
+	if lo <= 0 {
+		lo = 0
+	} else {
+		for lo > 0 {
+			r, size := utf8.DecodeLastRuneInString(text[:lo])
+			if unicode.IsSpace(r) {
+				break
+			}
+			lo -= size
+		}
+	}
+	if hi >= len(text) {
+		hi = len(text)
+	} else {
+		for hi < len(text) {
+			r, size := utf8.DecodeRuneInString(text[hi:])
+			if unicode.IsSpace(r) {
+				break
+			}
+			hi += size
+		}
+	}
+	return text[lo:hi]
+}
@@ -0,0 +1,63 @@
+package semchunk
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitDocumentContextGenerator(t *testing.T) {
+	doc := Document{
+		ID:    "doc-1",
+		Title: "Fruit",
+		Text:  "Apples are red. Bananas are yellow. Cherries are dark.",
+	}
+	generator := func(ctx context.Context, req ContextRequest) (string, error) {
+		assert.Contains(t, req.Excerpt, req.Chunk.Text)
+		return fmt.Sprintf("From %s, chunk %d.", req.Title, req.Chunk.Index), nil
+	}
+
+	splitter := newWordSplitter(t, 3, 0, WithContextGenerator(generator, true), WithContextExcerpt(10))
+	chunks, err := splitter.SplitDocument(context.Background(), doc)
+	assert.NoError(t, err)
+	assert.Len(t, chunks, 3)
+
+	assert.Equal(t, "doc-1", chunks[1].Metadata.DocumentID)
+	assert.Equal(t, "From Fruit, chunk 1.", chunks[1].Metadata.ContextPrefix)
+	assert.Equal(t, "From Fruit, chunk 1.\n\nBananas are yellow.", chunks[1].Text)
+	assert.Equal(t, "Bananas are yellow.", doc.Text[chunks[1].Start:chunks[1].End])
+	assert.Equal(t, 7, chunks[1].Tokens)
+}
+
+func TestWithContextBudget(t *testing.T) {
+	doc := Document{Text: "# Fruit\n\nApples are red. Bananas are yellow.\n\n## Cherries\n\nCherries are dark red."}
+	generator := func(ctx context.Context, req ContextRequest) (string, error) {
+		assert.Contains(t, req.Section, req.Chunk.Text)
+		return "About " + req.HeadingPath[len(req.HeadingPath)-1], nil
+	}
+
+	splitter := newWordSplitter(t, 6, 0, WithContextGenerator(generator, true), WithContextBudget(2))
+	chunks, err := splitter.SplitDocument(context.Background(), doc)
+	assert.NoError(t, err)
+	assert.Equal(t, "About Cherries\n\nCherries are dark red.", chunks[len(chunks)-1].Text)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, chunk.Tokens, 6, chunk.Text)
+	}
+
+	// the generated context does not fit the budget
+	splitter = newWordSplitter(t, 6, 0, WithContextGenerator(generator, true), WithContextBudget(1))
+	_, err = splitter.SplitDocument(context.Background(), doc)
+	assert.ErrorContains(t, err, "exceeds the context budget of 1")
+
+	splitter = newWordSplitter(t, 6, 0, WithContextGenerator(generator, true), WithContextBudget(6))
+	_, err = splitter.SplitDocument(context.Background(), doc)
+	assert.Error(t, err)
+}
+
+func TestExcerpt(t *testing.T) {
+	text := "one two three four five"
+	assert.Equal(t, "two three four", excerpt(text, 8, 13, 3))
+	assert.Equal(t, text, excerpt(text, 8, 13, 100))
+}
@@ -0,0 +1,139 @@
+package semchunk
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadDOCX extracts the text of a DOCX file as Markdown. Paragraphs with a heading or title
+// style become Markdown headings, list paragraphs become list items, and all other paragraphs
+// are separated by blank lines. Formatting, images and comments are dropped. r holds the
+// size bytes of the file; the files package reads DOCX files by path.
+func ReadDOCX(r io.ReaderAt, size int64) (string, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return "", err
+	}
+
+	for _, f := range archive.File {
+		if f.Name != "word/document.xml" {
+			continue
+		}
+		doc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		defer doc.Close()
+		return docxText(doc)
+	}
+	return "", fmt.Errorf("word/document.xml not found")
+}
+
+// SplitDOCX extracts the text of a DOCX file with ReadDOCX and splits it with SplitMarkdown.
+// Chunk offsets refer to the extracted text.
+func (c *TextSplitter) SplitDOCX(r io.ReaderAt, size int64) ([]Chunk, error) {
+	text, err := ReadDOCX(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return c.SplitMarkdownContext(context.Background(), text)
+}
+
+// docxText converts the WordprocessingML of a document body to Markdown
+func docxText(r io.Reader) (string, error) {
+	var (
+		paragraphs []string
+		text       strings.Builder
+		level      int
+		listItem   bool
+		inText     bool
+	)
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			switch tok.Name.Local {
+			case "p":
+				text.Reset()
+				level, listItem = 0, false
+			case "pStyle":
+				level = docxHeadingLevel(xmlAttr(tok, "val"))
+			case "outlineLvl":
+				if n, err := strconv.Atoi(xmlAttr(tok, "val")); err == nil && n < 9 && level == 0 {
+					level = n + 1
+				}
+			case "numPr":
+				listItem = true
+			case "t":
+				inText = true
+			case "tab":
+				text.WriteString("\t")
+			case "br", "cr":
+				text.WriteString("\n")
+			}
+		case xml.EndElement:
+			switch tok.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				paragraph := strings.TrimSpace(text.String())
+				if paragraph == "" {
+					continue
+				}
+				if level > 6 {
+					level = 6
+				}
+				switch {
+				case level > 0:
+					paragraph = strings.Repeat("#", level) + " " + strings.ReplaceAll(paragraph, "\n", " ")
+				case listItem:
+					paragraph = "- " + paragraph
+				}
+				paragraphs = append(paragraphs, paragraph)
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(tok)
+			}
+		}
+	}
+	return strings.Join(paragraphs, "\n\n"), nil
+}
+
+// docxHeadingLevel returns the heading level of a paragraph style, or 0 if it is not a heading
+func docxHeadingLevel(style string) int {
+	if strings.EqualFold(style, "Title") {
+		return 1
+	}
+	lower := strings.ToLower(style)
+	if !strings.HasPrefix(lower, "heading") {
+		return 0
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(lower[len("heading"):]))
+	if err != nil || n < 1 {
+		return 0
+	}
+	return n
+}
+
+func xmlAttr(el xml.StartElement, name string) string {
+	for _, attr := range el.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,111 @@
+package semchunk
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// ElideOption configures Elide
+type ElideOption struct {
+	// Marker replaces the elided middle, default "[...]"
+	Marker string
+	// HeadRatio is the share of the budget given to the head, default 0.5
+	HeadRatio float64
+}
+
+// WithElisionMarker sets the marker that replaces the elided middle, default "[...]"
+func WithElisionMarker(marker string) func(*ElideOption) {
+	return func(opts *ElideOption) {
+		if opts == nil {
+			opts = &ElideOption{}
+		}
+		opts.Marker = marker
+	}
+}
+
+// WithHeadRatio sets the share of the budget given to the head, default 0.5; the tail gets
+// the rest
+func WithHeadRatio(ratio float64) func(*ElideOption) {
+	return func(opts *ElideOption) {
+		if opts == nil {
+			opts = &ElideOption{}
+		}
+		opts.HeadRatio = ratio
+	}
+}
+
+// elideUnits is the number of pieces of the budget Elide cuts text into, so head and tail
+// end at semantic boundaries without wasting much of the budget
+const elideUnits = 8
+
+// sentenceBacktrack is the number of pieces Elide gives up to cut at a sentence end
+const sentenceBacktrack = 3
+
+// Elide returns text unchanged if it fits within budget tokens. Otherwise it keeps as much of
+// the head and the tail as fits and replaces the middle by a marker on a line of its own.
+// Head and tail end at the boundaries the splitter would choose for chunks of about an
+// eighth of the budget, preferably at the end of a sentence.
+func (c *TextSplitter) Elide(text string, budget int, opts ...func(*ElideOption)) string {
+	elideOpts := &ElideOption{Marker: "[...]", HeadRatio: 0.5}
+	for _, opt := range opts {
+		opt(elideOpts)
+	}
+	if c.countTokenFunc(text) <= budget {
+		return text
+	}
+
+	marker := "\n\n" + elideOpts.Marker + "\n\n"
+	available := budget - c.countTokenFunc(marker)
+	if available <= 0 {
+		return ""
+	}
+
+	units := *c
+	units.chunkSize = available / elideUnits
+	if units.chunkSize < 1 {
+		units.chunkSize = 1
+	}
+	units.overlap = 0
+	unitOpts := *c.opts
+	unitOpts.RelativeOverlap = 0
+	units.opts = &unitOpts
+	spans := units.SplitSpans(text)
+	if len(spans) == 0 {
+		return ""
+	}
+
+	// counts grow with the prefix or suffix, so search for the longest one that fits
+	headBudget := int(float64(available) * elideOpts.HeadRatio)
+	head := sort.Search(len(spans), func(i int) bool {
+		return c.countTokenFunc(text[:spans[i][1]]) > headBudget
+	})
+	// rather end the head at a sentence end a little earlier
+	terminators := c.splitRules().terminators()
+	for i := head; i > 0 && i > head-sentenceBacktrack; i-- {
+		if endsSentence(text[:spans[i-1][1]], terminators) {
+			head = i
+			break
+		}
+	}
+	headEnd := 0
+	if head > 0 {
+		headEnd = spans[head-1][1]
+	}
+	tailBudget := available - c.countTokenFunc(text[:headEnd])
+	tail := sort.Search(len(spans)-head, func(i int) bool {
+		return c.countTokenFunc(text[spans[len(spans)-1-i][0]:]) > tailBudget
+	})
+	for i := tail; i > 0 && i > tail-sentenceBacktrack; i-- {
+		if endsSentence(strings.TrimRightFunc(text[:spans[len(spans)-i][0]], unicode.IsSpace), terminators) {
+			tail = i
+			break
+		}
+	}
+	tailStart := len(text)
+	if tail > 0 {
+		tailStart = spans[len(spans)-tail][0]
+	}
+
+	return text[:headEnd] + marker + text[tailStart:]
+}
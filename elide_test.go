@@ -0,0 +1,29 @@
+package semchunk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElide(t *testing.T) {
+	splitter := newWordSplitter(t, 100, 10)
+	var sentences []string
+	for _, n := range []string{"First", "Second", "Third", "Fourth", "Fifth", "Sixth"} {
+		sentences = append(sentences, n+" sentence is right here in the text.")
+	}
+	text := strings.Join(sentences, " ")
+	head := func(n int) string { return strings.Join(sentences[:n], " ") }
+	tail := func(n int) string { return strings.Join(sentences[len(sentences)-n:], " ") }
+
+	assert.Equal(t, text, splitter.Elide(text, 48))
+	assert.Equal(t, head(2)+"\n\n[...]\n\n"+tail(2), splitter.Elide(text, 40))
+	assert.Equal(t, head(1)+"\n\n[...]\n\n"+tail(3), splitter.Elide(text, 40, WithHeadRatio(0.25)))
+	assert.Equal(t, head(2)+"\n\n<snip>\n\n"+tail(2), splitter.Elide(text, 40, WithElisionMarker("<snip>")))
+	assert.Equal(t, "", splitter.Elide(text, 1))
+
+	got := splitter.Elide(strings.Repeat("word ", 1000), 100)
+	assert.LessOrEqual(t, len(strings.Fields(got)), 100)
+	assert.Greater(t, len(strings.Fields(got)), 80)
+}
@@ -0,0 +1,41 @@
+package semchunk
+
+import (
+	"regexp"
+	"strings"
+)
+
+// enumerationMarker matches the marker of an enumerated list item on its own: "1.", "a)",
+// "iv.", "(2)", "一、", "（一）" or a circled number such as "①"
+var enumerationMarker = regexp.MustCompile(`^(?:` +
+	`(?:\d{1,3}|[a-zA-Z]|[ivxlcdm]{1,6}|[IVXLCDM]{1,6})[.)]|` +
+	`\((?:\d{1,3}|[a-zA-Z]|[ivxlcdm]{1,6}|[IVXLCDM]{1,6})\)|` +
+	`[一二三四五六七八九十]{1,3}[、．.]|` +
+	`[(（](?:\d{1,3}|[一二三四五六七八九十]{1,3})[)）]|` +
+	`[\x{2460}-\x{2473}\x{2776}-\x{2793}]` +
+	`)$`)
+
+// maxMarkerBytes is the length of the longest marker enumerationMarker matches
+const maxMarkerBytes = 15
+
+// isEnumerationMarker reports whether text, ignoring surrounding whitespace, is only the
+// marker of a list item
+func isEnumerationMarker(text string) bool {
+	text = strings.TrimSpace(text)
+	return len(text) <= maxMarkerBytes && enumerationMarker.MatchString(text)
+}
+
+// attachMarkers joins the parts that are only a list item marker to the part after them, so
+// a marker such as "1." is not split from its item as if it were a sentence
+func attachMarkers(parts []string, splitter string) []string {
+	attached := make([]string, 0, len(parts))
+	for i := 0; i < len(parts); i++ {
+		part := parts[i]
+		for i+1 < len(parts) && isEnumerationMarker(part) {
+			i++
+			part += splitter + parts[i]
+		}
+		attached = append(attached, part)
+	}
+	return attached
+}
@@ -0,0 +1,29 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsEnumerationMarker(t *testing.T) {
+	for _, marker := range []string{"1.", "12)", "a)", "B.", "iv.", "(2)", "(c)", "一、", "（一）", "(十二)", "①", " 3. "} {
+		assert.True(t, isEnumerationMarker(marker), marker)
+	}
+	for _, text := range []string{"end.", "1.5", "ab)", "2020.", "Done!", "(note)", ""} {
+		assert.False(t, isEnumerationMarker(text), text)
+	}
+}
+
+func TestEnumerationMarkers(t *testing.T) {
+	splitter := newWordSplitter(t, 4, 0)
+	assert.Equal(t, []string{
+		"1. Install the package.",
+		"2. Run the tests.",
+	}, splitter.Split("1. Install the package. 2. Run the tests."))
+
+	assert.Equal(t, []string{"① 总则 第一条", "② 附则"}, newWordSplitter(t, 3, 0).Split("① 总则 第一条 ② 附则"))
+
+	assert.Equal(t, []int{23, 41}, sentenceEnds("1. Install the package. 2. Run the tests.", sentenceTerminators))
+	assert.Equal(t, []int{12}, sentenceEnds("(a) Read it. b)", sentenceTerminators))
+}
@@ -0,0 +1,198 @@
+package semchunk
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ReadEPUB extracts the text of an EPUB file as Markdown, reading its content documents in
+// spine order. HTML headings become Markdown headings, preformatted text becomes fenced code
+// blocks, and other block elements become paragraphs. r holds the size bytes of the file;
+// the files package reads EPUB files by path.
+func ReadEPUB(r io.ReaderAt, size int64) (string, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return "", err
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	var container struct {
+		Rootfiles []struct {
+			FullPath string `xml:"full-path,attr"`
+		} `xml:"rootfiles>rootfile"`
+	}
+	if err := decodeZipXML(files, "META-INF/container.xml", &container); err != nil {
+		return "", err
+	}
+	if len(container.Rootfiles) == 0 {
+		return "", fmt.Errorf("no rootfile in META-INF/container.xml")
+	}
+	opfPath := container.Rootfiles[0].FullPath
+
+	var pkg struct {
+		Items []struct {
+			ID   string `xml:"id,attr"`
+			Href string `xml:"href,attr"`
+		} `xml:"manifest>item"`
+		Spine []struct {
+			IDRef string `xml:"idref,attr"`
+		} `xml:"spine>itemref"`
+	}
+	if err := decodeZipXML(files, opfPath, &pkg); err != nil {
+		return "", err
+	}
+	hrefs := make(map[string]string, len(pkg.Items))
+	for _, item := range pkg.Items {
+		hrefs[item.ID] = item.Href
+	}
+
+	documents := make([]string, 0, len(pkg.Spine))
+	for _, itemref := range pkg.Spine {
+		href, ok := hrefs[itemref.IDRef]
+		if !ok {
+			continue
+		}
+		if unescaped, err := url.PathUnescape(href); err == nil {
+			href = unescaped
+		}
+		f, ok := files[path.Join(path.Dir(opfPath), href)]
+		if !ok {
+			return "", fmt.Errorf("spine item %s not found", href)
+		}
+		doc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		text, err := htmlToMarkdown(doc)
+		doc.Close()
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", href, err)
+		}
+		if text != "" {
+			documents = append(documents, text)
+		}
+	}
+	return strings.Join(documents, "\n\n"), nil
+}
+
+// SplitEPUB extracts the text of an EPUB file with ReadEPUB and splits it with SplitMarkdown.
+// Chunk offsets refer to the extracted text.
+func (c *TextSplitter) SplitEPUB(r io.ReaderAt, size int64) ([]Chunk, error) {
+	text, err := ReadEPUB(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return c.SplitMarkdownContext(context.Background(), text)
+}
+
+func decodeZipXML(files map[string]*zip.File, name string, v any) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%s not found", name)
+	}
+	r, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return xml.NewDecoder(r).Decode(v)
+}
+
+// htmlBlocks are the elements that start a new paragraph
+var htmlBlocks = map[string]bool{
+	"p": true, "div": true, "section": true, "article": true, "blockquote": true,
+	"li": true, "dt": true, "dd": true, "tr": true, "figcaption": true, "caption": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "pre": true,
+	"body": true, "table": true, "ul": true, "ol": true, "dl": true, "hr": true,
+}
+
+// htmlToMarkdown converts an (X)HTML document to Markdown text
+func htmlToMarkdown(r io.Reader) (string, error) {
+	dec := xml.NewDecoder(r)
+	dec.Strict = false
+	dec.AutoClose = xml.HTMLAutoClose
+	dec.Entity = xml.HTMLEntity
+
+	var (
+		paragraphs []string
+		text       strings.Builder
+		prefix     string
+		skip       int
+		pre        int
+	)
+	flush := func() {
+		var paragraph string
+		if pre > 0 {
+			paragraph = "```\n" + strings.Trim(text.String(), "\n") + "\n```"
+		} else if content := strings.Join(strings.Fields(text.String()), " "); content != "" {
+			paragraph = prefix + content
+		}
+		if strings.TrimSpace(strings.Trim(paragraph, "`")) != "" {
+			paragraphs = append(paragraphs, paragraph)
+		}
+		text.Reset()
+		prefix = ""
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			name := strings.ToLower(tok.Name.Local)
+			switch {
+			case name == "head" || name == "script" || name == "style":
+				skip++
+			case name == "br":
+				text.WriteString("\n")
+			case htmlBlocks[name] && pre == 0:
+				flush()
+				if len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6' {
+					prefix = strings.Repeat("#", int(name[1]-'0')) + " "
+				} else if name == "li" {
+					prefix = "- "
+				}
+				if name == "pre" {
+					pre++
+				}
+			case name == "pre":
+				pre++
+			}
+		case xml.EndElement:
+			name := strings.ToLower(tok.Name.Local)
+			switch {
+			case name == "head" || name == "script" || name == "style":
+				skip--
+			case name == "pre":
+				if pre == 1 {
+					flush()
+				}
+				pre--
+			case htmlBlocks[name] && pre == 0:
+				flush()
+			}
+		case xml.CharData:
+			if skip == 0 {
+				text.Write(tok)
+			}
+		}
+	}
+	flush()
+	return strings.Join(paragraphs, "\n\n"), nil
+}
@@ -0,0 +1,145 @@
+// Package eval measures how well a chunking configuration keeps relevant passages together.
+//
+// A test set pairs queries with the span of a document that answers them. A span is covered
+// when a single chunk contains all of it, so a retriever returning that chunk sees the whole
+// answer. Evaluate reports the share of covered spans for every configuration, which makes it
+// easy to compare chunk sizes and overlaps on a corpus before indexing it.
+package eval
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	semchunk "github.com/sanbaiw/semtxtsplitter"
+)
+
+// Document is one text of the corpus
+type Document struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+// Query is a question together with the span of a document relevant to it. The span is given
+// by byte offsets or, if End is zero, by its text, which is looked up in the document.
+type Query struct {
+	Query      string `json:"query"`
+	DocumentID string `json:"document_id"`
+	Start      int    `json:"start,omitempty"`
+	End        int    `json:"end,omitempty"`
+	Text       string `json:"text,omitempty"`
+}
+
+// Setting is one chunking configuration to evaluate
+type Setting struct {
+	ChunkSize int     `json:"chunk_size"`
+	Overlap   float32 `json:"overlap"`
+}
+
+// Result is the outcome of evaluating one Setting
+type Result struct {
+	Setting
+	// Chunks is the number of chunks over the whole corpus
+	Chunks int `json:"chunks"`
+	// Covered is the number of spans contained in a single chunk
+	Covered int `json:"covered"`
+	Spans   int `json:"spans"`
+	// Coverage is Covered / Spans
+	Coverage float64 `json:"coverage"`
+	// Fragments is the mean number of chunks a span overlaps
+	Fragments float64 `json:"fragments"`
+	// MeanTokens is the mean token count of the chunks
+	MeanTokens float64 `json:"mean_tokens"`
+}
+
+// Grid returns every combination of the chunk sizes and overlaps
+func Grid(chunkSizes []int, overlaps []float32) []Setting {
+	settings := make([]Setting, 0, len(chunkSizes)*len(overlaps))
+	for _, size := range chunkSizes {
+		for _, overlap := range overlaps {
+			settings = append(settings, Setting{ChunkSize: size, Overlap: overlap})
+		}
+	}
+	return settings
+}
+
+type span struct{ start, end int }
+
+// Evaluate splits the corpus with every setting, using countTokens and opts for all of them,
+// and reports how many query spans land within a single chunk
+func Evaluate(ctx context.Context, docs []Document, queries []Query, countTokens func(string) int, settings []Setting, opts ...func(*semchunk.TextSplitterOption)) ([]Result, error) {
+	spans, err := resolveSpans(docs, queries)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(settings))
+	for _, setting := range settings {
+		splitter, err := semchunk.NewTextSplitter(setting.ChunkSize, setting.Overlap, countTokens, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("chunk size %d, overlap %g: %w", setting.ChunkSize, setting.Overlap, err)
+		}
+		result := Result{Setting: setting}
+		tokens, fragments := 0, 0
+		for _, doc := range docs {
+			chunks, err := splitter.SplitChunksContext(ctx, doc.Text)
+			if err != nil {
+				return nil, fmt.Errorf("document %s: %w", doc.ID, err)
+			}
+			result.Chunks += len(chunks)
+			for _, chunk := range chunks {
+				tokens += chunk.Tokens
+			}
+			for _, s := range spans[doc.ID] {
+				result.Spans++
+				covered := false
+				for _, chunk := range chunks {
+					if chunk.Start < s.end && chunk.End > s.start {
+						fragments++
+					}
+					covered = covered || chunk.Start <= s.start && chunk.End >= s.end
+				}
+				if covered {
+					result.Covered++
+				}
+			}
+		}
+		if result.Spans > 0 {
+			result.Coverage = float64(result.Covered) / float64(result.Spans)
+			result.Fragments = float64(fragments) / float64(result.Spans)
+		}
+		if result.Chunks > 0 {
+			result.MeanTokens = float64(tokens) / float64(result.Chunks)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// resolveSpans groups the spans of queries by document, looking up spans given by text
+func resolveSpans(docs []Document, queries []Query) (map[string][]span, error) {
+	texts := make(map[string]string, len(docs))
+	for _, doc := range docs {
+		texts[doc.ID] = doc.Text
+	}
+	spans := make(map[string][]span)
+	for i, q := range queries {
+		text, ok := texts[q.DocumentID]
+		if !ok {
+			return nil, fmt.Errorf("query %d: unknown document %q", i, q.DocumentID)
+		}
+		s := span{q.Start, q.End}
+		if q.End == 0 {
+			start := strings.Index(text, q.Text)
+			if q.Text == "" || start < 0 {
+				return nil, fmt.Errorf("query %d: span not found in document %q", i, q.DocumentID)
+			}
+			s = span{start, start + len(q.Text)}
+		}
+		if s.start < 0 || s.end > len(text) || s.start >= s.end {
+			return nil, fmt.Errorf("query %d: span %d-%d out of range", i, s.start, s.end)
+		}
+		spans[q.DocumentID] = append(spans[q.DocumentID], s)
+	}
+	return spans, nil
+}
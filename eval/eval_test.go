@@ -0,0 +1,43 @@
+package eval
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func countWords(text string) int {
+	return len(strings.Fields(text))
+}
+
+func TestGrid(t *testing.T) {
+	assert.Equal(t, []Setting{{10, 0}, {10, 0.5}, {20, 0}, {20, 0.5}}, Grid([]int{10, 20}, []float32{0, 0.5}))
+}
+
+func TestEvaluate(t *testing.T) {
+	docs := []Document{{ID: "a", Text: "The sky is blue. Grass is green. Snow is white and cold."}}
+	queries := []Query{
+		{Query: "sky color", DocumentID: "a", Text: "The sky is blue."},
+		{Query: "snow", DocumentID: "a", Start: 33, End: 56},
+	}
+	results, err := Evaluate(context.Background(), docs, queries, countWords, []Setting{{ChunkSize: 4}, {ChunkSize: 20}})
+	assert.NoError(t, err)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, 4, results[0].Chunks)
+		assert.Equal(t, 1, results[0].Covered)
+		assert.Equal(t, 2, results[0].Spans)
+		assert.Equal(t, 0.5, results[0].Coverage)
+		assert.Equal(t, 1.5, results[0].Fragments)
+
+		assert.Equal(t, 1, results[1].Chunks)
+		assert.Equal(t, 1.0, results[1].Coverage)
+		assert.Equal(t, 12.0, results[1].MeanTokens)
+	}
+
+	_, err = Evaluate(context.Background(), docs, []Query{{DocumentID: "b", Text: "x"}}, countWords, []Setting{{ChunkSize: 4}})
+	assert.ErrorContains(t, err, "unknown document")
+	_, err = Evaluate(context.Background(), docs, []Query{{DocumentID: "a", Text: "rain"}}, countWords, []Setting{{ChunkSize: 4}})
+	assert.ErrorContains(t, err, "span not found")
+}
@@ -0,0 +1,98 @@
+// Package files reads documents from the file system and splits them with a
+// semchunk.TextSplitter. It holds the file access of the module, so the semchunk package itself
+// does not depend on a file system and builds for targets such as js/wasm.
+package files
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	semchunk "github.com/sanbaiw/semtxtsplitter"
+)
+
+// Option configures how files are read
+type Option struct {
+	// Mmap memory-maps the file instead of reading it into memory, where supported. Default true.
+	Mmap bool
+}
+
+// WithMmap memory-maps files instead of reading them into memory, where supported
+func WithMmap(mmap bool) func(*Option) {
+	return func(opts *Option) {
+		if opts == nil {
+			opts = &Option{}
+		}
+		opts.Mmap = mmap
+	}
+}
+
+// Split splits the contents of the file at path with splitter, see semchunk.SplitBytes for the
+// supported encodings. Chunk offsets are byte offsets in the file, not in the decoded text.
+func Split(splitter *semchunk.TextSplitter, path string, opts ...func(*Option)) ([]semchunk.Chunk, error) {
+	var chunks []semchunk.Chunk
+	err := withFile(path, opts, func(data []byte) (err error) {
+		chunks, err = splitter.SplitBytes(data)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return chunks, nil
+}
+
+// SplitTo splits the file at path like Split, but writes the chunks to sink as they are
+// produced, like semchunk.SplitTo, so neither the file nor its chunks are held in memory at
+// once if the file can be memory-mapped
+func SplitTo(splitter *semchunk.TextSplitter, path string, sink semchunk.ChunkSink, opts ...func(*Option)) error {
+	return withFile(path, opts, func(data []byte) error {
+		return splitter.SplitBytesTo(data, sink)
+	})
+}
+
+// withFile reads the file at path and calls use with its contents, which are released on
+// return. Errors of use are prefixed with path.
+func withFile(path string, opts []func(*Option), use func(data []byte) error) error {
+	fileOpts := &Option{Mmap: true}
+	for _, opt := range opts {
+		opt(fileOpts)
+	}
+
+	data, release, err := readFile(path, fileOpts.Mmap)
+	if err != nil {
+		return err
+	}
+	defer release()
+	if err := use(data); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadDOCX extracts the text of the DOCX file at path as Markdown, see semchunk.ReadDOCX
+func ReadDOCX(path string) (string, error) {
+	return readArchive(path, semchunk.ReadDOCX)
+}
+
+// ReadEPUB extracts the text of the EPUB file at path as Markdown, see semchunk.ReadEPUB
+func ReadEPUB(path string) (string, error) {
+	return readArchive(path, semchunk.ReadEPUB)
+}
+
+// readArchive opens the file at path and extracts its text with read
+func readArchive(path string, read func(r io.ReaderAt, size int64) (string, error)) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	text, err := read(f, info.Size())
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+	return text, nil
+}
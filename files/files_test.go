@@ -0,0 +1,70 @@
+package files
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	semchunk "github.com/sanbaiw/semtxtsplitter"
+	"github.com/stretchr/testify/assert"
+)
+
+func newWordSplitter(t *testing.T, chunkSize int, opts ...func(*semchunk.TextSplitterOption)) *semchunk.TextSplitter {
+	t.Helper()
+	splitter, err := semchunk.NewTextSplitter(chunkSize, 0, func(text string) int { return len(strings.Fields(text)) }, opts...)
+	assert.NoError(t, err)
+	return splitter
+}
+
+func TestSplit(t *testing.T) {
+	text := "Apples are red. Bananas are yellow. Cherries are dark red."
+	path := filepath.Join(t.TempDir(), "doc.txt")
+	assert.NoError(t, os.WriteFile(path, append([]byte{0xEF, 0xBB, 0xBF}, text...), 0o644))
+
+	// flags are substrings of the text and must outlive the mapping
+	splitter := newWordSplitter(t, 3, semchunk.WithDenylist(semchunk.DenylistMark, "red"))
+	expected := splitter.SplitChunks(text)
+	for _, mmap := range []bool{true, false} {
+		chunks, err := Split(splitter, path, WithMmap(mmap))
+		assert.NoError(t, err)
+		assert.Equal(t, len(expected), len(chunks))
+		for i := range chunks {
+			assert.Equal(t, expected[i].Text, chunks[i].Text)
+			assert.Equal(t, expected[i].Start+3, chunks[i].Start)
+		}
+		assert.Equal(t, []string{"red"}, chunks[len(chunks)-1].Metadata.Flags)
+	}
+
+	var streamed []semchunk.Chunk
+	assert.NoError(t, SplitTo(splitter, path, semchunk.ChunkSinkFunc(func(chunk semchunk.Chunk) error {
+		streamed = append(streamed, chunk)
+		return nil
+	})))
+	assert.Equal(t, len(expected), len(streamed))
+	assert.Equal(t, []string{"red"}, streamed[len(streamed)-1].Metadata.Flags)
+
+	_, err := Split(splitter, filepath.Join(t.TempDir(), "missing.txt"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestReadDOCX(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "doc.docx")
+	f, err := os.Create(path)
+	assert.NoError(t, err)
+	w := zip.NewWriter(f)
+	fw, err := w.Create("word/document.xml")
+	assert.NoError(t, err)
+	_, err = fw.Write([]byte(`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>Hello.</w:t></w:r></w:p></w:body></w:document>`))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	assert.NoError(t, f.Close())
+
+	text, err := ReadDOCX(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello.", text)
+
+	_, err = ReadEPUB(path)
+	assert.ErrorContains(t, err, path+": META-INF/container.xml not found")
+}
@@ -0,0 +1,79 @@
+package files
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	semchunk "github.com/sanbaiw/semtxtsplitter"
+)
+
+// SplitAndCompare splits text like SplitChunks and compares the chunks with the golden chunks
+// stored at goldenPath, so a change of chunk boundaries, for example after upgrading the
+// splitter, is detected before the chunks are embedded again. The error wraps os.ErrNotExist
+// if there is no golden file.
+func SplitAndCompare(splitter *semchunk.TextSplitter, text string, goldenPath string) (semchunk.Drift, error) {
+	chunks, err := splitter.SplitChunksContext(context.Background(), text)
+	if err != nil {
+		return semchunk.Drift{}, err
+	}
+	return CompareGolden(chunks, goldenPath)
+}
+
+// CompareGolden compares chunks with the golden chunks stored at goldenPath, see
+// SplitAndCompare
+func CompareGolden(chunks []semchunk.Chunk, goldenPath string) (semchunk.Drift, error) {
+	golden, err := ReadGolden(goldenPath)
+	if err != nil {
+		return semchunk.Drift{}, err
+	}
+	return semchunk.CompareChunks(golden, chunks), nil
+}
+
+// WriteGolden stores chunks at goldenPath as JSON lines, in the format of semchunk.JSONLSink,
+// to be compared with later by SplitAndCompare
+func WriteGolden(goldenPath string, chunks []semchunk.Chunk) error {
+	f, err := os.Create(goldenPath)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	sink := semchunk.NewJSONLSink(w)
+	for _, chunk := range chunks {
+		if err := sink.Write(chunk); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadGolden reads the golden chunks stored at goldenPath by WriteGolden
+func ReadGolden(goldenPath string) ([]semchunk.Chunk, error) {
+	f, err := os.Open(goldenPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	chunks := make([]semchunk.Chunk, 0)
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for {
+		var chunk semchunk.Chunk
+		err := dec.Decode(&chunk)
+		if errors.Is(err, io.EOF) {
+			return chunks, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading golden chunks %s: %w", goldenPath, err)
+		}
+		chunks = append(chunks, chunk)
+	}
+}
@@ -0,0 +1,39 @@
+package files
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitAndCompare(t *testing.T) {
+	text := "One two three. Four five six.\n\nSeven eight nine. Ten eleven twelve."
+	path := filepath.Join(t.TempDir(), "doc.jsonl")
+
+	_, err := SplitAndCompare(newWordSplitter(t, 6), text, path)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	chunks, err := newWordSplitter(t, 6).SplitChunksContext(context.Background(), text)
+	assert.NoError(t, err)
+	assert.NoError(t, WriteGolden(path, chunks))
+	golden, err := ReadGolden(path)
+	assert.NoError(t, err)
+	assert.Equal(t, chunks, golden)
+
+	drift, err := SplitAndCompare(newWordSplitter(t, 6), text, path)
+	assert.NoError(t, err)
+	assert.False(t, drift.Drifted())
+
+	drift, err = SplitAndCompare(newWordSplitter(t, 3), text, path)
+	assert.NoError(t, err)
+	assert.True(t, drift.Drifted())
+	assert.NotEmpty(t, drift.Removed)
+	assert.NotEmpty(t, drift.Added)
+	for _, chunk := range drift.Added {
+		assert.LessOrEqual(t, chunk.Tokens, 3)
+	}
+	assert.Equal(t, "2 chunks removed, 4 chunks added", drift.String())
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package files
+
+import "os"
+
+// readFile returns the contents of the file at path. Memory mapping is not supported on this
+// platform, so the file is always read into memory.
+func readFile(path string, _ bool) ([]byte, func(), error) {
+	data, err := os.ReadFile(path)
+	return data, func() {}, err
+}
@@ -0,0 +1,39 @@
+//go:build unix
+
+package files
+
+import (
+	"os"
+	"syscall"
+)
+
+// readFile returns the contents of the file at path and a function releasing them.
+// With mmap the file is memory-mapped read-only rather than copied into memory.
+func readFile(path string, mmap bool) ([]byte, func(), error) {
+	if !mmap {
+		data, err := os.ReadFile(path)
+		return data, func() {}, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.Size() == 0 || !info.Mode().IsRegular() {
+		// empty files and pipes cannot be mapped
+		data, err := os.ReadFile(path)
+		return data, func() {}, err
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, &os.PathError{Op: "mmap", Path: path, Err: err}
+	}
+	return data, func() { _ = syscall.Munmap(data) }, nil
+}
@@ -0,0 +1,62 @@
+package semchunk
+
+// finisher applies the passes that follow splitting to chunks: dropping chunks, repeating
+// table headers, counting tokens, mapping offsets back to the caller's text, numbering lines
+// and pages, tagging personal data, redacting and appending the chunk suffix. Every entry
+// point finishes its chunks with a finisher, so they all produce the same chunks. Chunks may
+// be finished batch by batch, in text order.
+type finisher struct {
+	c *TextSplitter
+	// annotate enables token counts and metadata, which SplitSpans does without
+	annotate bool
+	// keep keeps the chunks that would be dropped, for chunks asked for by offset
+	keep bool
+	// positions maps offsets in the split text to offsets in the caller's text, nil if they
+	// are the same
+	positions func(int) int
+	// lines numbers the lines of the caller's text
+	lines *lineCounter
+	// pageBreaks are the page breaks in the caller's text, if page markers are configured
+	pageBreaks []int
+	// tables are the numeric tables whose header is repeated, see WithNumericTables
+	tables []numericTable
+	// index is the number of chunks finished so far and prevEnd the furthest end among them
+	index   int
+	prevEnd int
+}
+
+// newFinisher returns a finisher for the chunks of clean, which cleanText made of text
+func (c *TextSplitter) newFinisher(text string, clean string, positions func(int) int, annotate bool) *finisher {
+	f := &finisher{c: c, annotate: annotate, positions: positions, lines: newLineCounter(text), tables: c.headedTables(clean)}
+	if len(c.opts.PageMarkers) > 0 {
+		f.pageBreaks = pageBreaks(text, c.opts.PageMarkers)
+	}
+	return f
+}
+
+// finish post-processes chunks, which follow the chunks finished before, and returns the
+// chunks that are kept
+func (f *finisher) finish(chunks []Chunk) []Chunk {
+	c := f.c
+	if !f.keep {
+		chunks = c.dropChunks(chunks)
+	}
+	repeatTableHeaders(chunks, f.tables)
+	if f.annotate {
+		f.prevEnd = c.annotateFrom(chunks, f.index, f.prevEnd)
+	}
+	f.index += len(chunks)
+	if f.positions != nil {
+		remapChunks(chunks, f.positions)
+	}
+	if f.annotate {
+		setLines(chunks, f.lines)
+		c.tagPII(chunks)
+	}
+	if f.pageBreaks != nil {
+		setPages(chunks, f.pageBreaks)
+	}
+	c.redact(chunks)
+	c.appendSuffix(chunks, f.annotate)
+	return chunks
+}
@@ -0,0 +1,51 @@
+package semchunk
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinisherEntryPoints(t *testing.T) {
+	text := "One two three four.\nFive six. --\n\fSeven eight nine.\nTen eleven twelve."
+	opts := []func(*TextSplitterOption){
+		WithRecords(regexp.MustCompile(`(?m)^--$`), false),
+		WithPageMarkers("\f"),
+		WithMinContentRunes(4),
+		WithRedactor(strings.ToUpper),
+		WithChunkSuffix(" <eoc>"),
+	}
+	splitter := newWordSplitter(t, 4, 1, opts...)
+
+	chunks, err := splitter.SplitChunksContext(context.Background(), text)
+	assert.NoError(t, err)
+	var streamed []Chunk
+	assert.NoError(t, splitter.SplitTo(text, ChunkSinkFunc(func(chunk Chunk) error {
+		chunk.Seq = 0
+		streamed = append(streamed, chunk)
+		return nil
+	})))
+	assert.Equal(t, chunks, streamed)
+	assert.Equal(t, 2, chunks[len(chunks)-1].Metadata.Page)
+
+	// snippets and sentence windows are finished the same way
+	snippet := splitter.SplitAround(text, strings.Index(text, "Ten"), 0)
+	assert.True(t, strings.HasSuffix(snippet.Text, " <eoc>"))
+	assert.Equal(t, 2, snippet.Metadata.Page)
+	assert.Equal(t, 4, snippet.Metadata.Line)
+	windows := splitter.SplitSentenceWindows(text, 1)
+	assert.Equal(t, "TEN ELEVEN TWELVE. <eoc>", windows[len(windows)-1].Text)
+	assert.Equal(t, 2, windows[len(windows)-1].Metadata.Page)
+
+	levels, err := splitter.SplitMulti(context.Background(), text, []int{8, 4})
+	assert.NoError(t, err)
+	for _, level := range levels {
+		for _, chunk := range level.Chunks {
+			assert.True(t, strings.HasSuffix(chunk.Text, " <eoc>"), chunk.Text)
+			assert.NotZero(t, chunk.Metadata.Page)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package semchunk
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Footnote is a footnote or numbered reference used in a chunk, with the text of its definition
+type Footnote struct {
+	Label string `json:"label"`
+	Text  string `json:"text"`
+}
+
+// footnoteSyntax describes how footnotes are defined and referenced in a document format.
+// Both regular expressions capture the label in their first group.
+type footnoteSyntax struct {
+	definition *regexp.Regexp
+	reference  *regexp.Regexp
+}
+
+var (
+	// Markdown footnotes [^label]: text, and numbered references [1]: text
+	markdownFootnotes = footnoteSyntax{
+		definition: regexp.MustCompile(`^ {0,3}\[(\^[^\]\s]+|\d+)\]:[ \t]*(.*)$`),
+		reference:  regexp.MustCompile(`\[(\^[^\]\s]+|\d+)\]`),
+	}
+	// Org footnotes [fn:label] text
+	orgFootnotes = footnoteSyntax{
+		definition: regexp.MustCompile(`^\[fn:([^\]:\s]+)\][ \t]*(.*)$`),
+		reference:  regexp.MustCompile(`\[fn:([^\]:\s]+)\]`),
+	}
+)
+
+// parse returns the footnote definitions in lines by label. A definition continues on the
+// following lines as long as they are indented.
+func (syntax footnoteSyntax) parse(lines []textLine) map[string]string {
+	footnotes := make(map[string]string)
+	for i := 0; i < len(lines); i++ {
+		m := syntax.definition.FindStringSubmatch(lines[i].text)
+		if m == nil {
+			continue
+		}
+		parts := []string{strings.TrimSpace(m[2])}
+		for i+1 < len(lines) && (strings.HasPrefix(lines[i+1].text, "    ") || strings.HasPrefix(lines[i+1].text, "\t")) {
+			i++
+			parts = append(parts, strings.TrimSpace(lines[i].text))
+		}
+		footnotes[m[1]] = strings.TrimSpace(strings.Join(parts, " "))
+	}
+	return footnotes
+}
+
+// attach records in every chunk the definitions of the footnotes it references, in order of first use
+func (syntax footnoteSyntax) attach(chunks []Chunk, footnotes map[string]string) {
+	if len(footnotes) == 0 {
+		return
+	}
+	for i := range chunks {
+		text := chunks[i].Text
+		seen := make(map[string]bool)
+		for _, m := range syntax.reference.FindAllStringSubmatchIndex(text, -1) {
+			label := text[m[2]:m[3]]
+			lineStart := strings.LastIndexByte(text[:m[0]], '\n') + 1
+			lineEnd := len(text)
+			if end := strings.IndexByte(text[m[0]:], '\n'); end >= 0 {
+				lineEnd = m[0] + end
+			}
+			if lineStart <= m[0] && syntax.definition.MatchString(strings.TrimSuffix(text[lineStart:lineEnd], "\r")) &&
+				strings.TrimSpace(text[lineStart:m[0]]) == "" {
+				// the definition itself
+				continue
+			}
+			definition, ok := footnotes[label]
+			if !ok || seen[label] {
+				continue
+			}
+			seen[label] = true
+			chunks[i].Metadata.Footnotes = append(chunks[i].Metadata.Footnotes, Footnote{Label: label, Text: definition})
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkdownFootnotes(t *testing.T) {
+	text := "# Notes\n\nApples are red[^a] and sweet [1].\n\nPears are green[^b].\n\n" +
+		"[^a]: Mostly.\n    Some are yellow.\n[^b]: Usually.\n[1]: https://example.com/apples"
+	chunks := newWordSplitter(t, 8, 0).SplitMarkdown(text)
+
+	assert.Equal(t, "# Notes\n\nApples are red[^a] and sweet [1].", chunks[0].Text)
+	assert.Equal(t, []Footnote{
+		{Label: "^a", Text: "Mostly. Some are yellow."},
+		{Label: "1", Text: "https://example.com/apples"},
+	}, chunks[0].Metadata.Footnotes)
+	assert.Equal(t, []Footnote{{Label: "^b", Text: "Usually."}}, chunks[1].Metadata.Footnotes)
+
+	// the chunk holding the definitions does not reference them
+	last := chunks[len(chunks)-1]
+	assert.Contains(t, last.Text, "[1]: https://example.com/apples")
+	assert.Empty(t, last.Metadata.Footnotes)
+}
+
+func TestOrgFootnotes(t *testing.T) {
+	text := "* Notes\nApples are red[fn:1].\n\n[fn:1] Mostly."
+	chunks := newWordSplitter(t, 5, 0).SplitOrg(text)
+	assert.Equal(t, []Footnote{{Label: "1", Text: "Mostly."}}, chunks[0].Metadata.Footnotes)
+}
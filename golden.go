@@ -0,0 +1,55 @@
+package semchunk
+
+import "fmt"
+
+// Drift lists the differences between the chunks of a text and its golden chunks, see
+// CompareChunks. Chunks are compared by text and offsets only, so changes of token counts or
+// metadata are not drift, but any moved boundary is.
+type Drift struct {
+	// Removed are the golden chunks that are no longer produced, in golden order
+	Removed []Chunk
+	// Added are the chunks that are not among the golden chunks, in text order
+	Added []Chunk
+}
+
+// Drifted reports whether any chunk was added or removed
+func (d Drift) Drifted() bool {
+	return len(d.Removed) > 0 || len(d.Added) > 0
+}
+
+func (d Drift) String() string {
+	return fmt.Sprintf("%d chunks removed, %d chunks added", len(d.Removed), len(d.Added))
+}
+
+// goldenKey identifies a chunk when comparing it with golden chunks
+type goldenKey struct {
+	text       string
+	start, end int
+}
+
+// CompareChunks compares chunks with golden chunks, stored earlier for example by
+// files.WriteGolden. It returns the chunks of golden missing from chunks and those of chunks
+// missing from golden, counting repeated chunks.
+func CompareChunks(golden, chunks []Chunk) Drift {
+	drift := Drift{}
+	count := make(map[goldenKey]int, len(golden))
+	for _, chunk := range golden {
+		count[goldenKey{chunk.Text, chunk.Start, chunk.End}]++
+	}
+	for _, chunk := range chunks {
+		key := goldenKey{chunk.Text, chunk.Start, chunk.End}
+		if count[key] > 0 {
+			count[key]--
+		} else {
+			drift.Added = append(drift.Added, chunk)
+		}
+	}
+	for _, chunk := range golden {
+		key := goldenKey{chunk.Text, chunk.Start, chunk.End}
+		if count[key] > 0 {
+			count[key]--
+			drift.Removed = append(drift.Removed, chunk)
+		}
+	}
+	return drift
+}
@@ -0,0 +1,18 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareChunks(t *testing.T) {
+	golden := []Chunk{{Text: "a", Start: 0, End: 1}, {Text: "b", Start: 2, End: 3}, {Text: "b", Start: 2, End: 3}}
+	// token counts and metadata are not compared
+	chunks := []Chunk{{Text: "a", Start: 0, End: 1, Tokens: 5}, {Text: "b", Start: 2, End: 3}, {Text: "c", Start: 4, End: 5}}
+
+	drift := CompareChunks(golden, chunks)
+	assert.Equal(t, []Chunk{{Text: "b", Start: 2, End: 3}}, drift.Removed)
+	assert.Equal(t, []Chunk{{Text: "c", Start: 4, End: 5}}, drift.Added)
+	assert.False(t, CompareChunks(golden, golden).Drifted())
+}
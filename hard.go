@@ -0,0 +1,143 @@
+package semchunk
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// WithHardBoundaries makes every occurrence of separators, such as "\n\n## " before a
+// chapter heading or "\f" between pages, a boundary that no chunk or overlap spans, even if
+// the chunk size leaves room for more. The text before and after every occurrence is split
+// separately; the separator starts the text after it, so a heading stays with its chapter.
+// Structured documents are divided at hard boundaries inside their sections as well, and
+// the snippets of SplitAround and SplitHighlights end at them.
+func WithHardBoundaries(separators ...string) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.HardBoundaries = append([]string{}, separators...)
+	}
+}
+
+// hardCuts returns the sorted offsets in text at which a hard boundary starts
+func (c *TextSplitter) hardCuts(text string) []int {
+	cuts := make([]int, 0)
+	for _, separator := range c.opts.HardBoundaries {
+		if separator == "" {
+			continue
+		}
+		for i := 0; ; {
+			j := strings.Index(text[i:], separator)
+			if j < 0 {
+				break
+			}
+			cuts = append(cuts, i+j)
+			i += j + len(separator)
+		}
+	}
+	sort.Ints(cuts)
+	return cuts
+}
+
+// hardSegment returns the start and end of the text between the hard boundaries around offset,
+// looking for them only within [lo, hi) of text. The start is 0 and the end len(text) if
+// there is no hard boundary before or after offset.
+func (c *TextSplitter) hardSegment(text string, offset int, lo, hi int) (int, int) {
+	start, end := 0, len(text)
+	if len(c.opts.HardBoundaries) == 0 {
+		return start, end
+	}
+	// a separator starting before hi may reach past it
+	longest := 0
+	for _, separator := range c.opts.HardBoundaries {
+		if len(separator) > longest {
+			longest = len(separator)
+		}
+	}
+	if hi += longest - 1; hi > end {
+		hi = end
+	}
+	for _, cut := range c.hardCuts(text[lo:hi]) {
+		if cut += lo; cut <= offset {
+			start = cut
+		} else {
+			end = cut
+			break
+		}
+	}
+	return start, end
+}
+
+// segments returns the structure clean is divided into before it is split, nil if it is split
+// whole: the sections found by parse, or the records if WithRecords is set, divided at the
+// hard boundaries
+func (c *TextSplitter) segments(clean string, parse structureParser) *documentStructure {
+	if parse == nil && (c.opts.RecordSeparator != nil || len(c.opts.HardBoundaries) > 0) {
+		parse = c.parseSegments
+	}
+	if parse == nil {
+		return nil
+	}
+	doc := parse(clean)
+	doc.sections = cutSections(clean, doc.sections, c.hardCuts(clean))
+	return &doc
+}
+
+// parseSegments divides text into its records if WithRecords is set, or else into one section
+func (c *TextSplitter) parseSegments(text string) documentStructure {
+	if c.opts.RecordSeparator != nil {
+		return c.parseRecords(text)
+	}
+	return documentStructure{sections: []Section{{Start: 0, End: len(text)}}}
+}
+
+// cutSections divides sections at the offsets cuts. The pieces keep the heading of their
+// section, are trimmed of surrounding whitespace and left out if empty.
+func cutSections(text string, sections []Section, cuts []int) []Section {
+	if len(cuts) == 0 {
+		return sections
+	}
+	pieces := make([]Section, 0, len(sections)+len(cuts))
+	for _, section := range sections {
+		start := section.Start
+		for _, cut := range append(cuts[:len(cuts):len(cuts)], section.End) {
+			if cut <= start || cut > section.End {
+				continue
+			}
+			piece := section
+			piece.Start = skipSpace(text, start)
+			if piece.Start < cut {
+				piece.End = piece.Start + len(strings.TrimRightFunc(text[piece.Start:cut], unicode.IsSpace))
+				pieces = append(pieces, piece)
+			}
+			start = cut
+		}
+	}
+	return pieces
+}
+
+// hardBoundaryAfter returns the index of the first chunk separated from the previous one by a
+// hard boundary, 0 if there is none
+func (c *TextSplitter) hardBoundaryAfter(text string, chunks []Chunk) int {
+	if len(c.opts.HardBoundaries) == 0 {
+		return 0
+	}
+	for i := 1; i < len(chunks); i++ {
+		a, b := chunks[i-1], chunks[i]
+		if a.End > b.Start {
+			continue
+		}
+		for _, separator := range c.opts.HardBoundaries {
+			end := b.Start + len(separator)
+			if end > len(text) {
+				end = len(text)
+			}
+			if separator != "" && strings.Contains(text[a.End:end], separator) {
+				return i
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+package semchunk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithHardBoundaries(t *testing.T) {
+	text := "## One\n\nShort intro.\n\n## Two\n\nAnother part.\fNext page here."
+	assert.Equal(t, []string{text}, newWordSplitter(t, 20, 0).Split(text))
+
+	hard := newWordSplitter(t, 20, 0, WithHardBoundaries("\n\n## ", "\f"))
+	chunks := hard.SplitChunks(text)
+	assert.Equal(t, []string{"## One\n\nShort intro.", "## Two\n\nAnother part.", "Next page here."}, chunkTexts(chunks))
+	for _, chunk := range chunks {
+		assert.Equal(t, text[chunk.Start:chunk.End], chunk.Text)
+	}
+
+	var streamed []Chunk
+	assert.NoError(t, hard.SplitTo(text, ChunkSinkFunc(func(chunk Chunk) error {
+		streamed = append(streamed, chunk)
+		return nil
+	})))
+	assert.Equal(t, chunkTexts(chunks), chunkTexts(streamed))
+
+	// overlap does not span a hard boundary, in batches or streams
+	text = "a b c d\fe f g h"
+	overlapping := newWordSplitter(t, 4, 0, WithMinOverlap(2), WithHardBoundaries("\f"))
+	assert.Equal(t, []string{"a b", "a b c d", "e f", "e f g h"}, overlapping.Split(text))
+	streamed = nil
+	assert.NoError(t, overlapping.SplitTo(text, ChunkSinkFunc(func(chunk Chunk) error {
+		streamed = append(streamed, chunk)
+		return nil
+	})))
+	assert.Equal(t, []string{"a b", "a b c d", "e f", "e f g h"}, chunkTexts(streamed))
+
+	// sections of structured documents are divided too
+	markdown, err := hard.SplitMarkdownContext(context.Background(), "# Title\n\nPage one.\fPage two.")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"# Title\n\nPage one.", "Page two."}, chunkTexts(markdown))
+	assert.Equal(t, []string{"Title"}, markdown[1].Metadata.HeadingPath)
+
+	_, err = NewTextSplitter(10, 0, func(string) int { return 1 }, WithHardBoundaries(""))
+	assert.ErrorContains(t, err, "hard boundaries must not be empty")
+}
+
+func TestCutSections(t *testing.T) {
+	text := "ab\n\ncd  \n\nef"
+	sections := []Section{{Heading: "h", Start: 0, End: len(text)}}
+	assert.Equal(t, []Section{
+		{Heading: "h", Start: 0, End: 2},
+		{Heading: "h", Start: 4, End: 6},
+		{Heading: "h", Start: 10, End: 12},
+	}, cutSections(text, sections, []int{2, 6, 8}))
+	assert.Equal(t, sections, cutSections(text, sections, nil))
+}
@@ -0,0 +1,50 @@
+package semchunk
+
+import "strings"
+
+// WithHeadingPullForward enables a post-pass that moves a Markdown heading ending a chunk into
+// the following chunk, which holds its body, as long as that chunk stays within the chunk
+// size. A chunk holding nothing but the heading is merged into the following chunk.
+func WithHeadingPullForward(pull bool) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.PullHeadings = pull
+	}
+}
+
+// pullHeadings moves trailing headings into the next chunk, see WithHeadingPullForward
+func (c *TextSplitter) pullHeadings(text string, chunks []Chunk, chunkSize int) []Chunk {
+	result := make([]Chunk, 0, len(chunks))
+	for i := 0; i < len(chunks); i++ {
+		a := chunks[i]
+		if i+1 == len(chunks) {
+			result = append(result, a)
+			break
+		}
+		b := chunks[i+1]
+		if a.End > b.Start || strings.TrimSpace(text[a.End:b.Start]) != "" {
+			result = append(result, a)
+			continue
+		}
+		lineStart := strings.LastIndexByte(a.Text, '\n') + 1
+		if !atxHeadingRegex.MatchString(strings.TrimSuffix(a.Text[lineStart:], "\r")) {
+			result = append(result, a)
+			continue
+		}
+		start := a.Start + lineStart
+		if c.countTokenFunc(text[start:b.End]) > chunkSize {
+			result = append(result, a)
+			continue
+		}
+
+		chunks[i+1].Start, chunks[i+1].Text = start, text[start:b.End]
+		if lineStart > 0 {
+			a.Text = strings.TrimRight(text[a.Start:start], " \t\r\n")
+			a.End = a.Start + len(a.Text)
+			result = append(result, a)
+		}
+	}
+	return result
+}
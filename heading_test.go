@@ -0,0 +1,29 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithHeadingPullForward(t *testing.T) {
+	text := "Intro words here.\n## Setup\nInstall the tool now."
+	assert.Equal(t, []string{"Intro words here.\n## Setup", "Install the tool now."}, newWordSplitter(t, 6, 0).Split(text))
+
+	chunks := newWordSplitter(t, 6, 0, WithHeadingPullForward(true)).SplitChunks(text)
+	assert.Equal(t, []string{"Intro words here.", "## Setup\nInstall the tool now."}, chunkTexts(chunks))
+	for _, chunk := range chunks {
+		assert.Equal(t, text[chunk.Start:chunk.End], chunk.Text)
+	}
+
+	// the heading stays if the following chunk would become too large
+	assert.Equal(t, []string{"Intro words here.\n## Setup", "Install the tool now."}, newWordSplitter(t, 5, 0, WithHeadingPullForward(true)).Split(text))
+
+	// a chunk holding only the heading is merged into its body
+	markdown := "# Title\n\nOne two three. Four five six."
+	assert.Equal(t, []string{"# Title", "One two three.", "Four five six."}, chunkTexts(newWordSplitter(t, 5, 0).SplitMarkdown(markdown)))
+	chunks = newWordSplitter(t, 5, 0, WithHeadingPullForward(true)).SplitMarkdown(markdown)
+	assert.Equal(t, []string{"# Title\n\nOne two three.", "Four five six."}, chunkTexts(chunks))
+	assert.Equal(t, []string{"Title"}, chunks[0].Metadata.HeadingPath)
+	assert.Equal(t, 0, chunks[0].Index)
+}
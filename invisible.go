@@ -0,0 +1,148 @@
+package semchunk
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// InvisibleMode sets how zero-width and formatting characters are handled before splitting
+type InvisibleMode int
+
+const (
+	// InvisibleKeep leaves the text unchanged
+	InvisibleKeep InvisibleMode = iota
+	// InvisibleStrip removes soft hyphens, zero-width spaces and joiners, and directional marks
+	InvisibleStrip
+	// InvisibleSplit removes the same characters as InvisibleStrip, except zero-width spaces,
+	// which are kept and treated as whitespace between words
+	InvisibleSplit
+)
+
+// invisibleModeNames are the names InvisibleMode values are serialized as, in the order of the values
+var invisibleModeNames = []string{"keep", "strip", "split"}
+
+func (m InvisibleMode) MarshalText() ([]byte, error) {
+	return marshalEnum(m, invisibleModeNames)
+}
+
+func (m *InvisibleMode) UnmarshalText(text []byte) error {
+	return unmarshalEnum(text, invisibleModeNames, m)
+}
+
+const zeroWidthSpace = '\u200b'
+
+// invisibleChars are removed by InvisibleStrip. Removing the zero-width joiner also splits
+// emoji sequences joined by it into their parts.
+var invisibleChars = "\u00ad" + // soft hyphen
+	"\u200b\u200c\u200d\u2060\ufeff" + // zero-width space, non-joiner, joiner, word joiner, BOM
+	"\u200e\u200f\u061c" + // left-to-right, right-to-left and Arabic letter marks
+	"\u202a\u202b\u202c\u202d\u202e" + // directional embeddings and overrides
+	"\u2066\u2067\u2068\u2069" // directional isolates
+
+// WithInvisibleChars sets how invisible characters, common in text extracted from PDFs, are
+// handled. They corrupt token counts and hide word boundaries. Removed characters do not
+// appear in chunk texts, but chunk offsets still refer to the original text.
+func WithInvisibleChars(mode InvisibleMode) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.InvisibleChars = mode
+		opts.compileWhitespace()
+	}
+}
+
+// removeInvisible returns text without the invisible characters of mode and a function
+// mapping byte offsets in the result back to offsets in text
+func removeInvisible(text string, mode InvisibleMode) (string, func(int) int) {
+	if mode == InvisibleKeep {
+		return text, identity
+	}
+	isRemoved := func(r rune) bool {
+		return strings.ContainsRune(invisibleChars, r) && !(mode == InvisibleSplit && r == zeroWidthSpace)
+	}
+	if strings.IndexFunc(text, isRemoved) < 0 {
+		return text, identity
+	}
+	ranges := make([][2]int, 0)
+	for i, r := range text {
+		if !isRemoved(r) {
+			continue
+		}
+		end := i + utf8.RuneLen(r)
+		if n := len(ranges); n > 0 && ranges[n-1][1] == i {
+			ranges[n-1][1] = end
+		} else {
+			ranges = append(ranges, [2]int{i, end})
+		}
+	}
+	return removeRanges(text, ranges)
+}
+
+func identity(i int) int { return i }
+
+// removeRanges returns text without the sorted, non-overlapping byte ranges and a function
+// mapping byte offsets in the result back to offsets in text
+func removeRanges(text string, ranges [][2]int) (string, func(int) int) {
+	if len(ranges) == 0 {
+		return text, identity
+	}
+	var b strings.Builder
+	b.Grow(len(text))
+	// removed[k] is the offset in the result at which the k-th range was, and shift[k] is the
+	// total number of bytes removed up to and including that range
+	removed, shift := make([]int, 0, len(ranges)), make([]int, 0, len(ranges))
+	total, last := 0, 0
+	for _, r := range ranges {
+		b.WriteString(text[last:r[0]])
+		total += r[1] - r[0]
+		if n := len(removed); n > 0 && removed[n-1] == b.Len() {
+			shift[n-1] = total
+		} else {
+			removed = append(removed, b.Len())
+			shift = append(shift, total)
+		}
+		last = r[1]
+	}
+	b.WriteString(text[last:])
+
+	positions := func(i int) int {
+		// offsets at a removed range map to just after it
+		k := sort.Search(len(removed), func(j int) bool { return removed[j] > i })
+		if k == 0 {
+			return i
+		}
+		return i + shift[k-1]
+	}
+	return b.String(), positions
+}
+
+// cleanText removes the invisible characters and blobs that are not split, see
+// WithInvisibleChars and WithBlobs, and returns a function mapping offsets in the result back
+// to offsets in text
+func (c *TextSplitter) cleanText(text string) (string, func(int) int) {
+	clean, positions := removeInvisible(text, c.opts.InvisibleChars)
+	ranges := c.removedBlobRanges(clean)
+	if len(ranges) == 0 {
+		return clean, positions
+	}
+	stripped, inner := removeRanges(clean, ranges)
+	return stripped, func(i int) int { return positions(inner(i)) }
+}
+
+// remapChunks converts the offsets of chunks with positions, see remapChunk
+func remapChunks(chunks []Chunk, positions func(int) int) {
+	for i := range chunks {
+		remapChunk(&chunks[i], positions)
+	}
+}
+
+// remapChunk converts the offsets of chunk with positions, including the end of its overlap
+func remapChunk(chunk *Chunk, positions func(int) int) {
+	if chunk.Metadata.OverlapBytes > 0 {
+		chunk.Metadata.OverlapBytes = positions(chunk.Start+chunk.Metadata.OverlapBytes) - positions(chunk.Start)
+	}
+	chunk.Start = positions(chunk.Start)
+	chunk.End = positions(chunk.End)
+}
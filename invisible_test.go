@@ -0,0 +1,44 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveInvisible(t *testing.T) {
+	text := "ex\u00adam\u200b\u200dple text"
+	clean, positions := removeInvisible(text, InvisibleStrip)
+	assert.Equal(t, "example text", clean)
+	assert.Equal(t, 0, positions(0))
+	assert.Equal(t, 4, positions(2))
+	assert.Equal(t, 12, positions(4))
+	assert.Equal(t, len(text), positions(len(clean)))
+
+	clean, _ = removeInvisible(text, InvisibleSplit)
+	assert.Equal(t, "exam\u200bple text", clean)
+
+	clean, _ = removeInvisible(text, InvisibleKeep)
+	assert.Equal(t, text, clean)
+}
+
+func TestSplitInvisibleChars(t *testing.T) {
+	text := "Soft\u00adhyphen words. Zero\u200bwidth space."
+
+	chunks := newWordSplitter(t, 2, 0, WithInvisibleChars(InvisibleStrip)).SplitChunks(text)
+	assert.Equal(t, []string{"Softhyphen words.", "Zerowidth space."}, chunkTexts(chunks))
+	assert.Equal(t, "Zero\u200bwidth space.", text[chunks[1].Start:chunks[1].End])
+
+	splitter := newWordSplitter(t, 2, 0, WithInvisibleChars(InvisibleSplit))
+	ts := semanticSplit("Zero\u200bwidth", splitter.splitRules())
+	assert.Equal(t, []string{"Zero", "width"}, ts.splits)
+}
+
+func TestInvisibleCharsOverlap(t *testing.T) {
+	// the overlap "anoth­her" is shorter in the stripped text than in the input
+	text := "This is a test sentence. This is anoth­her test sentence."
+	chunks := newWordSplitter(t, 3, 1, WithInvisibleChars(InvisibleStrip)).SplitChunks(text)
+	assert.Equal(t, "anothher test sentence.", chunks[3].Text)
+	assert.Equal(t, len("anoth­her"), chunks[3].Metadata.OverlapBytes)
+	assert.Equal(t, " test sentence.", text[chunks[3].Start+chunks[3].Metadata.OverlapBytes:chunks[3].End])
+}
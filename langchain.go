@@ -0,0 +1,262 @@
+package semchunk
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// KeepSeparator decides where LangChain compatible splitting keeps the separators
+type KeepSeparator int
+
+const (
+	// KeepSeparatorStart attaches every separator to the start of the following split,
+	// LangChain's keep_separator=True and the default of RecursiveCharacterTextSplitter
+	KeepSeparatorStart KeepSeparator = iota
+	// KeepSeparatorEnd attaches every separator to the end of the preceding split
+	KeepSeparatorEnd
+	// KeepSeparatorNone drops the separators and joins merged splits with them instead
+	KeepSeparatorNone
+)
+
+// keepSeparatorNames are the names KeepSeparator values are serialized as, in the order of the values
+var keepSeparatorNames = []string{"start", "end", "none"}
+
+func (k KeepSeparator) MarshalText() ([]byte, error) {
+	return marshalEnum(k, keepSeparatorNames)
+}
+
+func (k *KeepSeparator) UnmarshalText(text []byte) error {
+	return unmarshalEnum(text, keepSeparatorNames, k)
+}
+
+// LangChainOptions mirrors the arguments of LangChain's RecursiveCharacterTextSplitter
+type LangChainOptions struct {
+	// Separators are tried in order, default "\n\n", "\n", " ", ""
+	Separators       []string      `json:"separators,omitempty" yaml:"separators,omitempty"`
+	IsSeparatorRegex bool          `json:"is_separator_regex,omitempty" yaml:"is_separator_regex,omitempty"`
+	KeepSeparator    KeepSeparator `json:"keep_separator,omitempty" yaml:"keep_separator,omitempty"`
+	// KeepWhitespace disables stripping whitespace from the ends of chunks, strip_whitespace=False
+	KeepWhitespace bool `json:"keep_whitespace,omitempty" yaml:"keep_whitespace,omitempty"`
+
+	// patterns are the compiled separators, err the error compiling them
+	patterns []*regexp.Regexp
+	err      error
+}
+
+// WithLangChainCompat makes the splitter reproduce the chunks of LangChain's
+// RecursiveCharacterTextSplitter instead of splitting semantically, so the output of a Python
+// pipeline can be compared with this package before switching to the semantic mode. The token
+// counter plays the role of length_function and the chunk size and overlap are used as
+// chunk_size and chunk_overlap in tokens; pass utf8.RuneCountInString as counter to match the
+// default length function, Python's len. Regular expression separators use Go syntax; an
+// invalid one makes NewTextSplitter fail. Structured splitting such as SplitMarkdown is not
+// affected.
+func WithLangChainCompat(lc LangChainOptions) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		if lc.Separators == nil {
+			lc.Separators = []string{"\n\n", "\n", " ", ""}
+		}
+		lc.compile()
+		opts.LangChain = &lc
+	}
+}
+
+// compile compiles the separators, which are quoted unless IsSeparatorRegex is set
+func (lc *LangChainOptions) compile() {
+	lc.patterns = make([]*regexp.Regexp, len(lc.Separators))
+	lc.err = nil
+	for i, separator := range lc.Separators {
+		if !lc.IsSeparatorRegex {
+			separator = regexp.QuoteMeta(separator)
+		}
+		pattern, err := regexp.Compile(separator)
+		if err != nil {
+			lc.err = fmt.Errorf("invalid LangChain separator %q: %w", lc.Separators[i], err)
+			return
+		}
+		lc.patterns[i] = pattern
+	}
+}
+
+// langChainSplit is a split made by langChainSplitRegex and its offset in the text split
+type langChainSplit struct {
+	text  string
+	start int
+}
+
+// splitLangChain splits text like RecursiveCharacterTextSplitter.split_text. Chunks keep the
+// spans of the splits they are merged from. The chunks merged from splits with a regular
+// expression separator dropped, KeepSeparatorNone, are joined with the expression itself, as
+// LangChain does, so their text differs from the text they span.
+func (c *TextSplitter) splitLangChain(text string) []Chunk {
+	return c.langChainSplit(text, 0, 0)
+}
+
+// langChainSplit is RecursiveCharacterTextSplitter._split_text for text at offset, trying the
+// separators from first on
+func (c *TextSplitter) langChainSplit(text string, offset int, first int) []Chunk {
+	lc := c.opts.LangChain
+	index, next := len(lc.Separators)-1, len(lc.Separators)
+	for i := first; i < len(lc.Separators); i++ {
+		if lc.Separators[i] == "" {
+			index = i
+			break
+		}
+		if lc.patterns[i].MatchString(text) {
+			index, next = i, i+1
+			break
+		}
+	}
+
+	splits := langChainSplitRegex(text, lc.patterns[index], lc.KeepSeparator)
+	mergeSeparator := ""
+	if lc.KeepSeparator == KeepSeparatorNone {
+		mergeSeparator = lc.Separators[index]
+	}
+
+	finalChunks := make([]Chunk, 0)
+	goodSplits := make([]langChainSplit, 0)
+	for _, s := range splits {
+		if c.countTokenFunc(s.text) < c.chunkSize {
+			goodSplits = append(goodSplits, s)
+			continue
+		}
+		if len(goodSplits) > 0 {
+			finalChunks = append(finalChunks, c.langChainMerge(goodSplits, mergeSeparator, offset)...)
+			goodSplits = make([]langChainSplit, 0)
+		}
+		if next == len(lc.Separators) {
+			finalChunks = append(finalChunks, Chunk{Text: s.text, Start: offset + s.start, End: offset + s.start + len(s.text)})
+		} else {
+			finalChunks = append(finalChunks, c.langChainSplit(s.text, offset+s.start, next)...)
+		}
+	}
+	if len(goodSplits) > 0 {
+		finalChunks = append(finalChunks, c.langChainMerge(goodSplits, mergeSeparator, offset)...)
+	}
+	return finalChunks
+}
+
+// langChainSplitRegex is _split_text_with_regex: text is split at every match of pattern, or
+// into characters if pattern is empty, and empty splits are dropped
+func langChainSplitRegex(text string, pattern *regexp.Regexp, keep KeepSeparator) []langChainSplit {
+	var splits []langChainSplit
+	if pattern.String() == "" {
+		for i, r := range text {
+			splits = append(splits, langChainSplit{text: string(r), start: i})
+		}
+		return splits
+	}
+
+	// bounds alternates between the ends of text and separators like re.split with a
+	// capturing group
+	bounds := []int{0}
+	for _, loc := range pattern.FindAllStringIndex(text, -1) {
+		bounds = append(bounds, loc[0], loc[1])
+	}
+	bounds = append(bounds, len(text))
+	piece := func(i, j int) langChainSplit {
+		return langChainSplit{text: text[bounds[i]:bounds[j]], start: bounds[i]}
+	}
+
+	switch keep {
+	case KeepSeparatorStart:
+		splits = append(splits, piece(0, 1))
+		for i := 1; i+2 < len(bounds); i += 2 {
+			splits = append(splits, piece(i, i+2))
+		}
+	case KeepSeparatorEnd:
+		for i := 0; i+2 < len(bounds); i += 2 {
+			splits = append(splits, piece(i, i+2))
+		}
+		splits = append(splits, piece(len(bounds)-2, len(bounds)-1))
+	default:
+		for i := 0; i+1 < len(bounds); i += 2 {
+			splits = append(splits, piece(i, i+1))
+		}
+	}
+
+	nonEmpty := splits[:0]
+	for _, s := range splits {
+		if s.text != "" {
+			nonEmpty = append(nonEmpty, s)
+		}
+	}
+	return nonEmpty
+}
+
+// langChainMerge is TextSplitter._merge_splits for splits of the text at offset
+func (c *TextSplitter) langChainMerge(splits []langChainSplit, separator string, offset int) []Chunk {
+	separatorLen := c.countTokenFunc(separator)
+	docs := make([]Chunk, 0)
+	current := make([]langChainSplit, 0)
+	total := 0
+	for _, d := range splits {
+		l := c.countTokenFunc(d.text)
+		sep := 0
+		if len(current) > 0 {
+			sep = separatorLen
+		}
+		if total+l+sep > c.chunkSize {
+			if len(current) > 0 {
+				if doc, ok := c.langChainJoin(current, separator, offset); ok {
+					docs = append(docs, doc)
+				}
+				for total > c.overlap || total+l+sep > c.chunkSize && total > 0 {
+					first := c.countTokenFunc(current[0].text)
+					if len(current) > 1 {
+						first += separatorLen
+					}
+					total -= first
+					current = current[1:]
+					sep = 0
+					if len(current) > 0 {
+						sep = separatorLen
+					}
+				}
+			}
+		}
+		current = append(current, d)
+		total += l
+		if len(current) > 1 {
+			total += separatorLen
+		}
+	}
+	if doc, ok := c.langChainJoin(current, separator, offset); ok {
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+// langChainJoin is TextSplitter._join_docs for splits of the text at offset. The chunk spans
+// the splits, less the whitespace stripped from its ends.
+func (c *TextSplitter) langChainJoin(splits []langChainSplit, separator string, offset int) (Chunk, bool) {
+	texts := make([]string, len(splits))
+	for i, split := range splits {
+		texts[i] = split.text
+	}
+	last := splits[len(splits)-1]
+	chunk := Chunk{
+		Text:  strings.Join(texts, separator),
+		Start: offset + splits[0].start,
+		End:   offset + last.start + len(last.text),
+	}
+	if !c.opts.LangChain.KeepWhitespace {
+		trimmed := strings.TrimLeftFunc(chunk.Text, isPythonSpace)
+		chunk.Start += len(chunk.Text) - len(trimmed)
+		chunk.Text = strings.TrimRightFunc(trimmed, isPythonSpace)
+		chunk.End -= len(trimmed) - len(chunk.Text)
+	}
+	return chunk, chunk.Text != ""
+}
+
+// isPythonSpace reports whether Python's str.isspace holds for r, which unlike unicode.IsSpace
+// includes the information separators U+001C to U+001F
+func isPythonSpace(r rune) bool {
+	return unicode.IsSpace(r) || r >= 0x1c && r <= 0x1f
+}
@@ -0,0 +1,90 @@
+package semchunk
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newLangChainSplitter(t *testing.T, chunkSize, overlap int, lc LangChainOptions) *TextSplitter {
+	t.Helper()
+	splitter, err := NewTextSplitter(chunkSize, overlap, utf8.RuneCountInString, WithLangChainCompat(lc))
+	assert.NoError(t, err)
+	return splitter
+}
+
+func TestLangChainCompat(t *testing.T) {
+	// test_iterative_text_splitter from LangChain's test suite
+	text := "Hi.\n\nI'm Harrison.\n\nHow? Are? You?\nOkay then f f f f.\nThis is a weird text to write, but gotta test the splittingggg some how.\n\nBye!\n\n-H."
+	splitter := newLangChainSplitter(t, 10, 1, LangChainOptions{})
+	assert.Equal(t, []string{
+		"Hi.", "I'm", "Harrison.", "How? Are?", "You?", "Okay then", "f f f f.", "This is a",
+		"weird", "text to", "write,", "but gotta", "test the", "splitting", "gggg", "some how.",
+		"Bye!", "-H.",
+	}, splitter.Split(text))
+
+	chunks := splitter.SplitChunks(text)
+	for _, chunk := range chunks {
+		assert.Equal(t, chunk.Text, text[chunk.Start:chunk.End])
+	}
+
+	// repeated text is located where it was split from
+	repeated := "散步吧aa散步吧散步吧"
+	chunks = newLangChainSplitter(t, 4, 0, LangChainOptions{}).SplitChunks(repeated)
+	assert.Equal(t, []string{"散步吧a", "a散步吧", "散步吧"}, chunkTexts(chunks))
+	for i, chunk := range chunks {
+		assert.Equal(t, chunk.Text, repeated[chunk.Start:chunk.End])
+		if i > 0 {
+			assert.Less(t, chunks[i-1].Start, chunk.Start, chunk.Text)
+		}
+	}
+	assert.Equal(t, len(repeated), chunks[len(chunks)-1].End)
+
+	_, err := NewTextSplitter(10, 0, utf8.RuneCountInString, WithLangChainCompat(LangChainOptions{Separators: []string{"("}, IsSeparatorRegex: true}))
+	assert.ErrorContains(t, err, `invalid LangChain separator "("`)
+}
+
+func TestLangChainCompatOptions(t *testing.T) {
+	text := "one two three four five six"
+	tests := []struct {
+		name string
+		lc   LangChainOptions
+		want []string
+	}{
+		{
+			name: "separator at start",
+			want: []string{"one two", "three four", "five six"},
+		},
+		{
+			name: "separator at end",
+			lc:   LangChainOptions{KeepSeparator: KeepSeparatorEnd},
+			want: []string{"one two", "three four", "five six"},
+		},
+		{
+			name: "separator dropped",
+			lc:   LangChainOptions{KeepSeparator: KeepSeparatorNone, Separators: []string{"e"}},
+			want: []string{"one two thr", "four fiv", "six"},
+		},
+		{
+			name: "regex separator",
+			lc:   LangChainOptions{Separators: []string{`\s+t`, ""}, IsSeparatorRegex: true},
+			want: []string{"one two", "three four", "five six"},
+		},
+		{
+			name: "keep whitespace",
+			lc:   LangChainOptions{KeepWhitespace: true},
+			want: []string{"one two", " three four", " five six"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newLangChainSplitter(t, 11, 0, tt.lc).Split(text))
+		})
+	}
+
+	// chunks joined with a dropped regular expression separator span the text of their splits
+	chunks := newLangChainSplitter(t, 11, 0, LangChainOptions{Separators: []string{`\s+`}, IsSeparatorRegex: true, KeepSeparator: KeepSeparatorNone}).SplitChunks(text)
+	assert.Equal(t, `one\s+two`, chunks[0].Text)
+	assert.Equal(t, "one two", text[chunks[0].Start:chunks[0].End])
+}
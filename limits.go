@@ -0,0 +1,88 @@
+package semchunk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrLimitExceeded is returned when splitting is aborted by WithMaxSplitOps or WithTimeout
+var ErrLimitExceeded = errors.New("split limit exceeded")
+
+// WithMaxSplitOps aborts splitting once more than n split operations have been performed,
+// where every piece produced by splitting a text at a separator counts as one operation.
+// This bounds the work spent on pathological inputs, such as long texts without separators
+// that force character level recursion. The error is returned by the APIs that return one;
+// Split and SplitChunks return no chunks instead.
+func WithMaxSplitOps(n int) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.MaxSplitOps = n
+	}
+}
+
+// WithTimeout aborts splitting a single text once it has taken longer than d, see WithMaxSplitOps
+func WithTimeout(d time.Duration) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.Timeout = d
+	}
+}
+
+// splitBudget tracks the work done while splitting one text and records why splitting was aborted
+type splitBudget struct {
+	ctx      context.Context
+	ops      int
+	maxOps   int
+	timeout  time.Duration
+	deadline time.Time
+	err      error
+}
+
+// withBudget returns a copy of the splitter that checks the configured limits and ctx
+// while splitting one text
+func (c *TextSplitter) withBudget(ctx context.Context) *TextSplitter {
+	budget := &splitBudget{ctx: ctx, maxOps: c.opts.MaxSplitOps, timeout: c.opts.Timeout}
+	if budget.timeout > 0 {
+		budget.deadline = time.Now().Add(budget.timeout)
+	}
+	limited := *c
+	limited.budget = budget
+	return &limited
+}
+
+// charge adds ops split operations and returns an error once a limit is exceeded
+func (b *splitBudget) charge(ops int) error {
+	if b == nil || b.err != nil {
+		return b.error()
+	}
+	b.ops += ops
+	switch {
+	case b.maxOps > 0 && b.ops > b.maxOps:
+		b.err = fmt.Errorf("%w: more than %d split operations", ErrLimitExceeded, b.maxOps)
+	case b.timeout > 0 && time.Now().After(b.deadline):
+		b.err = fmt.Errorf("%w: timeout of %s after %d split operations", ErrLimitExceeded, b.timeout, b.ops)
+	case b.ctx.Err() != nil:
+		b.err = b.ctx.Err()
+	}
+	return b.err
+}
+
+// fail aborts splitting with err unless it is already aborted
+func (b *splitBudget) fail(err error) {
+	if b != nil && b.err == nil {
+		b.err = err
+	}
+}
+
+func (b *splitBudget) error() error {
+	if b == nil {
+		return nil
+	}
+	return b.err
+}
@@ -0,0 +1,51 @@
+package semchunk
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitLimits(t *testing.T) {
+	// no separators at all forces character level recursion
+	text := strings.Repeat("x", 10000)
+	countRunes := func(text string) int { return len([]rune(text)) }
+
+	tests := []struct {
+		name   string
+		opts   []func(*TextSplitterOption)
+		exceed bool
+	}{
+		{"no limits", nil, false},
+		{"ops within limit", []func(*TextSplitterOption){WithMaxSplitOps(20000)}, false},
+		{"ops exceeded", []func(*TextSplitterOption){WithMaxSplitOps(100)}, true},
+		{"timeout", []func(*TextSplitterOption){WithTimeout(time.Nanosecond)}, true},
+	}
+	for _, tt := range tests {
+		splitter, err := NewTextSplitter(10, 0, countRunes, tt.opts...)
+		assert.NoError(t, err)
+
+		chunks, err := splitter.SplitChunksContext(context.Background(), text)
+		sinkErr := splitter.SplitTo(text, ChunkSinkFunc(func(Chunk) error { return nil }))
+		if tt.exceed {
+			assert.ErrorIs(t, err, ErrLimitExceeded, tt.name)
+			assert.ErrorIs(t, sinkErr, ErrLimitExceeded, tt.name)
+			assert.Nil(t, chunks, tt.name)
+			assert.Nil(t, splitter.Split(text), tt.name)
+		} else {
+			assert.NoError(t, err, tt.name)
+			assert.NoError(t, sinkErr, tt.name)
+			assert.Len(t, chunks, 1000, tt.name)
+		}
+	}
+}
+
+func TestSplitChunksContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := newWordSplitter(t, 3, 0).SplitChunksContext(ctx, "a b c. d e f.")
+	assert.ErrorIs(t, err, context.Canceled)
+}
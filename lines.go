@@ -0,0 +1,41 @@
+package semchunk
+
+import "strings"
+
+// lineCounter finds the line numbers of offsets in a text. Consecutive lookups are cheap
+// when the offsets are close, as the starts and ends of chunks in text order are.
+type lineCounter struct {
+	text   string
+	offset int
+	// line is the number of the line offset is on, from 1
+	line int
+}
+
+func newLineCounter(text string) *lineCounter {
+	return &lineCounter{text: text, line: 1}
+}
+
+// lineAt returns the number of the line containing the byte at offset, from 1
+func (l *lineCounter) lineAt(offset int) int {
+	if offset > len(l.text) {
+		offset = len(l.text)
+	}
+	if offset >= l.offset {
+		l.line += strings.Count(l.text[l.offset:offset], "\n")
+	} else {
+		l.line -= strings.Count(l.text[offset:l.offset], "\n")
+	}
+	l.offset = offset
+	return l.line
+}
+
+// setLines records the first and last line of every chunk, numbered from 1
+func setLines(chunks []Chunk, lines *lineCounter) {
+	for i := range chunks {
+		chunks[i].Metadata.Line = lines.lineAt(chunks[i].Start)
+		chunks[i].Metadata.LineEnd = chunks[i].Metadata.Line
+		if chunks[i].End > chunks[i].Start {
+			chunks[i].Metadata.LineEnd = lines.lineAt(chunks[i].End - 1)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package semchunk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunkLines(t *testing.T) {
+	text := "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hello\")\n}\n"
+	splitter := newWordSplitter(t, 3, 0)
+
+	chunks := splitter.SplitChunks(text)
+	for _, chunk := range chunks {
+		line := strings.Count(text[:chunk.Start], "\n") + 1
+		assert.Equal(t, line, chunk.Metadata.Line, chunk.Text)
+		assert.Equal(t, line+strings.Count(strings.TrimSuffix(chunk.Text, "\n"), "\n"), chunk.Metadata.LineEnd, chunk.Text)
+	}
+	assert.Equal(t, 1, chunks[0].Metadata.Line)
+	assert.Equal(t, 7, chunks[len(chunks)-1].Metadata.LineEnd)
+
+	var streamed []Chunk
+	assert.NoError(t, splitter.SplitTo(text, ChunkSinkFunc(func(chunk Chunk) error {
+		streamed = append(streamed, chunk)
+		return nil
+	})))
+	for i := range streamed {
+		assert.Equal(t, chunks[i].Metadata.Line, streamed[i].Metadata.Line)
+		assert.Equal(t, chunks[i].Metadata.LineEnd, streamed[i].Metadata.LineEnd)
+	}
+
+	// lookups may move backwards, as they do for overlapping chunks
+	lines := newLineCounter("a\nb\nc\nd")
+	assert.Equal(t, []int{4, 2, 3, 1, 4}, []int{lines.lineAt(6), lines.lineAt(2), lines.lineAt(4), lines.lineAt(0), lines.lineAt(100)})
+}
@@ -0,0 +1,75 @@
+package semchunk
+
+// WithMaxChunkBytes caps chunks at n bytes of UTF-8 in addition to the chunk size, for vector
+// stores and APIs that limit payloads in bytes, which token budgets can exceed for CJK text.
+// Chunks over n bytes are split again semantically with their size measured in bytes, and
+// preserve patterns are not honoured for them. The pieces of such a chunk do not overlap. The
+// cap includes the chunk suffix, see WithChunkSuffix.
+func WithMaxChunkBytes(n int) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.MaxChunkBytes = n
+	}
+}
+
+// fitBytes splits the chunks longer than the byte cap set by WithMaxChunkBytes, less the chunk
+// suffix appended to them later
+func (c *TextSplitter) fitBytes(chunks []Chunk) []Chunk {
+	limit := c.opts.MaxChunkBytes
+	if limit <= 0 {
+		return chunks
+	}
+	limit -= len(c.opts.ChunkSuffix)
+	var bySize *TextSplitter
+	result := make([]Chunk, 0, len(chunks))
+	for i := 0; i < len(chunks); i++ {
+		chunk := chunks[i]
+		if len(chunk.Text) <= limit {
+			result = append(result, chunk)
+			continue
+		}
+		if bySize == nil {
+			bySize = c.byteSplitter()
+		}
+		// overlapping chunks over the cap are split again as one span, so that their pieces
+		// do not repeat each other
+		for i+1 < len(chunks) && len(chunks[i+1].Text) > limit && chunks[i+1].Start < chunk.End {
+			if next := chunks[i+1]; next.End > chunk.End {
+				chunk.Text += next.Text[chunk.End-next.Start:]
+				chunk.End = next.End
+			}
+			i++
+		}
+		for _, piece := range bySize.split(chunk.Text, chunk.Start, limit, chunk.Metadata.Depth+1) {
+			// pieces within the neighbouring chunks repeat them
+			if n := len(result); n > 0 && covers(result[n-1], piece) || i+1 < len(chunks) && covers(chunks[i+1], piece) {
+				continue
+			}
+			result = append(result, piece)
+		}
+	}
+	return result
+}
+
+// covers reports whether the span of chunk contains the span of piece
+func covers(chunk Chunk, piece Chunk) bool {
+	return chunk.Start <= piece.Start && piece.End <= chunk.End
+}
+
+// byteSplitter returns a copy of the splitter that measures text in bytes, without overlap and
+// without the options that let chunks exceed their size
+func (c *TextSplitter) byteSplitter() *TextSplitter {
+	opts := *c.opts
+	opts.PreservePatterns = nil
+	opts.StopDescent = nil
+	opts.RelativeOverlap = 0
+	splitter := *c
+	splitter.opts = &opts
+	splitter.countTokenFunc = SizeBytes.counter()
+	splitter.batchCounter = nil
+	splitter.encoder = nil
+	splitter.overlap = 0
+	return &splitter
+}
@@ -0,0 +1,65 @@
+package semchunk
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMaxChunkBytes(t *testing.T) {
+	text := "今天天气很好。我们去公园散步吧。公园里有很多人。"
+
+	plain, err := NewTextSplitter(20, 0, nil, WithSizeUnit(SizeRunes))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"今天天气很好。我们去公园散步吧", "公园里有很多人。"}, plain.Split(text))
+
+	capped, err := NewTextSplitter(20, 0, nil, WithSizeUnit(SizeRunes), WithMaxChunkBytes(30))
+	assert.NoError(t, err)
+	chunks := capped.SplitChunks(text)
+	assert.Equal(t, []string{"今天天气很好", "我们去公园散步吧", "公园里有很多人。"}, chunkTexts(chunks))
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, len(chunk.Text), 30)
+		assert.Equal(t, chunk.Text, text[chunk.Start:chunk.End])
+	}
+
+	var streamed []string
+	assert.NoError(t, capped.SplitTo(text, ChunkSinkFunc(func(chunk Chunk) error {
+		streamed = append(streamed, chunk.Text)
+		return nil
+	})))
+	assert.Equal(t, chunkTexts(chunks), streamed)
+
+	// the cap includes the chunk suffix
+	suffixed, err := NewTextSplitter(20, 0, nil, WithSizeUnit(SizeRunes), WithMaxChunkBytes(25), WithChunkSuffix(" <eoc>"))
+	assert.NoError(t, err)
+	for _, chunk := range suffixed.SplitChunks(text) {
+		assert.LessOrEqual(t, len(chunk.Text), 25, chunk.Text)
+	}
+	_, err = NewTextSplitter(20, 0, nil, WithMaxChunkBytes(6), WithChunkSuffix(" <eoc>"))
+	assert.ErrorContains(t, err, "maximum chunk bytes must be greater than the 6 bytes of the chunk suffix")
+
+	// the pieces of overlapping chunks stay in text order
+	blob := "Some words before the blob " + strings.Repeat("QUJDREVGR0hJSktMTU5PUFFSU1RVVldYWVo", 4)[:120] + " and a few words after it end."
+	for _, opts := range [][]func(*TextSplitterOption){
+		{WithMaxChunkBytes(40)},
+		{WithMaxChunkBytes(46), WithChunkSuffix(" <eoc>")},
+		{WithMaxChunkBytes(40), WithRelativeOverlap(0.25)},
+	} {
+		overlapping, err := NewTextSplitter(47, 12, utf8.RuneCountInString, opts...)
+		assert.NoError(t, err)
+		chunks := overlapping.SplitChunks(blob)
+		for i := 1; i < len(chunks); i++ {
+			assert.LessOrEqual(t, chunks[i-1].Start, chunks[i].Start, chunks[i].Text)
+			assert.LessOrEqual(t, chunks[i-1].End, chunks[i].End, chunks[i].Text)
+			assert.False(t, covers(chunks[i], chunks[i-1]) || covers(chunks[i-1], chunks[i]), chunks[i].Text)
+		}
+	}
+
+	// words longer than the cap are cut
+	for _, chunk := range newWordSplitter(t, 10, 0, WithMaxChunkBytes(4)).Split("abcdefghij kl") {
+		assert.LessOrEqual(t, len(chunk), 4)
+		assert.NotEmpty(t, strings.TrimSpace(chunk))
+	}
+}
@@ -0,0 +1,276 @@
+package semchunk
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Merger groups consecutive splits into chunks. The splitter hands it the token counts of
+// the splits of one level that fit the chunk size on their own.
+type Merger interface {
+	// Merge returns [start, end) windows of split indices covering all splits in order;
+	// empty windows are skipped.
+	// Windows joined with separatorSize tokens between their splits should stay within
+	// chunkSize. overlap returns the number of tokens a window of size tokens should share
+	// with the next one, 0 for no overlap.
+	Merge(splitSizes []int, separatorSize int, chunkSize int, overlap func(size int) int) [][2]int
+}
+
+// WithMerger replaces the greedy merging of splits into chunks by merger, for example
+// BalancedMerger or OptimalMerger
+func WithMerger(merger Merger) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.Merger = merger
+	}
+}
+
+// MergeStrategy names a Merger, for configurations that cannot hold one
+type MergeStrategy int
+
+const (
+	// MergeGreedy merges with GreedyMerger
+	MergeGreedy MergeStrategy = iota
+	// MergeBalanced merges with BalancedMerger
+	MergeBalanced
+	// MergeOptimal merges with OptimalMerger
+	MergeOptimal
+)
+
+// mergeStrategyNames are the names MergeStrategy values are serialized as, in the order of the values
+var mergeStrategyNames = []string{"greedy", "balanced", "optimal"}
+
+func (s MergeStrategy) MarshalText() ([]byte, error) {
+	return marshalEnum(s, mergeStrategyNames)
+}
+
+func (s *MergeStrategy) UnmarshalText(text []byte) error {
+	return unmarshalEnum(text, mergeStrategyNames, s)
+}
+
+// merger returns the Merger of s, seeding a BalancedMerger with seed
+func (s MergeStrategy) merger(seed int64) Merger {
+	switch s {
+	case MergeBalanced:
+		return BalancedMerger{Seed: seed}
+	case MergeOptimal:
+		return OptimalMerger{}
+	}
+	return GreedyMerger{}
+}
+
+// GreedyMerger fills every chunk with as many splits as fit before starting the next one.
+// It is the default.
+type GreedyMerger struct{}
+
+func (GreedyMerger) Merge(splitSizes []int, splitterSize int, chunkSize int, overlapFor func(size int) int) [][2]int {
+	windows := make([][2]int, 0)
+
+	windowStart := 0
+	size := 0
+	for i, l := range splitSizes {
+		if estimateSize(size, l, splitterSize, i > windowStart) > chunkSize {
+			windows = append(windows, [2]int{windowStart, i})
+
+			if overlap := overlapFor(size); overlap > 0 {
+				// keeps popping from the front of the window until the size is less than the overlap
+				for size > overlap ||
+					(estimateSize(size, l, splitterSize, i > windowStart) > chunkSize && size > 0) {
+					size -= splitSizes[windowStart]
+					if i-windowStart > 1 {
+						size -= splitterSize
+					}
+					windowStart++
+				}
+			} else {
+				windowStart = i
+				size = 0
+			}
+		}
+
+		// still have a chace that single split exceeds chunkSize
+		size += l
+		if i-windowStart > 0 {
+			size += splitterSize
+		}
+	}
+	if windowStart < len(splitSizes) {
+		windows = append(windows, [2]int{windowStart, len(splitSizes)})
+	}
+
+	return windows
+}
+
+// MergeScored merges splits made at scored boundaries. Every chunk is filled like by Merge,
+// but then ends at the best scored boundary that leaves it at least half of chunkSize full,
+// the latest one among equally good boundaries. Consecutive chunks overlap like those of
+// OptimalMerger.
+func (GreedyMerger) MergeScored(splitSizes []int, scores []float64, separatorSize int, chunkSize int, overlap func(size int) int) [][2]int {
+	windows := make([][2]int, 0)
+	for start := 0; start < len(splitSizes); {
+		// the latest end keeping the window within chunkSize
+		end, size := start+1, splitSizes[start]
+		for end < len(splitSizes) && size+separatorSize+splitSizes[end] <= chunkSize {
+			size += separatorSize + splitSizes[end]
+			end++
+		}
+		if end < len(splitSizes) {
+			best := end
+			for e := end - 1; e > start; e-- {
+				size -= separatorSize + splitSizes[e]
+				if 2*size < chunkSize {
+					break
+				}
+				if scores[e] > scores[best] {
+					best = e
+				}
+			}
+			end = best
+		}
+		windows = append(windows, [2]int{start, end})
+		start = end
+	}
+	return overlapWindows(windows, splitSizes, separatorSize, chunkSize, overlap)
+}
+
+// BalancedMerger produces as many chunks as GreedyMerger, but evens out their sizes, so the
+// last chunk of a section is not a small remainder. Where several boundaries give chunks as
+// even, it ends every chunk as late as possible, unless Seed is set: then it picks among them
+// pseudo-randomly, seeded by Seed, so different seeds give different but equally balanced
+// chunks and the same seed always gives the same chunks.
+type BalancedMerger struct {
+	Seed int64
+}
+
+func (m BalancedMerger) Merge(splitSizes []int, separatorSize int, chunkSize int, overlap func(size int) int) [][2]int {
+	count := len(packWindows(splitSizes, separatorSize, chunkSize))
+	// find the smallest limit that packs the splits into as many windows
+	low, high := 0, chunkSize
+	for low < high {
+		mid := (low + high) / 2
+		if len(packWindows(splitSizes, separatorSize, mid)) <= count {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	windows := packWindows(splitSizes, separatorSize, high)
+	if m.Seed != 0 {
+		windows = seededWindows(splitSizes, separatorSize, high, len(windows), rand.New(rand.NewSource(m.Seed)))
+	}
+	return overlapWindows(windows, splitSizes, separatorSize, chunkSize, overlap)
+}
+
+// seededWindows groups splits into count windows of at most limit tokens, ending every window
+// at a boundary picked by rng among those that leave the remaining splits packable into the
+// remaining windows
+func seededWindows(splitSizes []int, separatorSize int, limit int, count int, rng *rand.Rand) [][2]int {
+	windows := make([][2]int, 0, count)
+	for start := 0; start < len(splitSizes); {
+		left := count - len(windows) - 1
+		if left <= 0 {
+			windows = append(windows, [2]int{start, len(splitSizes)})
+			break
+		}
+		// the latest end keeping the window within limit
+		latest, size := start+1, splitSizes[start]
+		for latest < len(splitSizes) && size+separatorSize+splitSizes[latest] <= limit {
+			size += separatorSize + splitSizes[latest]
+			latest++
+		}
+		// the earliest end leaving the rest packable into the windows left
+		earliest := start + 1 + sort.Search(latest-start-1, func(i int) bool {
+			return len(packWindows(splitSizes[start+1+i:], separatorSize, limit)) <= left
+		})
+		end := earliest + rng.Intn(latest-earliest+1)
+		windows = append(windows, [2]int{start, end})
+		start = end
+	}
+	return windows
+}
+
+// OptimalMerger chooses the chunks that minimize the sum of the squared unused space of all
+// chunks, by dynamic programming. It prefers a few full chunks to many small ones and avoids
+// very uneven chunks.
+type OptimalMerger struct{}
+
+func (OptimalMerger) Merge(splitSizes []int, separatorSize int, chunkSize int, overlap func(size int) int) [][2]int {
+	n := len(splitSizes)
+	// cost[i] is the cost of the best merge of the first i splits, which ends a window at i
+	cost := make([]int, n+1)
+	from := make([]int, n+1)
+	for end := 1; end <= n; end++ {
+		cost[end] = -1
+		size := -separatorSize
+		for start := end - 1; start >= 0; start-- {
+			size += splitSizes[start] + separatorSize
+			if size > chunkSize && start < end-1 {
+				break
+			}
+			slack := chunkSize - size
+			if slack < 0 {
+				slack = 0
+			}
+			if c := cost[start] + slack*slack; cost[end] < 0 || c < cost[end] {
+				cost[end], from[end] = c, start
+			}
+		}
+	}
+
+	windows := make([][2]int, 0)
+	for end := n; end > 0; end = from[end] {
+		windows = append(windows, [2]int{from[end], end})
+	}
+	for i, j := 0, len(windows)-1; i < j; i, j = i+1, j-1 {
+		windows[i], windows[j] = windows[j], windows[i]
+	}
+	return overlapWindows(windows, splitSizes, separatorSize, chunkSize, overlap)
+}
+
+// packWindows groups splits greedily into windows of at most limit tokens, without overlap
+func packWindows(splitSizes []int, separatorSize int, limit int) [][2]int {
+	windows := make([][2]int, 0)
+	start, size := 0, 0
+	for i, l := range splitSizes {
+		if i > start && size+separatorSize+l > limit {
+			windows = append(windows, [2]int{start, i})
+			start, size = i, 0
+		}
+		if i > start {
+			size += separatorSize
+		}
+		size += l
+	}
+	if start < len(splitSizes) {
+		windows = append(windows, [2]int{start, len(splitSizes)})
+	}
+	return windows
+}
+
+// overlapWindows extends every window but the first backwards by the splits at the end of the
+// previous window that fit its overlap, as long as the window stays within chunkSize
+func overlapWindows(windows [][2]int, splitSizes []int, separatorSize int, chunkSize int, overlap func(size int) int) [][2]int {
+	windowSize := func(window [2]int) int {
+		size := (window[1] - window[0] - 1) * separatorSize
+		for _, l := range splitSizes[window[0]:window[1]] {
+			size += l
+		}
+		return size
+	}
+	for i := len(windows) - 1; i > 0; i-- {
+		limit := overlap(windowSize(windows[i-1]))
+		size, shared := windowSize(windows[i]), 0
+		for start := windows[i][0]; start-1 > windows[i-1][0]; start-- {
+			l := splitSizes[start-1]
+			if shared+l > limit || size+l+separatorSize > chunkSize {
+				break
+			}
+			shared += l + separatorSize
+			size += l + separatorSize
+			windows[i][0] = start - 1
+		}
+	}
+	return windows
+}
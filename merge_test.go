@@ -0,0 +1,71 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergers(t *testing.T) {
+	sizes := []int{2, 2, 2, 2, 2, 2, 1}
+	noOverlap := func(size int) int { return 0 }
+	tests := []struct {
+		name   string
+		merger Merger
+		want   [][2]int
+	}{
+		{"greedy", GreedyMerger{}, [][2]int{{0, 5}, {5, 7}}},
+		{"balanced", BalancedMerger{}, [][2]int{{0, 3}, {3, 7}}},
+		{"optimal", OptimalMerger{}, [][2]int{{0, 3}, {3, 7}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.merger.Merge(sizes, 0, 10, noOverlap))
+			assert.Empty(t, tt.merger.Merge(nil, 0, 10, noOverlap))
+			// a split larger than the chunk size gets a window of its own; GreedyMerger may
+			// also return empty windows, which are skipped
+			windows := make([][2]int, 0)
+			for _, window := range tt.merger.Merge([]int{12, 3}, 0, 10, noOverlap) {
+				if window[0] < window[1] {
+					windows = append(windows, window)
+				}
+			}
+			assert.Equal(t, [][2]int{{0, 1}, {1, 2}}, windows)
+		})
+	}
+
+	overlap := func(size int) int { return 2 }
+	assert.Equal(t, [][2]int{{0, 3}, {2, 7}}, OptimalMerger{}.Merge(sizes, 0, 10, overlap))
+	assert.Equal(t, [][2]int{{0, 3}, {2, 7}}, BalancedMerger{}.Merge(sizes, 0, 10, overlap))
+}
+
+func TestWithMerger(t *testing.T) {
+	text := "one two three four five six seven"
+	assert.Equal(t, []string{"one two three four five", "six seven"}, newWordSplitter(t, 5, 0).Split(text))
+	assert.Equal(t, []string{"one two three four", "five six seven"}, newWordSplitter(t, 5, 0, WithMerger(BalancedMerger{})).Split(text))
+	assert.Equal(t, []string{"one two three four", "five six seven"}, newWordSplitter(t, 5, 0, WithMerger(OptimalMerger{})).Split(text))
+}
+
+func TestBalancedMergerSeed(t *testing.T) {
+	sizes := []int{1, 1, 1, 1, 1, 1, 1, 1, 1}
+	noOverlap := func(size int) int { return 0 }
+	assert.Equal(t, [][2]int{{0, 5}, {5, 9}}, BalancedMerger{}.Merge(sizes, 0, 5, noOverlap))
+
+	ends := make(map[int]bool)
+	for seed := int64(1); seed <= 20; seed++ {
+		windows := BalancedMerger{Seed: seed}.Merge(sizes, 0, 5, noOverlap)
+		assert.Equal(t, windows, BalancedMerger{Seed: seed}.Merge(sizes, 0, 5, noOverlap))
+		assert.Equal(t, 2, len(windows))
+		assert.Equal(t, [2]int{0, 9}, [2]int{windows[0][0], windows[1][1]})
+		assert.Equal(t, windows[0][1], windows[1][0])
+		assert.LessOrEqual(t, windows[0][1], 5)
+		assert.GreaterOrEqual(t, windows[0][1], 4)
+		ends[windows[0][1]] = true
+	}
+	// both equally balanced boundaries are picked by some seed
+	assert.Equal(t, map[int]bool{4: true, 5: true}, ends)
+
+	text := "one two three four five six seven eight nine"
+	splitter := newWordSplitter(t, 5, 0, WithMerger(BalancedMerger{Seed: 7}))
+	assert.Equal(t, splitter.Split(text), splitter.Split(text))
+}
@@ -0,0 +1,96 @@
+package semchunk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Granularity is the set of chunks of one chunk size produced by SplitMulti
+type Granularity struct {
+	ChunkSize int
+	Chunks    []Chunk
+	// Parents holds for every chunk the index of the chunk containing it at the next coarser
+	// granularity, -1 at the coarsest
+	Parents []int
+	// Children holds for every chunk the [start, end) range of indices of the chunks it
+	// contains at the next finer granularity, empty at the finest
+	Children [][2]int
+}
+
+// SplitMulti splits text at several chunk sizes at once, for example 2048, 512 and 128
+// tokens, and returns one Granularity per size from the largest to the smallest. Every
+// chunk is split from a chunk of the next larger size, so the granularities are aligned: no
+// chunk crosses the boundary of its parent, and parents and children link to each other.
+// Splitting the chunks of the previous size is cheaper than splitting the text once per
+// size. The safety margin, minimum overlap and chunk suffix apply to every size as they do to
+// the configured size, and the overlap is scaled with it.
+func (c *TextSplitter) SplitMulti(ctx context.Context, text string, sizes []int) ([]Granularity, error) {
+	if len(sizes) == 0 {
+		return nil, fmt.Errorf("no chunk sizes")
+	}
+	sizes = append([]int(nil), sizes...)
+	sort.Sort(sort.Reverse(sort.IntSlice(sizes)))
+	for i, size := range sizes {
+		if size <= 0 {
+			return nil, fmt.Errorf("chunk size %d must be positive", size)
+		}
+		if i > 0 && size == sizes[i-1] {
+			return nil, fmt.Errorf("duplicate chunk size %d", size)
+		}
+	}
+
+	// every size is reduced like the configured chunk size, leaving room for the safety
+	// margin, the minimum overlap and the suffix every granularity is finished with
+	if c.reducedSize(sizes[len(sizes)-1]) <= 0 {
+		return nil, fmt.Errorf("chunk size %d leaves no room after the minimum overlap and chunk suffix", sizes[len(sizes)-1])
+	}
+
+	// finer granularities split the coarser chunks before they are finished, which may
+	// change their text
+	clean, positions := c.cleanText(text)
+	levels := make([]Granularity, len(sizes))
+	coarsest, err := c.withChunkSize(c.reducedSize(sizes[0])).splitClean(ctx, clean, nil)
+	if err != nil {
+		return nil, err
+	}
+	coarsest = c.dropChunks(coarsest)
+	levels[0] = Granularity{ChunkSize: sizes[0], Chunks: coarsest, Parents: make([]int, len(coarsest))}
+	for i := range coarsest {
+		levels[0].Parents[i] = -1
+	}
+
+	for l := 1; l < len(sizes); l++ {
+		splitter := c.withChunkSize(c.reducedSize(sizes[l]))
+		parent := &levels[l-1]
+		parent.Children = make([][2]int, len(parent.Chunks))
+		level := Granularity{ChunkSize: sizes[l], Chunks: make([]Chunk, 0), Parents: make([]int, 0)}
+		for p, chunk := range parent.Chunks {
+			children, err := splitter.splitClean(ctx, clean[chunk.Start:chunk.End], nil)
+			if err != nil {
+				return nil, err
+			}
+			parent.Children[p][0] = len(level.Chunks)
+			for _, child := range c.dropChunks(children) {
+				child.Start += chunk.Start
+				child.End += chunk.Start
+				level.Chunks = append(level.Chunks, child)
+				level.Parents = append(level.Parents, p)
+			}
+			parent.Children[p][1] = len(level.Chunks)
+		}
+		levels[l] = level
+	}
+	levels[len(levels)-1].Children = make([][2]int, len(levels[len(levels)-1].Chunks))
+
+	// the chunks are dropped above already, so the links stay valid
+	for l := range levels {
+		f := c.newFinisher(text, clean, positions, true)
+		f.keep = true
+		levels[l].Chunks = f.finish(levels[l].Chunks)
+	}
+	if err := c.summarize(ctx, levels[0].Chunks); err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
@@ -0,0 +1,58 @@
+package semchunk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitMulti(t *testing.T) {
+	text := "One two three. Four five six.\n\nSeven eight nine. Ten eleven twelve."
+	splitter := newWordSplitter(t, 100, 0)
+
+	levels, err := splitter.SplitMulti(context.Background(), text, []int{3, 12, 6})
+	assert.NoError(t, err)
+	assert.Len(t, levels, 3)
+	assert.Equal(t, []int{12, 6, 3}, []int{levels[0].ChunkSize, levels[1].ChunkSize, levels[2].ChunkSize})
+
+	assert.Equal(t, []string{text}, chunkTexts(levels[0].Chunks))
+	assert.Equal(t, []string{"One two three. Four five six.", "Seven eight nine. Ten eleven twelve."}, chunkTexts(levels[1].Chunks))
+	assert.Equal(t, []string{"One two three.", "Four five six.", "Seven eight nine.", "Ten eleven twelve."}, chunkTexts(levels[2].Chunks))
+
+	assert.Equal(t, []int{-1}, levels[0].Parents)
+	assert.Equal(t, [][2]int{{0, 2}}, levels[0].Children)
+	assert.Equal(t, []int{0, 0}, levels[1].Parents)
+	assert.Equal(t, [][2]int{{0, 2}, {2, 4}}, levels[1].Children)
+	assert.Equal(t, []int{0, 0, 1, 1}, levels[2].Parents)
+	assert.Equal(t, [][2]int{{}, {}, {}, {}}, levels[2].Children)
+
+	for _, level := range levels {
+		for i, chunk := range level.Chunks {
+			assert.Equal(t, i, chunk.Index)
+			assert.Equal(t, text[chunk.Start:chunk.End], chunk.Text)
+		}
+	}
+	assert.Equal(t, 3, levels[2].Chunks[2].Metadata.Line)
+
+	_, err = splitter.SplitMulti(context.Background(), text, nil)
+	assert.Error(t, err)
+	_, err = splitter.SplitMulti(context.Background(), text, []int{6, 6})
+	assert.Error(t, err)
+}
+
+func TestSplitMultiReducedSizes(t *testing.T) {
+	text := "One two three. Four five six.\n\nSeven eight nine. Ten eleven twelve."
+	// half of every size is kept as a safety margin
+	splitter := newWordSplitter(t, 100, 0, WithSafetyMargin(0.5))
+	levels, err := splitter.SplitMulti(context.Background(), text, []int{12, 6})
+	assert.NoError(t, err)
+	for _, level := range levels {
+		for _, chunk := range level.Chunks {
+			assert.LessOrEqual(t, chunk.Tokens, level.ChunkSize/2, chunk.Text)
+		}
+	}
+
+	_, err = newWordSplitter(t, 100, 0, WithMinOverlap(2)).SplitMulti(context.Background(), text, []int{6, 2})
+	assert.ErrorContains(t, err, "chunk size 2 leaves no room")
+}
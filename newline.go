@@ -0,0 +1,56 @@
+package semchunk
+
+import "regexp"
+
+// NewlineTiers enables the newline separator tiers, see WithNewlineTiers
+type NewlineTiers struct {
+	// Paragraphs splits at blank lines
+	Paragraphs bool
+	// Lines splits at single line breaks
+	Lines bool
+}
+
+// WithNewlineTiers replaces splitting at the longest run of line breaks in a text by two
+// separate tiers: first at blank lines between paragraphs, then at single line breaks. Each
+// tier can be disabled; text is then split at the next tier, newlines counting as whitespace.
+// Lines containing only spaces or tabs count as blank.
+func WithNewlineTiers(paragraphs, lines bool) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.NewlineTiers = &NewlineTiers{Paragraphs: paragraphs, Lines: lines}
+	}
+}
+
+var (
+	paragraphBreakRegex = regexp.MustCompile(`[ \t]*(?:\r\n|\r|\n)(?:[ \t]*(?:\r\n|\r|\n))+`)
+	lineBreakRegex      = regexp.MustCompile(`\r\n|\r|\n`)
+)
+
+// splitNewlineTiers splits text at its paragraph breaks or, if it has none, at its line
+// breaks, unless the tier is disabled
+func splitNewlineTiers(text string, tiers *NewlineTiers) (textSplit, bool) {
+	if tiers.Paragraphs {
+		if ts, ok := splitAtBreaks(text, paragraphBreakRegex, LevelParagraph); ok {
+			return ts, true
+		}
+	}
+	if tiers.Lines {
+		return splitAtBreaks(text, lineBreakRegex, LevelLine)
+	}
+	return textSplit{}, false
+}
+
+// splitAtBreaks splits text around the matches of re
+func splitAtBreaks(text string, re *regexp.Regexp, level SplitLevel) (textSplit, bool) {
+	matches := re.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return textSplit{}, false
+	}
+	offsets := make([]int, 0, 2*len(matches))
+	for _, m := range matches {
+		offsets = append(offsets, m[0], m[1])
+	}
+	return splitAtOffsets(text, offsets, level)
+}
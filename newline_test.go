@@ -0,0 +1,29 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithNewlineTiers(t *testing.T) {
+	text := "One two\nthree four\n\nFive six\n\n\nSeven eight\nnine"
+
+	// the longest newline run splits first, so the two paragraph breaks are not equal
+	assert.Equal(t, []string{"One two\nthree four", "Five six", "Seven eight\nnine"}, newWordSplitter(t, 5, 0).Split(text))
+
+	tiered := newWordSplitter(t, 5, 0, WithNewlineTiers(true, true))
+	chunks := tiered.SplitChunks(text)
+	assert.Equal(t, []string{"One two\nthree four", "Five six\n\n\nSeven eight\nnine"}, chunkTexts(chunks))
+	assert.Equal(t, LevelParagraph, chunks[0].Metadata.Level)
+	for _, chunk := range chunks {
+		assert.Equal(t, text[chunk.Start:chunk.End], chunk.Text)
+	}
+
+	// lines are split at single line breaks before whitespace
+	assert.Equal(t, []string{"One two", "three four", "Five six", "Seven eight", "nine"}, newWordSplitter(t, 2, 0, WithNewlineTiers(true, true)).Split(text))
+	// without the line tier newlines count as whitespace
+	assert.Equal(t, []string{"One two\nthree", "four", "Five six", "Seven eight\nnine"}, newWordSplitter(t, 3, 0, WithNewlineTiers(true, false)).Split(text))
+	// blank lines holding spaces are paragraph breaks too
+	assert.Equal(t, []string{"One two", "three four"}, newWordSplitter(t, 3, 0, WithNewlineTiers(true, false)).Split("One two\n  \nthree four"))
+}
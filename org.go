@@ -0,0 +1,70 @@
+package semchunk
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// SplitOrg splits an Emacs Org document along its headings, like SplitMarkdown. Blocks such as
+// #+BEGIN_SRC ... #+END_SRC are kept in one chunk unless they exceed the chunk size on their own,
+// and footnotes used in a chunk are listed in Metadata.Footnotes.
+func (c *TextSplitter) SplitOrg(text string) []Chunk {
+	chunks, _ := c.SplitOrgContext(context.Background(), text)
+	return chunks
+}
+
+// SplitOrgContext is SplitOrg, returning an error if splitting is aborted
+func (c *TextSplitter) SplitOrgContext(ctx context.Context, text string) ([]Chunk, error) {
+	return c.splitParsed(ctx, text, true, parseOrg)
+}
+
+// OrgOutline returns the sections of an Org document that start at a heading, like Outline
+func OrgOutline(text string) []Section {
+	return headingSections(parseOrg(text).sections)
+}
+
+var (
+	orgHeadingRegex    = regexp.MustCompile(`^(\*+)[ \t]+(.*?)[ \t]*$`)
+	orgTagsRegex       = regexp.MustCompile(`[ \t]+:[[:alnum:]_@#%:]+:$`)
+	orgKeywordRegex    = regexp.MustCompile(`^(?:TODO|DONE)[ \t]+`)
+	orgPriorityRegex   = regexp.MustCompile(`^\[#[A-Z0-9]\][ \t]+`)
+	orgBlockBeginRegex = regexp.MustCompile(`(?i)^[ \t]*#\+begin_(\S+)`)
+)
+
+// parseOrg finds the headings and the #+BEGIN_ blocks of an Org text. The heading text is
+// stripped of TODO keywords, priorities and tags.
+func parseOrg(text string) documentStructure {
+	var doc documentStructure
+	headings := make([]Section, 0)
+
+	lines := textLines(text)
+	blockEnd, blockStart := "", 0
+	for _, line := range lines {
+		if blockEnd != "" {
+			if strings.EqualFold(strings.TrimSpace(line.text), blockEnd) {
+				doc.blocks = append(doc.blocks, [2]int{blockStart, line.end})
+				blockEnd = ""
+			}
+			continue
+		}
+		if m := orgBlockBeginRegex.FindStringSubmatch(line.text); m != nil {
+			blockEnd, blockStart = "#+end_"+m[1], line.start
+			continue
+		}
+		if m := orgHeadingRegex.FindStringSubmatch(line.text); m != nil {
+			heading := orgTagsRegex.ReplaceAllString(m[2], "")
+			heading = orgKeywordRegex.ReplaceAllString(heading, "")
+			heading = orgPriorityRegex.ReplaceAllString(heading, "")
+			headings = append(headings, Section{Heading: strings.TrimSpace(heading), Level: len(m[1]), Start: line.start})
+		}
+	}
+	if blockEnd != "" {
+		// an unclosed block runs to the end of the document
+		doc.blocks = append(doc.blocks, [2]int{blockStart, len(text)})
+	}
+
+	doc.sections = buildSections(text, headings)
+	doc.footnotes, doc.footnoteSyntax = orgFootnotes.parse(lines), orgFootnotes
+	return doc
+}
@@ -0,0 +1,28 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOrg(t *testing.T) {
+	text := "#+TITLE: Notes\n\n* TODO [#A] Fruit :food:\nApples.\n** Code\n#+BEGIN_SRC python\n* not a heading\n#+END_SRC\n* Next"
+	doc := parseOrg(text)
+
+	paths := make([][]string, 0)
+	for _, section := range doc.sections {
+		paths = append(paths, section.Path)
+	}
+	assert.Equal(t, [][]string{nil, {"Fruit"}, {"Fruit", "Code"}, {"Next"}}, paths)
+	assert.Len(t, doc.blocks, 1)
+	assert.Equal(t, "#+BEGIN_SRC python\n* not a heading\n#+END_SRC", text[doc.blocks[0][0]:doc.blocks[0][1]])
+}
+
+func TestSplitOrg(t *testing.T) {
+	text := "* Fruit\nApples are red.\n** Code\nRun:\n#+begin_src sh\necho one two\n#+end_src"
+	chunks := newWordSplitter(t, 6, 0).SplitOrg(text)
+
+	assert.Equal(t, []string{"* Fruit\nApples are red.", "** Code\nRun:", "#+begin_src sh\necho one two\n#+end_src"}, chunkTexts(chunks))
+	assert.Equal(t, []string{"Fruit", "Code"}, chunks[2].Metadata.HeadingPath)
+}
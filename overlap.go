@@ -0,0 +1,96 @@
+package semchunk
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WithMinOverlap guarantees that consecutive chunks share at least n tokens. Where the
+// overlap at split granularity is smaller, for example because the splits are large, the
+// start of the following chunk is moved back into the previous one, to a word start if
+// possible and otherwise character by character. Chunks are merged up to the chunk size
+// less n tokens, so the duplicated text fits.
+func WithMinOverlap(n int) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.MinOverlap = n
+	}
+}
+
+// WithParagraphBreakOverlap enables or disables overlap between chunks that meet at a
+// paragraph break, which is enabled by default. When disabled, chunks only overlap where
+// their boundary falls inside a paragraph, so text already divided into paragraphs is not
+// duplicated; this also applies to the overlap guaranteed by WithMinOverlap.
+func WithParagraphBreakOverlap(enabled bool) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.NoParagraphBreakOverlap = !enabled
+	}
+}
+
+// overlapAt returns the overlap after a chunk merged from splits of level, none between
+// paragraphs if overlap at paragraph breaks is disabled
+func (c *TextSplitter) overlapAt(level SplitLevel) func(size int) int {
+	if c.opts != nil && c.opts.NoParagraphBreakOverlap && level == LevelParagraph {
+		return func(int) int { return 0 }
+	}
+	return c.overlapFor
+}
+
+// ensureOverlap extends the start of every chunk that shares fewer than MinOverlap tokens
+// with the previous one. chunkSize is the size the extended chunks should fit in.
+func (c *TextSplitter) ensureOverlap(text string, chunks []Chunk, chunkSize int) []Chunk {
+	n := c.opts.MinOverlap
+	for i := 0; i+1 < len(chunks); i++ {
+		a, b := chunks[i], chunks[i+1]
+		if b.Start < a.Start || a.End < b.Start && strings.TrimSpace(text[a.End:b.Start]) != "" {
+			// only chunks that follow each other in the text can share content
+			continue
+		}
+		if c.opts.NoParagraphBreakOverlap && a.End <= b.Start && blankLineRegex.MatchString(text[a.End:b.Start]) {
+			continue
+		}
+		if b.Start < a.End && c.countTokenFunc(text[b.Start:a.End]) >= n {
+			continue
+		}
+		if start, ok := c.overlapStart(text, a, b, n, chunkSize); ok {
+			chunks[i+1].Start, chunks[i+1].Text = start, text[start:b.End]
+		}
+	}
+	return chunks
+}
+
+// overlapStart finds the latest start in a for b whose text up to the end of a has at least n
+// tokens, preferring word starts. It reports false if b would not stay within chunkSize.
+func (c *TextSplitter) overlapStart(text string, a, b Chunk, n int, chunkSize int) (int, bool) {
+	shared := func(start int) bool { return c.countTokenFunc(text[start:a.End]) >= n }
+	fits := func(start int) bool { return c.countTokenFunc(text[start:b.End]) <= chunkSize }
+	for start := a.End; start > a.Start; {
+		_, size := utf8.DecodeLastRuneInString(text[a.Start:start])
+		start -= size
+		r, _ := utf8.DecodeRuneInString(text[start:])
+		prev, _ := utf8.DecodeLastRuneInString(text[:start])
+		wordStart := start == a.Start || !unicode.IsSpace(r) && unicode.IsSpace(prev)
+		if !wordStart || !shared(start) {
+			continue
+		}
+		if fits(start) {
+			return start, true
+		}
+		break
+	}
+	// fall back to the character granularity
+	for start := a.End; start > a.Start; {
+		_, size := utf8.DecodeLastRuneInString(text[a.Start:start])
+		start -= size
+		if shared(start) {
+			return start, fits(start)
+		}
+	}
+	return a.Start, a.Start < b.Start && fits(a.Start)
+}
@@ -0,0 +1,54 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMinOverlap(t *testing.T) {
+	text := "Alpha beta gamma delta. Epsilon zeta eta theta."
+	assert.Equal(t, []string{"Alpha beta gamma delta.", "Epsilon zeta eta theta."}, newWordSplitter(t, 6, 0).Split(text))
+
+	chunks := newWordSplitter(t, 6, 0, WithMinOverlap(2)).SplitChunks(text)
+	assert.Equal(t, []string{"Alpha beta gamma delta.", "gamma delta. Epsilon zeta eta theta."}, chunkTexts(chunks))
+	assert.Equal(t, text[chunks[1].Start:chunks[1].End], chunks[1].Text)
+	assert.Equal(t, 2, chunks[1].Metadata.OverlapTokens)
+
+	// without word boundaries the overlap is taken character by character
+	splitter, err := NewTextSplitter(5, 0, nil, WithSizeUnit(SizeRunes), WithMinOverlap(2))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"一二三", "二三四五六", "五六七八"}, splitter.Split("一二三四五六七八"))
+
+	_, err = NewTextSplitter(5, 0, nil, WithSizeUnit(SizeRunes), WithMinOverlap(5))
+	assert.Error(t, err)
+}
+
+func TestOverlapStartChunkSize(t *testing.T) {
+	text := "alpha beta gamma delta"
+	a, b := Chunk{Start: 0, End: 10}, Chunk{Start: 11, End: 22}
+	splitter := newWordSplitter(t, 3, 0)
+	start, ok := splitter.overlapStart(text, a, b, 1, 3)
+	assert.True(t, ok)
+	assert.Equal(t, "beta gamma delta", text[start:b.End])
+
+	// neither the word start nor any character start leaves b within the chunk size
+	_, ok = splitter.overlapStart(text, a, b, 1, 2)
+	assert.False(t, ok)
+}
+
+func TestWithParagraphBreakOverlap(t *testing.T) {
+	text := "a b\n\nc d\n\ne f\n\ng h"
+	assert.Equal(t, []string{"a b\n\nc d", "c d\n\ne f", "e f\n\ng h"}, newWordSplitter(t, 4, 2).Split(text))
+	splitter := newWordSplitter(t, 4, 2, WithParagraphBreakOverlap(false))
+	assert.Equal(t, []string{"a b\n\nc d", "e f\n\ng h"}, splitter.Split(text))
+
+	// chunks inside a paragraph still overlap
+	assert.Equal(t, []string{"one two three four", "three four five six"}, splitter.Split("one two three four five six"))
+
+	// nor is the minimum overlap added at paragraph breaks
+	text = "Alpha beta gamma delta.\n\nEpsilon zeta eta theta."
+	splitter = newWordSplitter(t, 6, 0, WithMinOverlap(2), WithParagraphBreakOverlap(false))
+	assert.Equal(t, []string{"Alpha beta gamma delta.", "Epsilon zeta eta theta."}, splitter.Split(text))
+	assert.Equal(t, []string{"Alpha beta gamma delta.", "gamma delta. Epsilon zeta eta theta."}, splitter.Split("Alpha beta gamma delta. Epsilon zeta eta theta."))
+}
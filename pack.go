@@ -0,0 +1,128 @@
+package semchunk
+
+import "sort"
+
+// PackOption configures Pack
+type PackOption struct {
+	// Reorder allows texts to be moved between groups to reduce the number of groups.
+	// Within a group, texts always keep their original relative order.
+	Reorder bool
+	// Separator is the text the texts of a group are joined with; its tokens count against the budget.
+	Separator string
+}
+
+// WithReorder allows texts to be moved between groups to reduce the number of groups
+func WithReorder(reorder bool) func(*PackOption) {
+	return func(opts *PackOption) {
+		if opts == nil {
+			opts = &PackOption{}
+		}
+		opts.Reorder = reorder
+	}
+}
+
+// WithPackSeparator sets the separator the texts of a group are joined with, such as "\n\n",
+// so that the joined group stays within budget
+func WithPackSeparator(separator string) func(*PackOption) {
+	return func(opts *PackOption) {
+		if opts == nil {
+			opts = &PackOption{}
+		}
+		opts.Separator = separator
+	}
+}
+
+// Pack groups texts into as few groups as possible whose total token count stays within budget.
+// By default groups are filled in input order; with WithReorder(true) texts are bin-packed
+// first-fit decreasing. The tokens of the separator set with WithPackSeparator are counted
+// between the texts of a group. A text that exceeds budget on its own is placed in a group by itself.
+func (c *TextSplitter) Pack(texts []string, budget int, opts ...func(*PackOption)) [][]string {
+	packOpts := &PackOption{}
+	for _, opt := range opts {
+		opt(packOpts)
+	}
+
+	sizes := c.countTokensBatch(texts)
+	separatorSize := 0
+	if packOpts.Separator != "" {
+		separatorSize = c.countTokenFunc(packOpts.Separator)
+	}
+	var groups [][]int
+	if packOpts.Reorder {
+		groups = packFirstFitDecreasing(sizes, separatorSize, budget)
+	} else {
+		groups = packInOrder(sizes, separatorSize, budget)
+	}
+
+	result := make([][]string, len(groups))
+	for i, group := range groups {
+		result[i] = make([]string, len(group))
+		for j, idx := range group {
+			result[i][j] = texts[idx]
+		}
+	}
+	return result
+}
+
+// packInOrder fills groups with consecutive items, starting a new group when the budget is reached.
+// Every item after the first of a group adds separatorSize.
+func packInOrder(sizes []int, separatorSize int, budget int) [][]int {
+	groups := make([][]int, 0)
+	group := make([]int, 0)
+	size := 0
+	for i, l := range sizes {
+		if len(group) > 0 && size+separatorSize+l > budget {
+			groups = append(groups, group)
+			group = make([]int, 0)
+			size = 0
+		}
+		if len(group) > 0 {
+			size += separatorSize
+		}
+		group = append(group, i)
+		size += l
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// packFirstFitDecreasing places the largest items first, each into the first group it fits.
+// Every item after the first of a group adds separatorSize.
+func packFirstFitDecreasing(sizes []int, separatorSize int, budget int) [][]int {
+	order := make([]int, len(sizes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return sizes[order[a]] > sizes[order[b]]
+	})
+
+	groups := make([][]int, 0)
+	groupSizes := make([]int, 0)
+	for _, idx := range order {
+		placed := false
+		for g := range groups {
+			if groupSizes[g]+separatorSize+sizes[idx] <= budget {
+				groups[g] = append(groups[g], idx)
+				groupSizes[g] += separatorSize + sizes[idx]
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			groups = append(groups, []int{idx})
+			groupSizes = append(groupSizes, sizes[idx])
+		}
+	}
+
+	// keep the original order inside groups and order groups by their first item
+	for _, group := range groups {
+		sort.Ints(group)
+	}
+	sort.Slice(groups, func(a, b int) bool {
+		return groups[a][0] < groups[b][0]
+	})
+	return groups
+}
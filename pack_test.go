@@ -0,0 +1,57 @@
+package semchunk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPack(t *testing.T) {
+	splitter := &TextSplitter{
+		countTokenFunc: func(text string) int { return len(text) },
+		opts:           &TextSplitterOption{},
+	}
+
+	texts := []string{"aaaaaa", "bbb", "cccc", "dd", "eeeeeeeeeeee"}
+
+	tests := []struct {
+		name    string
+		reorder bool
+		want    [][]string
+	}{
+		{
+			name: "in order",
+			want: [][]string{{"aaaaaa", "bbb"}, {"cccc", "dd"}, {"eeeeeeeeeeee"}},
+		},
+		{
+			name:    "reorder",
+			reorder: true,
+			want:    [][]string{{"aaaaaa", "cccc"}, {"bbb", "dd"}, {"eeeeeeeeeeee"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitter.Pack(texts, 10, WithReorder(tt.reorder))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPackSeparator(t *testing.T) {
+	splitter := &TextSplitter{
+		countTokenFunc: func(text string) int { return len(text) },
+		opts:           &TextSplitterOption{},
+	}
+
+	texts := []string{"aaaa", "bbb", "cc", "d"}
+	assert.Equal(t, [][]string{{"aaaa", "bbb", "cc", "d"}}, splitter.Pack(texts, 10))
+	for _, reorder := range []bool{false, true} {
+		groups := splitter.Pack(texts, 10, WithPackSeparator("\n\n"), WithReorder(reorder))
+		assert.Len(t, groups, 2)
+		for _, group := range groups {
+			assert.LessOrEqual(t, len(strings.Join(group, "\n\n")), 10, group)
+		}
+	}
+}
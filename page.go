@@ -0,0 +1,68 @@
+package semchunk
+
+import (
+	"sort"
+	"strings"
+)
+
+// LevelPage is the level of splits made at page markers, see WithPageMarkers
+const LevelPage SplitLevel = "page"
+
+// WithPageMarkers makes the splitter prefer breaking text at page markers, such as the form
+// feeds PDF extractors emit between pages, over any other separator, and records the pages a
+// chunk spans in its metadata. Without markers, the form feed "\f" is used.
+func WithPageMarkers(markers ...string) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		if len(markers) == 0 {
+			markers = []string{"\f"}
+		}
+		opts.PageMarkers = markers
+	}
+}
+
+// splitPages splits text at the first page marker it contains
+func splitPages(text string, markers []string) (textSplit, bool) {
+	for _, marker := range markers {
+		if marker != "" && strings.Contains(text, marker) {
+			return textSplit{splitter: marker, isWhitespace: strings.TrimSpace(marker) == "", level: LevelPage, splits: strings.Split(text, marker)}, true
+		}
+	}
+	return textSplit{}, false
+}
+
+// pageBreaks returns the sorted offsets just after every page marker in text
+func pageBreaks(text string, markers []string) []int {
+	breaks := make([]int, 0)
+	for _, marker := range markers {
+		if marker == "" {
+			continue
+		}
+		for offset := 0; ; {
+			i := strings.Index(text[offset:], marker)
+			if i < 0 {
+				break
+			}
+			offset += i + len(marker)
+			breaks = append(breaks, offset)
+		}
+	}
+	sort.Ints(breaks)
+	return breaks
+}
+
+// setPages records the first and last page of every chunk, numbered from 1
+func setPages(chunks []Chunk, breaks []int) {
+	page := func(offset int) int {
+		return 1 + sort.Search(len(breaks), func(i int) bool { return breaks[i] > offset })
+	}
+	for i := range chunks {
+		chunks[i].Metadata.Page = page(chunks[i].Start)
+		chunks[i].Metadata.PageEnd = chunks[i].Metadata.Page
+		if chunks[i].End > chunks[i].Start {
+			chunks[i].Metadata.PageEnd = page(chunks[i].End - 1)
+		}
+	}
+}
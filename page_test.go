@@ -0,0 +1,33 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPageMarkers(t *testing.T) {
+	text := "First page.\nStill first.\fSecond page here.\fThird."
+
+	chunks := newWordSplitter(t, 5, 0, WithPageMarkers()).SplitChunks(text)
+	assert.Equal(t, []string{"First page.\nStill first.", "Second page here.\fThird."}, chunkTexts(chunks))
+	assert.Equal(t, LevelPage, chunks[0].Metadata.Level)
+	assert.Equal(t, [2]int{1, 1}, [2]int{chunks[0].Metadata.Page, chunks[0].Metadata.PageEnd})
+	assert.Equal(t, [2]int{2, 3}, [2]int{chunks[1].Metadata.Page, chunks[1].Metadata.PageEnd})
+
+	chunks = newWordSplitter(t, 100, 0, WithPageMarkers()).SplitChunks(text)
+	assert.Len(t, chunks, 1)
+	assert.Equal(t, 1, chunks[0].Metadata.Page)
+	assert.Equal(t, 3, chunks[0].Metadata.PageEnd)
+
+	// without page markers the form feed is only whitespace
+	chunks = newWordSplitter(t, 4, 0).SplitChunks(text)
+	assert.Zero(t, chunks[0].Metadata.Page)
+}
+
+func TestCustomPageMarkers(t *testing.T) {
+	text := "One two.\n--- page ---\nThree four."
+	chunks := newWordSplitter(t, 3, 0, WithPageMarkers("\n--- page ---\n")).SplitChunks(text)
+	assert.Equal(t, []string{"One two.", "Three four."}, chunkTexts(chunks))
+	assert.Equal(t, 2, chunks[1].Metadata.Page)
+}
@@ -0,0 +1,48 @@
+package semchunk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ParentDocument is the record written for every document by ExportParents
+type ParentDocument struct {
+	Document
+	// Chunks is the number of chunks of the document
+	Chunks int `json:"chunks"`
+}
+
+// ExportParents splits docs for parent-document retrieval, where chunks are matched but the
+// whole document they belong to is handed on. The chunks are written to chunks and the
+// documents to parents, both as JSON lines. Chunks carry the ID of their document in
+// Metadata.DocumentID, so every document needs a unique ID.
+func (c *TextSplitter) ExportParents(ctx context.Context, docs []Document, chunks io.Writer, parents io.Writer) error {
+	seen := make(map[string]bool, len(docs))
+	for i, doc := range docs {
+		if doc.ID == "" {
+			return fmt.Errorf("document %d: no ID", i)
+		}
+		if seen[doc.ID] {
+			return fmt.Errorf("document %d: duplicate ID %q", i, doc.ID)
+		}
+		seen[doc.ID] = true
+	}
+
+	chunkSink, parentEnc := NewJSONLSink(chunks), json.NewEncoder(parents)
+	var seq int64
+	return c.splitDocuments(ctx, docs, func(i int, docChunks []Chunk) error {
+		if err := parentEnc.Encode(ParentDocument{Document: docs[i], Chunks: len(docChunks)}); err != nil {
+			return err
+		}
+		for _, chunk := range docChunks {
+			seq++
+			chunk.Seq = seq
+			if err := chunkSink.Write(chunk); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
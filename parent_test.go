@@ -0,0 +1,43 @@
+package semchunk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportParents(t *testing.T) {
+	splitter := newWordSplitter(t, 3, 0)
+	docs := []Document{
+		{ID: "a", Title: "First", Text: "One two three. Four five."},
+		{ID: "b", Text: "Six seven."},
+	}
+
+	var chunks, parents bytes.Buffer
+	assert.NoError(t, splitter.ExportParents(context.Background(), docs, &chunks, &parents))
+
+	var records []ParentDocument
+	for _, line := range strings.Split(strings.TrimSpace(parents.String()), "\n") {
+		var record ParentDocument
+		assert.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	assert.Equal(t, []ParentDocument{{Document: docs[0], Chunks: 2}, {Document: docs[1], Chunks: 1}}, records)
+
+	ids := make([]string, 0)
+	for _, line := range strings.Split(strings.TrimSpace(chunks.String()), "\n") {
+		var chunk Chunk
+		assert.NoError(t, json.Unmarshal([]byte(line), &chunk))
+		ids = append(ids, ChunkID(chunk))
+	}
+	assert.Equal(t, []string{"a#0", "a#1", "b#0"}, ids)
+
+	err := splitter.ExportParents(context.Background(), []Document{{ID: "a"}, {ID: "a"}}, &chunks, &parents)
+	assert.ErrorContains(t, err, "duplicate ID")
+	err = splitter.ExportParents(context.Background(), []Document{{Text: "x"}}, &chunks, &parents)
+	assert.ErrorContains(t, err, "no ID")
+}
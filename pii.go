@@ -0,0 +1,69 @@
+package semchunk
+
+// PIICategory is a kind of personal data detected in chunks, see WithPIIDetection
+type PIICategory string
+
+const (
+	PIIEmail      PIICategory = "email"
+	PIIPhone      PIICategory = "phone"
+	PIICreditCard PIICategory = "credit_card"
+	// PIIIDNumber is a national ID number, currently a US social security number
+	PIIIDNumber PIICategory = "id_number"
+)
+
+// WithPIIDetection lists the categories of personal data found in every chunk in
+// Metadata.PII, so stores can apply access controls per chunk. Only the given categories
+// are reported, all of them if none are given. Detection uses the patterns of RedactPII and
+// looks at the chunk before it is redacted.
+func WithPIIDetection(categories ...PIICategory) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.DetectPII = true
+		opts.PIICategories = categories
+	}
+}
+
+// DetectPII returns the categories of personal data in text, in the order of RedactPII's patterns
+func DetectPII(text string) []PIICategory {
+	found := make(map[PIICategory]bool)
+	replacePII(text, func(pattern piiPattern, match string) string {
+		found[pattern.category] = true
+		return pattern.replacement
+	})
+	categories := make([]PIICategory, 0, len(found))
+	for _, pattern := range piiPatterns {
+		if found[pattern.category] {
+			categories = append(categories, pattern.category)
+		}
+	}
+	return categories
+}
+
+// tagPII sets Metadata.PII of chunks if WithPIIDetection is set
+func (c *TextSplitter) tagPII(chunks []Chunk) {
+	if !c.opts.DetectPII {
+		return
+	}
+	for i := range chunks {
+		for _, category := range DetectPII(chunks[i].Text) {
+			if c.reportsPII(category) {
+				chunks[i].Metadata.PII = append(chunks[i].Metadata.PII, category)
+			}
+		}
+	}
+}
+
+// reportsPII reports whether category is one of those passed to WithPIIDetection
+func (c *TextSplitter) reportsPII(category PIICategory) bool {
+	if len(c.opts.PIICategories) == 0 {
+		return true
+	}
+	for _, reported := range c.opts.PIICategories {
+		if reported == category {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectPII(t *testing.T) {
+	tests := []struct {
+		text string
+		want []PIICategory
+	}{
+		{"Nothing personal here.", []PIICategory{}},
+		{"Call +1 415-555-0132 or mail jane@example.com.", []PIICategory{PIIEmail, PIIPhone}},
+		{"Card 4111 1111 1111 1111, SSN 078-05-1120.", []PIICategory{PIICreditCard, PIIIDNumber}},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, DetectPII(tt.text), tt.text)
+	}
+}
+
+func TestWithPIIDetection(t *testing.T) {
+	text := "Mail jane@example.com now. Call +1 415-555-0132 today. Nothing else here."
+
+	chunks := newWordSplitter(t, 4, 0, WithPIIDetection(), WithRedactor(RedactPII)).SplitChunks(text)
+	assert.Equal(t, []string{"Mail [EMAIL] now.", "Call [PHONE] today.", "Nothing else here."}, chunkTexts(chunks))
+	assert.Equal(t, []PIICategory{PIIEmail}, chunks[0].Metadata.PII)
+	assert.Equal(t, []PIICategory{PIIPhone}, chunks[1].Metadata.PII)
+	assert.Empty(t, chunks[2].Metadata.PII)
+
+	chunks = newWordSplitter(t, 4, 0, WithPIIDetection(PIIPhone)).SplitChunks(text)
+	assert.Empty(t, chunks[0].Metadata.PII)
+	assert.Equal(t, []PIICategory{PIIPhone}, chunks[1].Metadata.PII)
+
+	for _, chunk := range newWordSplitter(t, 4, 0).SplitChunks(text) {
+		assert.Empty(t, chunk.Metadata.PII)
+	}
+}
@@ -0,0 +1,338 @@
+package semchunk
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+)
+
+// PreservePreset is a predefined set of patterns kept intact when splitting
+type PreservePreset string
+
+const (
+	// PresetSocial keeps #hashtags, @handles and multi-codepoint emoji sequences intact
+	PresetSocial PreservePreset = "social"
+	// PresetPaths keeps Unix and Windows file paths, dotted identifiers such as pkg.module.Func,
+	// and semantic versions intact
+	PresetPaths PreservePreset = "paths"
+	// PresetTimestamps keeps ISO 8601 timestamps, clock times and common date formats intact
+	PresetTimestamps PreservePreset = "timestamps"
+	// PresetEmails keeps email addresses intact
+	PresetEmails PreservePreset = "emails"
+)
+
+// emoji approximates a single emoji: a pictograph with optional variation selector and skin
+// tone modifier, a regional indicator flag, or a keycap
+const emoji = `(?:[\x{1F1E6}-\x{1F1FF}]{2}` +
+	`|[0-9#*]\x{FE0F}?\x{20E3}` +
+	`|[\x{1F000}-\x{1FAFF}\x{2300}-\x{23FF}\x{2600}-\x{27BF}\x{2B00}-\x{2BFF}]\x{FE0F}?[\x{1F3FB}-\x{1F3FF}]?)`
+
+// pathComponent is a file name that does not end with a dot, so a path at the end of a
+// sentence does not take the full stop
+const pathComponent = `[\w\-]+(?:\.[\w\-]+)*`
+
+const month = `(?:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Sept|Oct|Nov|Dec)[a-z]*\.?`
+
+var presetPatterns = map[PreservePreset]*regexp.Regexp{
+	PresetEmails: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`),
+	PresetTimestamps: regexp.MustCompile(
+		// ISO 8601 dates with optional time and zone
+		`\b\d{4}-\d{2}-\d{2}(?:[T ]\d{2}:\d{2}(?::\d{2}(?:[.,]\d+)?)?(?:Z|[+-]\d{2}:?\d{2})?)?` +
+			// clock times
+			`|\b\d{1,2}:\d{2}(?::\d{2}(?:\.\d+)?)?(?:\s?[AaPp][Mm]\b)?` +
+			// numeric dates
+			`|\b\d{1,4}[/.]\d{1,2}[/.]\d{1,4}\b` +
+			// dates with month names
+			`|\b` + month + ` \d{1,2},? \d{4}\b|\b\d{1,2} ` + month + `,? \d{4}\b`),
+	PresetPaths: regexp.MustCompile(
+		// Windows paths, whose components may contain single spaces
+		`\b[A-Za-z]:\\(?:[^\\/:*?"<>|\s]+(?: [^\\/:*?"<>|\s]+)*\\)*(?:` + pathComponent + `)?` +
+			// Unix paths, absolute or relative to the home or current directory
+			`|(?:~|\.\.?)?\B/` + pathComponent + `(?:/` + pathComponent + `)*/?` +
+			// semantic versions
+			`|\bv?\d+\.\d+\.\d+(?:-[0-9A-Za-z\-]+(?:\.[0-9A-Za-z\-]+)*)?(?:\+[0-9A-Za-z\-]+(?:\.[0-9A-Za-z\-]+)*)?\b` +
+			// dotted identifiers
+			`|\b[A-Za-z_]\w*(?:\.[A-Za-z_]\w*)+(?:\(\))?`),
+	PresetSocial: regexp.MustCompile(`#[\p{L}\p{N}_]+` +
+		`|@[\p{L}\p{N}_](?:[\p{L}\p{N}_.]*[\p{L}\p{N}_])?` +
+		`|` + emoji + `(?:\x{200D}` + emoji + `)*`),
+}
+
+// WithPreservePresets keeps the patterns of the given presets intact, like WithPreservePatterns
+func WithPreservePresets(presets ...PreservePreset) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		for _, preset := range presets {
+			if pattern, ok := presetPatterns[preset]; ok {
+				opts.PreservePatterns = append(opts.PreservePatterns, pattern)
+			}
+		}
+	}
+}
+
+// PatternResolution decides which preserve pattern matches are kept when several patterns match
+type PatternResolution int
+
+const (
+	// ResolveFirstPattern splits around the matches of the first pattern, in the order the
+	// patterns were added, that matches the text at all. The other patterns are only applied to
+	// the text between those matches when it is split further.
+	ResolveFirstPattern PatternResolution = iota
+	// ResolvePriority splits around the matches of all patterns at once. Where matches
+	// overlap, the match of the pattern added first wins, then the match that starts first.
+	ResolvePriority
+	// ResolveLongest splits around the matches of all patterns at once. Where matches overlap,
+	// the longest match wins, then the match of the pattern added first.
+	ResolveLongest
+)
+
+// patternResolutionNames are the names PatternResolution values are serialized as, in the order of the values
+var patternResolutionNames = []string{"first_pattern", "priority", "longest"}
+
+func (r PatternResolution) MarshalText() ([]byte, error) {
+	return marshalEnum(r, patternResolutionNames)
+}
+
+func (r *PatternResolution) UnmarshalText(text []byte) error {
+	return unmarshalEnum(text, patternResolutionNames, r)
+}
+
+// WithPatternResolution sets how overlapping matches of different preserve patterns are
+// resolved. Patterns are prioritized in the order they were added; the default is ResolveFirstPattern.
+func WithPatternResolution(resolution PatternResolution) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.PatternResolution = resolution
+	}
+}
+
+// preserveMatches returns the ordered, non-overlapping spans of text matched by the preserve patterns
+func (r splitRules) preserveMatches(text string) [][2]int {
+	if r.patternResolution == ResolveFirstPattern {
+		for _, pattern := range r.preservePatterns {
+			if indices := pattern.FindAllStringIndex(text, -1); len(indices) > 0 {
+				matches := make([][2]int, len(indices))
+				for i, index := range indices {
+					matches[i] = [2]int{index[0], index[1]}
+				}
+				return matches
+			}
+		}
+		return nil
+	}
+
+	type candidate struct {
+		start, end, priority int
+	}
+	candidates := make([]candidate, 0)
+	for priority, pattern := range r.preservePatterns {
+		for _, index := range pattern.FindAllStringIndex(text, -1) {
+			if index[0] == index[1] {
+				continue
+			}
+			candidates = append(candidates, candidate{index[0], index[1], priority})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if r.patternResolution == ResolveLongest && a.end-a.start != b.end-b.start {
+			return a.end-a.start > b.end-b.start
+		}
+		if a.priority != b.priority {
+			return a.priority < b.priority
+		}
+		return a.start < b.start
+	})
+
+	// matches are kept ordered by start, so a candidate can only overlap the matches right
+	// before and after the position it would be inserted at
+	matches := make([][2]int, 0)
+	for _, c := range candidates {
+		i := sort.Search(len(matches), func(i int) bool { return matches[i][0] >= c.start })
+		if i > 0 && matches[i-1][1] > c.start || i < len(matches) && matches[i][0] < c.end {
+			continue
+		}
+		matches = append(matches, [2]int{})
+		copy(matches[i+1:], matches[i:])
+		matches[i] = [2]int{c.start, c.end}
+	}
+	return matches
+}
+
+// splitAround splits text into the ordered, non-overlapping matches and the text between them
+func splitAround(text string, matches [][2]int) []string {
+	parts := make([]string, 0, 2*len(matches)+1)
+	lastIndex := 0
+	for _, match := range matches {
+		start, end := match[0], match[1]
+
+		// Add the text before the pattern
+		if start > lastIndex {
+			parts = append(parts, text[lastIndex:start])
+		}
+
+		// Add the pattern itself
+		parts = append(parts, text[start:end])
+
+		lastIndex = end
+	}
+
+	// Add any remaining text
+	if lastIndex < len(text) {
+		parts = append(parts, text[lastIndex:])
+	}
+	return parts
+}
+
+// OversizedPolicy decides what happens to a preserved match that exceeds the chunk size
+type OversizedPolicy int
+
+const (
+	// OversizedEmitWhole emits the match as a single chunk flagged with Metadata.Oversized
+	OversizedEmitWhole OversizedPolicy = iota
+	// OversizedRelease splits matches larger than the threshold like ordinary text, ignoring
+	// the preserve patterns inside them. Smaller matches are emitted whole and flagged.
+	OversizedRelease
+)
+
+// oversizedPolicyNames are the names OversizedPolicy values are serialized as, in the order of the values
+var oversizedPolicyNames = []string{"emit_whole", "release"}
+
+func (p OversizedPolicy) MarshalText() ([]byte, error) {
+	return marshalEnum(p, oversizedPolicyNames)
+}
+
+func (p *OversizedPolicy) UnmarshalText(text []byte) error {
+	return unmarshalEnum(text, oversizedPolicyNames, p)
+}
+
+// WithOversizedMatches sets what happens to preserved matches larger than the chunk size.
+// By default they are emitted whole. With OversizedRelease, matches larger than threshold
+// tokens, or than the chunk size if threshold is 0, are split further.
+func WithOversizedMatches(policy OversizedPolicy, threshold int) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.OversizedMatches = policy
+		opts.OversizedThreshold = threshold
+	}
+}
+
+// WholeMatchPolicy decides what happens to a text that is a single preserved match, such as a
+// long URL or base64 blob on its own, larger than the chunk size
+type WholeMatchPolicy int
+
+const (
+	// WholeMatchDefault emits the match as a single chunk, which is cut at token boundaries
+	// if the counter implements TokenEncoder
+	WholeMatchDefault WholeMatchPolicy = iota
+	// WholeMatchEmit emits the match as a single chunk flagged with Metadata.Oversized, or
+	// splits it as WithOversizedMatches decides for matches inside a text
+	WholeMatchEmit
+	// WholeMatchWrap cuts the match into pieces of a fixed number of characters
+	WholeMatchWrap
+	// WholeMatchError aborts splitting with ErrWholeMatch
+	WholeMatchError
+)
+
+// wholeMatchPolicyNames are the names WholeMatchPolicy values are serialized as, in the order of the values
+var wholeMatchPolicyNames = []string{"default", "emit", "wrap", "error"}
+
+func (p WholeMatchPolicy) MarshalText() ([]byte, error) {
+	return marshalEnum(p, wholeMatchPolicyNames)
+}
+
+func (p *WholeMatchPolicy) UnmarshalText(text []byte) error {
+	return unmarshalEnum(text, wholeMatchPolicyNames, p)
+}
+
+// ErrWholeMatch is returned with WholeMatchError when a text is one preserved match larger
+// than the chunk size
+var ErrWholeMatch = errors.New("text is a single preserved match larger than the chunk size")
+
+// WithWholeMatch sets what happens to a text, or a paragraph or line split from it, that is
+// one preserved match larger than the chunk size. With WholeMatchWrap the match is cut every
+// wrap characters. The error of WholeMatchError is returned by the APIs that return one;
+// Split and SplitChunks return no chunks instead.
+func WithWholeMatch(policy WholeMatchPolicy, wrap int) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.WholeMatch = policy
+		opts.WholeMatchWrap = wrap
+	}
+}
+
+// walkWholeMatch handles a text that is one preserved match, see WithWholeMatch
+func (c *TextSplitter) walkWholeMatch(text string, offset int, chunkSize int, depth int, emit func(Chunk) error) error {
+	size := c.countTokenFunc(text)
+	if size <= chunkSize || c.opts.WholeMatch == WholeMatchDefault {
+		return emit(Chunk{
+			Text:     text,
+			Start:    offset,
+			End:      offset + len(text),
+			Metadata: Metadata{Level: LevelPattern, Depth: depth},
+		})
+	}
+	switch c.opts.WholeMatch {
+	case WholeMatchWrap:
+		for start := 0; start < len(text); {
+			end := start
+			for n := 0; n < c.opts.WholeMatchWrap && end < len(text); n++ {
+				_, size := utf8.DecodeRuneInString(text[end:])
+				end += size
+			}
+			if err := emit(Chunk{
+				Text:     text[start:end],
+				Start:    offset + start,
+				End:      offset + end,
+				Metadata: Metadata{Level: LevelChar, Depth: depth + 1},
+			}); err != nil {
+				return err
+			}
+			start = end
+		}
+		return nil
+	case WholeMatchError:
+		err := fmt.Errorf("%w: %d tokens", ErrWholeMatch, size)
+		c.budget.fail(err)
+		return err
+	}
+	return c.walkOversizedMatch(text, offset, size, chunkSize, depth, emit)
+}
+
+// isPreserved reports whether text is exactly one match of the preserve patterns
+func (c *TextSplitter) isPreserved(text string) bool {
+	matches := c.splitRules().preserveMatches(text)
+	return len(matches) == 1 && matches[0] == [2]int{0, len(text)}
+}
+
+// walkOversizedMatch handles a preserved match of size tokens that exceeds chunkSize
+func (c *TextSplitter) walkOversizedMatch(text string, offset int, size int, chunkSize int, depth int, emit func(Chunk) error) error {
+	threshold := c.opts.OversizedThreshold
+	if threshold <= 0 {
+		threshold = chunkSize
+	}
+	if c.opts.OversizedMatches == OversizedRelease && size > threshold {
+		// split the match as plain text
+		plain := *c
+		plainOpts := *c.opts
+		plainOpts.PreservePatterns = nil
+		plain.opts = &plainOpts
+		return plain.walk(text, offset, chunkSize, depth, emit)
+	}
+	return emit(Chunk{
+		Text:     text,
+		Start:    offset,
+		End:      offset + len(text),
+		Metadata: Metadata{Level: LevelPattern, Depth: depth, Oversized: true},
+	})
+}
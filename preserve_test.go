@@ -0,0 +1,166 @@
+package semchunk
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreservePresets(t *testing.T) {
+	tests := []struct {
+		preset  PreservePreset
+		text    string
+		matches []string
+	}{
+		{
+			PresetSocial,
+			"Loved it #NoFilter, thanks @jane.doe! 👍🏽 👨\u200d👩\u200d👧 🇯🇵 #1",
+			[]string{"#NoFilter", "@jane.doe", "👍🏽", "👨\u200d👩\u200d👧", "🇯🇵", "#1"},
+		},
+		{
+			PresetPaths,
+			`Run /usr/local/bin/foo or ~/bin/x.sh, see C:\Program Files\Go\go.exe. Call pkg.module.Func() in v1.2.3-rc.1, not 1.2 or km/s.`,
+			[]string{"/usr/local/bin/foo", "~/bin/x.sh", `C:\Program Files\Go\go.exe`, "pkg.module.Func()", "v1.2.3-rc.1"},
+		},
+		{
+			PresetTimestamps,
+			"At 2024-01-02T15:04:05Z, then 15:04:05 and 9:30 pm on 01/02/2024, Jan. 2, 2024 or 2 January 2024.",
+			[]string{"2024-01-02T15:04:05Z", "15:04:05", "9:30 pm", "01/02/2024", "Jan. 2, 2024", "2 January 2024"},
+		},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.matches, presetPatterns[tt.preset].FindAllString(tt.text, -1), tt.preset)
+	}
+}
+
+func TestSplitPreservesEmojiSequences(t *testing.T) {
+	countRunes := func(text string) int { return len([]rune(text)) }
+	splitter, err := NewTextSplitter(2, 0, countRunes, WithPreservePresets(PresetSocial))
+	assert.NoError(t, err)
+
+	for _, chunk := range splitter.Split("ab👨\u200d👩\u200d👧cd") {
+		assert.NotContains(t, []string{"👨", "👩", "👧"}, chunk)
+	}
+	assert.Contains(t, splitter.Split("ab👨\u200d👩\u200d👧cd"), "👨\u200d👩\u200d👧")
+}
+
+func TestPatternResolution(t *testing.T) {
+	text := "x foo bar baz qux y 12:30 and 2024-01-02T12:30"
+	tests := []struct {
+		resolution PatternResolution
+		splits     []string
+	}{
+		{ResolveFirstPattern, []string{"x ", "foo bar", " baz qux y 12:30 and 2024-01-02T12:30"}},
+		{ResolvePriority, []string{"x ", "foo bar", " baz qux y ", "12:30", " and 2024-01-02T", "12:30"}},
+		{ResolveLongest, []string{"x foo ", "bar baz qux", " y ", "12:30", " and ", "2024-01-02T12:30"}},
+	}
+	for _, tt := range tests {
+		splitter, err := NewTextSplitter(100, 0, func(text string) int { return len(text) },
+			WithPreservePatterns("foo bar", "bar baz qux"),
+			WithPreservePatterns("12:30"),
+			WithPreservePresets(PresetTimestamps),
+			WithPatternResolution(tt.resolution))
+		assert.NoError(t, err)
+		assert.Equal(t, tt.splits, semanticSplit(text, splitter.splitRules()).splits, tt.resolution)
+	}
+}
+
+func TestPatternResolutionManyMatches(t *testing.T) {
+	// every "ab" overlaps the "ba" before and after it
+	text := strings.Repeat("ab", 20000)
+	rules := splitRules{
+		preservePatterns:  []*regexp.Regexp{regexp.MustCompile("ab"), regexp.MustCompile("ba")},
+		patternResolution: ResolvePriority,
+	}
+	matches := rules.preserveMatches(text)
+	assert.Len(t, matches, 20000)
+	for i, match := range matches {
+		assert.Equal(t, [2]int{2 * i, 2*i + 2}, match)
+	}
+}
+
+func TestOversizedMatches(t *testing.T) {
+	countRunes := func(text string) int { return len([]rune(text)) }
+	text := "see https://example.com/a/long/path ok"
+	url := "https://example.com/a/long/path"
+
+	tests := []struct {
+		name      string
+		opts      []func(*TextSplitterOption)
+		whole     bool
+		oversized bool
+	}{
+		{"default emits whole", nil, true, true},
+		{"below threshold", []func(*TextSplitterOption){WithOversizedMatches(OversizedRelease, 40)}, true, true},
+		{"released", []func(*TextSplitterOption){WithOversizedMatches(OversizedRelease, 0)}, false, false},
+	}
+	for _, tt := range tests {
+		splitter, err := NewTextSplitter(10, 0, countRunes, append([]func(*TextSplitterOption){WithPreserveURLs(true)}, tt.opts...)...)
+		assert.NoError(t, err)
+
+		chunks := splitter.SplitChunks(text)
+		found := false
+		for _, chunk := range chunks {
+			if chunk.Text == url {
+				found = true
+				assert.Equal(t, tt.oversized, chunk.Metadata.Oversized, tt.name)
+			}
+			assert.Equal(t, chunk.Text, text[chunk.Start:chunk.End], tt.name)
+		}
+		assert.Equal(t, tt.whole, found, tt.name)
+	}
+}
+
+func TestWithWholeMatch(t *testing.T) {
+	blob := "aGVsbG8gd29ybGQgdGhpcyBpcyBhIGJsb2I="
+	text := "https://example.com/" + blob
+
+	tests := []struct {
+		name   string
+		policy WholeMatchPolicy
+		wrap   int
+		want   []string
+	}{
+		{"default", WholeMatchDefault, 0, []string{text}},
+		{"emit", WholeMatchEmit, 0, []string{text}},
+		{"wrap", WholeMatchWrap, 20, []string{text[:20], text[20:40], text[40:]}},
+	}
+	newSplitter := func(t *testing.T, opts ...func(*TextSplitterOption)) *TextSplitter {
+		t.Helper()
+		splitter, err := NewTextSplitter(16, 0, utf8.RuneCountInString, append([]func(*TextSplitterOption){WithPreserveURLs(true)}, opts...)...)
+		assert.NoError(t, err)
+		return splitter
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			splitter := newSplitter(t, WithWholeMatch(tt.policy, tt.wrap))
+			chunks := splitter.SplitChunks(text)
+			texts := make([]string, len(chunks))
+			for i, chunk := range chunks {
+				texts[i] = chunk.Text
+			}
+			assert.Equal(t, tt.want, texts)
+			if tt.policy == WholeMatchEmit {
+				assert.True(t, chunks[0].Metadata.Oversized)
+			}
+		})
+	}
+
+	splitter := newSplitter(t, WithWholeMatch(WholeMatchError, 0))
+	_, err := splitter.SplitChunksContext(context.Background(), text)
+	assert.ErrorIs(t, err, ErrWholeMatch)
+	assert.Nil(t, splitter.Split(text))
+	assert.ErrorIs(t, splitter.SplitTo(text, ChunkSinkFunc(func(Chunk) error { return nil })), ErrWholeMatch)
+
+	// texts that only contain a match are split as before
+	chunks, err := splitter.SplitChunksContext(context.Background(), "see "+text+" for the details of it")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, chunks)
+
+	_, err = NewTextSplitter(4, 0, utf8.RuneCountInString, WithWholeMatch(WholeMatchWrap, 0))
+	assert.Error(t, err)
+}
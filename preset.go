@@ -0,0 +1,70 @@
+package semchunk
+
+import (
+	"fmt"
+	"sort"
+)
+
+// PipelinePreset is a ready-made chunk size, overlap and set of options for a kind of text
+type PipelinePreset struct {
+	Name      string
+	ChunkSize int
+	// Overlap is a ratio of ChunkSize
+	Overlap float32
+	Options []func(*TextSplitterOption)
+}
+
+// Names of the pipeline presets
+const (
+	// PresetRAGDefault splits prose for retrieval: 512 tokens with 15% overlap, keeping URLs
+	// and email addresses intact
+	PresetRAGDefault = "rag-default"
+	// PresetCode splits source code: 256 tokens with 10% overlap, keeping URLs, file paths,
+	// dotted identifiers and versions intact
+	PresetCode = "code"
+	// PresetCJKDocs splits Chinese, Japanese and Korean documents, which may contain runs of
+	// Latin text: 512 tokens with 10% overlap, keeping URLs intact and splitting mixed-script
+	// text into runs first
+	PresetCJKDocs = "cjk-docs"
+)
+
+var pipelinePresets = map[string]PipelinePreset{
+	PresetRAGDefault: {ChunkSize: 512, Overlap: 0.15, Options: []func(*TextSplitterOption){
+		WithPreserveURLs(true),
+		WithPreservePresets(PresetEmails),
+	}},
+	PresetCode: {ChunkSize: 256, Overlap: 0.1, Options: []func(*TextSplitterOption){
+		WithPreserveURLs(true),
+		WithPreservePresets(PresetPaths),
+	}},
+	PresetCJKDocs: {ChunkSize: 512, Overlap: 0.1, Options: []func(*TextSplitterOption){
+		WithPreserveURLs(true),
+		WithScriptSegmentation(true),
+	}},
+}
+
+// Preset returns the pipeline preset called name
+func Preset(name string) (PipelinePreset, error) {
+	preset, ok := pipelinePresets[name]
+	if !ok {
+		return PipelinePreset{}, fmt.Errorf("unknown preset %q", name)
+	}
+	preset.Name = name
+	return preset, nil
+}
+
+// Presets returns the names of all pipeline presets in sorted order
+func Presets() []string {
+	names := make([]string, 0, len(pipelinePresets))
+	for name := range pipelinePresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates a TextSplitter with the settings of the preset. opts are applied after the
+// options of the preset.
+func (p PipelinePreset) New(countTokenFunc func(text string) int, opts ...func(*TextSplitterOption)) (*TextSplitter, error) {
+	return NewTextSplitter(p.ChunkSize, p.Overlap, countTokenFunc, append(append([]func(*TextSplitterOption){}, p.Options...), opts...)...)
+}
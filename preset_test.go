@@ -0,0 +1,42 @@
+package semchunk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreset(t *testing.T) {
+	assert.Equal(t, []string{PresetCJKDocs, PresetCode, PresetRAGDefault}, Presets())
+
+	_, err := Preset("unknown")
+	assert.Error(t, err)
+
+	preset, err := Preset(PresetRAGDefault)
+	assert.NoError(t, err)
+	assert.Equal(t, PresetRAGDefault, preset.Name)
+	assert.Equal(t, 512, preset.ChunkSize)
+
+	splitter, err := preset.New(func(text string) int { return len(strings.Fields(text)) })
+	assert.NoError(t, err)
+	assert.Equal(t, 512, splitter.chunkSize)
+	assert.Equal(t, 76, splitter.overlap)
+
+	// email addresses are kept intact
+	small, err := preset.New(func(text string) int { return len(text) })
+	assert.NoError(t, err)
+	small.chunkSize, small.overlap = 20, 0
+	for _, chunk := range small.Split("write to someone.else@example.com today") {
+		if strings.Contains(chunk, "@") {
+			assert.Equal(t, "someone.else@example.com", chunk)
+		}
+	}
+
+	for _, name := range Presets() {
+		preset, err := Preset(name)
+		assert.NoError(t, err)
+		_, err = preset.New(func(text string) int { return len(text) })
+		assert.NoError(t, err, name)
+	}
+}
@@ -0,0 +1,114 @@
+package semchunk
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// WithSentenceTerminators replaces the punctuation that ends a sentence, by default ".", "?"
+// and "!", for example to add "‼", "⁇" or the Urdu full stop "۔". Text is split after any
+// of them followed by whitespace, or at them in text without whitespace, before clause
+// separators are tried. Sentence windows, boundary repair, sentence snapping and eliding find
+// sentence ends with them as well. Full-width punctuation such as "。" is not affected.
+func WithSentenceTerminators(terminators ...string) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.SentenceTerminators = append([]string{}, terminators...)
+	}
+}
+
+// WithClauseSeparators replaces the punctuation that separates clauses, by default "—", "…",
+// ",", ";" and ":", tried one at a time in order, for example to leave out ":" in technical
+// documents full of "key: value" lines. Full-width punctuation such as "，" is not affected.
+func WithClauseSeparators(separators ...string) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.ClauseSeparators = append([]string{}, separators...)
+	}
+}
+
+// WithClauseSplitting enables or disables splitting at clause separators, ASCII and
+// full-width, which is enabled by default. When disabled, text is only split between
+// paragraphs, lines and sentences; a sentence larger than the chunk size is split between
+// words rather than at its commas.
+func WithClauseSplitting(enabled bool) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.NoClauseSplitting = !enabled
+	}
+}
+
+// multiCharTerminators are split at as a whole rather than at each of their characters
+var multiCharTerminators = []string{"...", "……", "?!", "!?", "？！", "！？"}
+
+// maxTerminatorRunes is the longest run of punctuation split at as a terminator; longer runs,
+// such as the dot leaders of a table of contents, are not split at
+const maxTerminatorRunes = 4
+
+// splitPunctuation splits text at splitter like strings.Split, except that a multi-character
+// terminator containing splitter, such as "..." or "?!", is split at as a whole, together with
+// any repetitions of splitter following it, unless the run is longer than maxTerminatorRunes
+func splitPunctuation(text string, splitter string, level SplitLevel) textSplit {
+	ts := textSplit{splitter: splitter, level: level}
+	hasUnit := false
+	for _, unit := range multiCharTerminators {
+		if strings.Contains(unit, splitter) && strings.Contains(text, unit) {
+			hasUnit = true
+			break
+		}
+	}
+	if !hasUnit {
+		ts.splits = strings.Split(text, splitter)
+		return ts
+	}
+
+	last, from := 0, 0
+	for {
+		i := strings.Index(text[from:], splitter)
+		if i < 0 {
+			break
+		}
+		start, end := from+i, from+i+len(splitter)
+		if unitStart, unitEnd, ok := terminatorAt(text, start, last, splitter); ok {
+			start, end = unitStart, unitEnd
+			for strings.HasPrefix(text[end:], splitter) {
+				end += len(splitter)
+			}
+			if utf8.RuneCountInString(text[start:end]) > maxTerminatorRunes {
+				// the run stays in its split
+				from = end
+				continue
+			}
+		}
+		ts.splits = append(ts.splits, text[last:start])
+		ts.starts = append(ts.starts, last)
+		last, from = end, end
+	}
+	ts.splits = append(ts.splits, text[last:])
+	ts.starts = append(ts.starts, last)
+	return ts
+}
+
+// terminatorAt finds a multi-character terminator in text, starting at or after from, that
+// contains the splitter found at i
+func terminatorAt(text string, i int, from int, splitter string) (int, int, bool) {
+	for _, unit := range multiCharTerminators {
+		for off := strings.Index(unit, splitter); off >= 0 && off+len(splitter) <= len(unit); {
+			if start := i - off; start >= from && strings.HasPrefix(text[start:], unit) {
+				return start, start + len(unit), true
+			}
+			next := strings.Index(unit[off+1:], splitter)
+			if next < 0 {
+				break
+			}
+			off += next + 1
+		}
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,130 @@
+package semchunk
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSentenceTerminators(t *testing.T) {
+	text := "یہ پہلا جملہ ہے۔ یہ دوسرا جملہ ہے۔ یہ تیسرا"
+	assert.Equal(t, []string{"یہ پہلا جملہ ہے۔ یہ", "دوسرا جملہ ہے۔ یہ تیسرا"}, newWordSplitter(t, 5, 0).Split(text))
+
+	splitter := newWordSplitter(t, 5, 0, WithSentenceTerminators(".", "?", "!", "۔"))
+	chunks := splitter.SplitChunks(text)
+	assert.Equal(t, []string{"یہ پہلا جملہ ہے۔", "یہ دوسرا جملہ ہے۔", "یہ تیسرا"}, chunkTexts(chunks))
+	assert.Equal(t, LevelSentence, chunks[0].Metadata.Level)
+
+	// sentence windows and boundary repair use the same terminators
+	windows := splitter.SplitSentenceWindows(text, 0)
+	assert.Equal(t, []string{"یہ پہلا جملہ ہے۔", "یہ دوسرا جملہ ہے۔", "یہ تیسرا"}, chunkTexts(windows))
+	assert.Len(t, newWordSplitter(t, 5, 0).SplitSentenceWindows(text, 0), 1)
+	assert.Equal(t, []int{len("یہ پہلا جملہ ہے۔")}, sentenceEnds("یہ پہلا جملہ ہے۔ یہ", splitter.splitRules().terminators()))
+
+	splitter = newWordSplitter(t, 3, 0, WithSentenceTerminators("‼"))
+	assert.Equal(t, []string{"Stop right there‼", "Do not move.", "Not an inch."}, splitter.Split("Stop right there‼ Do not move. Not an inch."))
+
+	_, err := NewTextSplitter(4, 0, func(string) int { return 1 }, WithSentenceTerminators(""))
+	assert.Error(t, err)
+}
+
+func TestWithClauseSeparators(t *testing.T) {
+	text := "host: example.org port: 8080 user: admin"
+	assert.Equal(t, []string{"host:", "example.org port:", "8080 user:", "admin"}, newWordSplitter(t, 2, 0).Split(text))
+
+	splitter := newWordSplitter(t, 2, 0, WithClauseSeparators("—", "…", ",", ";"))
+	assert.Equal(t, []string{"host: example.org", "port: 8080", "user: admin"}, splitter.Split(text))
+
+	_, err := NewTextSplitter(4, 0, func(string) int { return 1 }, WithClauseSeparators(",", ""))
+	assert.Error(t, err)
+}
+
+func TestWithClauseSplitting(t *testing.T) {
+	text := "The party of the first part, hereinafter the seller, agrees to deliver the goods. Payment is due on delivery."
+	assert.Equal(t, []string{
+		"The party of the first part, hereinafter the seller,",
+		"agrees to deliver the goods.",
+		"Payment is due on delivery.",
+	}, newWordSplitter(t, 10, 0).Split(text))
+
+	splitter := newWordSplitter(t, 10, 0, WithClauseSplitting(false))
+	chunks := splitter.SplitChunks(text)
+	assert.Equal(t, []string{
+		"The party of the first part, hereinafter the seller, agrees",
+		"to deliver the goods.",
+		"Payment is due on delivery.",
+	}, chunkTexts(chunks))
+	for _, chunk := range chunks {
+		assert.NotEqual(t, LevelClause, chunk.Metadata.Level)
+	}
+
+	assert.Equal(t, LevelClause, semanticSplit("甲方，乙方", splitRules{}).level)
+	assert.Equal(t, LevelChar, semanticSplit("甲方，乙方", splitRules{noClauses: true}).level)
+	assert.Equal(t, LevelSentence, semanticSplit("甲方，乙方。丙方", splitRules{noClauses: true}).level)
+}
+
+func TestSplitPunctuation(t *testing.T) {
+	tests := []struct {
+		text      string
+		splitter  string
+		want      []string
+		wantStart []int
+	}{
+		{"a.b.c", ".", []string{"a", "b", "c"}, nil},
+		{"Wait...what.Now", ".", []string{"Wait", "what", "Now"}, []int{0, 7, 12}},
+		{"Wait....what", ".", []string{"Wait", "what"}, []int{0, 8}},
+		{"Really?!No?Yes", "?", []string{"Really", "No", "Yes"}, []int{0, 8, 11}},
+		{"What!?No", "?", []string{"What", "No"}, []int{0, 6}},
+		{"真的？！不是。", "？", []string{"真的", "不是。"}, []int{0, 12}},
+		{"他说……然后", "…", []string{"他说", "然后"}, []int{0, 12}},
+		// a dot leader is not a terminator
+		{"Intro.......5.Next", ".", []string{"Intro.......5", "Next"}, []int{0, 14}},
+		{".......", ".", []string{"......."}, []int{0}},
+	}
+	for _, tt := range tests {
+		ts := splitPunctuation(tt.text, tt.splitter, LevelSentence)
+		assert.Equal(t, tt.want, ts.splits, tt.text)
+		assert.Equal(t, tt.wantStart, ts.starts, tt.text)
+	}
+}
+
+func TestMultiCharTerminators(t *testing.T) {
+	splitter, err := NewTextSplitter(6, 0, utf8.RuneCountInString)
+	assert.NoError(t, err)
+	chunks := splitter.SplitChunks("Wait...what?!Really")
+	assert.Equal(t, []string{"Wait", "what", "Really"}, chunkTexts(chunks))
+	assert.Equal(t, 7, chunks[1].Start)
+	assert.Equal(t, 13, chunks[2].Start)
+
+	// dot leaders are kept and cut within the chunk size
+	for _, opts := range [][]func(*TextSplitterOption){nil, {WithChunkSuffix(" <eoc>")}} {
+		for _, overlap := range []int{0, 1} {
+			size := 3
+			if opts != nil {
+				size = 9
+			}
+			splitter, err := NewTextSplitter(size, overlap, utf8.RuneCountInString, opts...)
+			assert.NoError(t, err)
+			chunks := splitter.SplitChunks("a ....... b")
+			assert.Equal(t, "a", strings.TrimSuffix(chunks[0].Text, " <eoc>"))
+			if overlap == 0 {
+				assert.Equal(t, 7, strings.Count(strings.Join(chunkTexts(chunks), ""), "."))
+			}
+			for _, chunk := range chunks {
+				assert.LessOrEqual(t, chunk.Tokens, size, chunk.Text)
+				assert.LessOrEqual(t, utf8.RuneCountInString(chunk.Text), size, chunk.Text)
+			}
+		}
+	}
+
+	// a terminator longer than the splitter counts towards the chunk size
+	splitter, err = NewTextSplitter(8, 0, nil, WithSizeUnit(SizeRunes))
+	assert.NoError(t, err)
+	chunks = splitter.SplitChunks("等等等……好的好的……再见再见")
+	assert.Equal(t, []string{"等等等", "好的好的", "再见再见"}, chunkTexts(chunks))
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, chunk.Tokens, 8)
+	}
+}
@@ -0,0 +1,225 @@
+package semchunk
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// WithSemchunkCompat makes the splitter reproduce the chunks of the Python semchunk library
+// (semchunk.chunk without overlap) instead of its own semantic splitting, so mixed Python and
+// Go pipelines produce identical chunk boundaries for the same inputs and settings. The chunk
+// size is used as chunk_size and the token counter as token_counter. Overlap is not applied
+// in this mode. Structured splitting such as SplitMarkdown is not affected.
+func WithSemchunkCompat() func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.SemchunkCompat = true
+	}
+}
+
+// pySemanticSplitters are semchunk's _NON_WHITESPACE_SEMANTIC_SPLITTERS, in order
+var pySemanticSplitters = []string{
+	// sentence terminators
+	".", "?", "!", "*",
+	// clause separators
+	";", ",", "(", ")", "[", "]", "“", "”", "‘", "’", "'", "\"", "`",
+	// sentence interrupters
+	":", "—", "…",
+	// word joiners
+	"/", "\\", "–", "&", "-",
+}
+
+// pyMaxRun returns the lexicographically greatest run of runes satisfying f, like Python's
+// max(re.findall(...)). Byte order of UTF-8 strings is code point order.
+func pyMaxRun(text string, f func(rune) bool) string {
+	best := ""
+	start := -1
+	for i, r := range text + "\x00" {
+		if i < len(text) && f(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			if run := text[start:i]; run > best {
+				best = run
+			}
+			start = -1
+		}
+	}
+	return best
+}
+
+// pySplitText is semchunk's _split_text. starts holds the byte offsets of the splits in text.
+func pySplitText(text string) (splitter string, isWhitespace bool, splits []string, starts []int) {
+	isWhitespace = true
+	switch {
+	case strings.ContainsAny(text, "\n\r"):
+		splitter = pyMaxRun(text, func(r rune) bool { return r == '\n' || r == '\r' })
+	case strings.Contains(text, "\t"):
+		splitter = pyMaxRun(text, func(r rune) bool { return r == '\t' })
+	case strings.IndexFunc(text, isPythonSpace) >= 0:
+		splitter = pyMaxRun(text, isPythonSpace)
+		if utf8.RuneCountInString(splitter) == 1 {
+			// prefer whitespace preceded by a semantically meaningful splitter
+			for _, preceder := range pySemanticSplitters {
+				if space, ok := pyFollowingSpace(text, preceder); ok {
+					splits, starts = pySplitAfter(text, preceder, space)
+					return space, isWhitespace, splits, starts
+				}
+			}
+		}
+	default:
+		isWhitespace = false
+		splitter = ""
+		for _, s := range pySemanticSplitters {
+			if strings.Contains(text, s) {
+				splitter = s
+				break
+			}
+		}
+		if splitter == "" {
+			for i, r := range text {
+				splits = append(splits, string(r))
+				starts = append(starts, i)
+			}
+			return "", isWhitespace, splits, starts
+		}
+	}
+	splits = strings.Split(text, splitter)
+	return splitter, isWhitespace, splits, splitOffsets(splits, splitter)
+}
+
+// pyFollowingSpace returns the whitespace character following the first occurrence of
+// preceder that is followed by whitespace
+func pyFollowingSpace(text, preceder string) (string, bool) {
+	for from := 0; ; {
+		i := strings.Index(text[from:], preceder)
+		if i < 0 {
+			return "", false
+		}
+		after := from + i + len(preceder)
+		if r, size := utf8.DecodeRuneInString(text[after:]); size > 0 && isPythonSpace(r) {
+			return text[after : after+size], true
+		}
+		from += i + 1
+	}
+}
+
+// pySplitAfter splits text at every occurrence of space that follows preceder, removing space,
+// like re.split with a lookbehind
+func pySplitAfter(text, preceder, space string) ([]string, []int) {
+	splits := make([]string, 0)
+	starts := []int{0}
+	last := 0
+	for i := 0; i+len(space) <= len(text); {
+		j := strings.Index(text[i:], space)
+		if j < 0 {
+			break
+		}
+		at := i + j
+		if strings.HasSuffix(text[:at], preceder) {
+			splits = append(splits, text[last:at])
+			last = at + len(space)
+			starts = append(starts, last)
+		}
+		i = at + len(space)
+	}
+	return append(splits, text[last:]), starts
+}
+
+// splitSemchunk splits text like semchunk.chunk with offsets=True
+func (c *TextSplitter) splitSemchunk(text string) []Chunk {
+	chunks := c.pyChunk(text, 0)
+	result := make([]Chunk, 0, len(chunks))
+	for _, chunk := range chunks {
+		if strings.TrimFunc(chunk.Text, isPythonSpace) != "" {
+			result = append(result, chunk)
+		}
+	}
+	return result
+}
+
+// pyChunk is the recursive part of semchunk.chunk, offset is the position of text
+func (c *TextSplitter) pyChunk(text string, offset int) []Chunk {
+	splitter, isWhitespace, splits, starts := pySplitText(text)
+
+	// lengths are in characters, as in Python, since they drive the binary search
+	cumLens := make([]int, len(splits)+1)
+	for i, split := range splits {
+		cumLens[i+1] = cumLens[i] + utf8.RuneCountInString(split)
+	}
+
+	chunks := make([]Chunk, 0)
+	skips := make(map[int]bool)
+	for i, split := range splits {
+		if skips[i] {
+			continue
+		}
+		start := offset + starts[i]
+		if c.countTokenFunc(split) > c.chunkSize {
+			chunks = append(chunks, c.pyChunk(split, start)...)
+		} else {
+			end, merged := c.pyMergeSplits(splits, cumLens, splitter, i, len(splits)+1)
+			for j := i + 1; j < end; j++ {
+				skips[j] = true
+			}
+			chunks = append(chunks, Chunk{Text: merged, Start: start, End: start + len(merged)})
+		}
+
+		if isWhitespace || i == len(splits)-1 {
+			continue
+		}
+		allSkipped := true
+		for j := i + 1; j < len(splits); j++ {
+			allSkipped = allSkipped && skips[j]
+		}
+		if allSkipped {
+			continue
+		}
+		// put the non-whitespace splitter back at the end of the last chunk if it fits
+		last := &chunks[len(chunks)-1]
+		if c.countTokenFunc(last.Text+splitter) <= c.chunkSize {
+			last.Text += splitter
+			last.End += len(splitter)
+		} else {
+			at := last.End
+			chunks = append(chunks, Chunk{Text: splitter, Start: at, End: at + len(splitter)})
+		}
+	}
+	return chunks
+}
+
+// pyMergeSplits is semchunk's merge_splits: it finds by binary search, guided by the average
+// number of characters per token, the longest run of splits from start that fits the chunk
+// size, and returns the index after it and the joined run
+func (c *TextSplitter) pyMergeSplits(splits []string, cumLens []int, splitter string, start, high int) (int, string) {
+	average := 0.2
+	low := start
+	offset := cumLens[start]
+	target := float64(offset) + float64(c.chunkSize)*average
+	for low < high {
+		i := low + sort.Search(high-low, func(k int) bool { return float64(cumLens[low+k]) >= target })
+		midpoint := i
+		if midpoint > high-1 {
+			midpoint = high - 1
+		}
+		tokens := c.countTokenFunc(strings.Join(splits[start:midpoint], splitter))
+		localCum := cumLens[midpoint] - offset
+		if localCum != 0 && tokens > 0 {
+			average = float64(localCum) / float64(tokens)
+			target = float64(offset) + float64(c.chunkSize)*average
+		}
+		if tokens > c.chunkSize {
+			high = midpoint
+		} else {
+			low = midpoint + 1
+		}
+	}
+	end := low - 1
+	return end, strings.Join(splits[start:end], splitter)
+}
@@ -0,0 +1,61 @@
+package semchunk
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSemchunkCompat checks the chunks of WithSemchunkCompat against the fixtures written by
+// testdata/semchunk/generate.py, whose generator field records the semchunk version
+func TestSemchunkCompat(t *testing.T) {
+	data, err := os.ReadFile("testdata/semchunk/fixtures.json")
+	assert.NoError(t, err)
+	var fixtures struct {
+		Cases []struct {
+			Text      string   `json:"text"`
+			ChunkSize int      `json:"chunk_size"`
+			Counter   string   `json:"counter"`
+			Chunks    []string `json:"chunks"`
+			Offsets   [][2]int `json:"offsets"`
+		} `json:"cases"`
+	}
+	assert.NoError(t, json.Unmarshal(data, &fixtures))
+	assert.NotEmpty(t, fixtures.Cases)
+
+	counters := map[string]func(string) int{
+		"words": func(text string) int { return len(strings.FieldsFunc(text, isPythonSpace)) },
+		"chars": utf8.RuneCountInString,
+	}
+	for _, tc := range fixtures.Cases {
+		t.Run(fmt.Sprintf("%.20s/%s/%d", tc.Text, tc.Counter, tc.ChunkSize), func(t *testing.T) {
+			splitter, err := NewTextSplitter(tc.ChunkSize, 0, counters[tc.Counter], WithSemchunkCompat())
+			assert.NoError(t, err)
+			chunks := splitter.SplitChunks(tc.Text)
+			assert.Equal(t, tc.Chunks, chunkTexts(chunks))
+
+			// Python offsets count characters
+			byteOffset := make(map[int]int)
+			i := 0
+			for b := range tc.Text {
+				byteOffset[i] = b
+				i++
+			}
+			byteOffset[i] = len(tc.Text)
+			spans := make([][2]int, len(chunks))
+			expected := make([][2]int, len(tc.Offsets))
+			for j, chunk := range chunks {
+				spans[j] = [2]int{chunk.Start, chunk.End}
+			}
+			for j, o := range tc.Offsets {
+				expected[j] = [2]int{byteOffset[o[0]], byteOffset[o[1]]}
+			}
+			assert.Equal(t, expected, spans)
+		})
+	}
+}
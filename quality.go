@@ -0,0 +1,99 @@
+package semchunk
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// continuationWords are words that usually continue a sentence started in a previous chunk
+var continuationWords = []string{
+	"and", "but", "or", "nor", "so", "yet", "because", "which", "that", "then",
+	"however", "although", "though", "while", "whereas",
+}
+
+var closingPunctuation = `"'”’)]}）】」』》`
+
+var bracketPairs = map[rune]rune{
+	')': '(', ']': '[', '}': '{', '）': '（', '】': '【', '」': '「', '』': '『', '》': '《',
+}
+
+// ChunkQuality scores how self-contained a chunk is, from 0 to 1. The score is the fraction of
+// the following checks that pass: the chunk ends with a sentence terminator, it does not start
+// with a lowercase continuation, it does not start with a conjunction, and its brackets and
+// quotes are balanced.
+func ChunkQuality(text string) float64 {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0
+	}
+
+	passed := 0
+	if endsSentence(text, sentenceTerminators) {
+		passed++
+	}
+	if first, _ := utf8.DecodeRuneInString(text); !unicode.IsLower(first) {
+		passed++
+	}
+	if !startsWithContinuationWord(text) {
+		passed++
+	}
+	if balancedBrackets(text) && balancedQuotes(text) {
+		passed++
+	}
+	return float64(passed) / 4
+}
+
+// endsSentence reports whether text ends with one of terminators or a full-width terminator,
+// optionally followed by closing punctuation
+func endsSentence(text string, terminators []string) bool {
+	text = strings.TrimRight(text, closingPunctuation)
+	for _, terminator := range terminators {
+		if strings.HasSuffix(text, terminator) {
+			return true
+		}
+	}
+	for _, terminator := range fullWidthSentenceTerminators {
+		if strings.HasSuffix(text, terminator) {
+			return true
+		}
+	}
+	return false
+}
+
+func startsWithContinuationWord(text string) bool {
+	end := strings.IndexFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	if end < 0 {
+		end = len(text)
+	}
+	word := strings.ToLower(text[:end])
+	for _, continuation := range continuationWords {
+		if word == continuation {
+			return true
+		}
+	}
+	return false
+}
+
+func balancedBrackets(text string) bool {
+	stack := make([]rune, 0)
+	for _, r := range text {
+		switch r {
+		case '(', '[', '{', '（', '【', '「', '『', '《':
+			stack = append(stack, r)
+		case ')', ']', '}', '）', '】', '」', '』', '》':
+			if len(stack) == 0 || stack[len(stack)-1] != bracketPairs[r] {
+				return false
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return len(stack) == 0
+}
+
+func balancedQuotes(text string) bool {
+	return strings.Count(text, `"`)%2 == 0 &&
+		strings.Count(text, "“") == strings.Count(text, "”")
+}
@@ -0,0 +1,30 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunkQuality(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want float64
+	}{
+		{name: "complete sentence", text: "This is a complete sentence.", want: 1},
+		{name: "quoted sentence", text: `He said "hello there."`, want: 1},
+		{name: "chinese sentence", text: "这是一个完整的句子。", want: 1},
+		{name: "no terminator", text: "This is not finished", want: 0.75},
+		{name: "lowercase continuation", text: "continues from before.", want: 0.75},
+		{name: "conjunction", text: "And then it ended.", want: 0.75},
+		{name: "unclosed bracket", text: "It starts (but never", want: 0.5},
+		{name: "empty", text: "  ", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ChunkQuality(tt.text))
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package semchunk
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// zipArchive returns a zip archive of the given files
+func zipArchive(t *testing.T, files map[string]string) *bytes.Reader {
+	t.Helper()
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	for name, content := range files {
+		fw, err := w.Create(name)
+		assert.NoError(t, err)
+		_, err = fw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+	return bytes.NewReader(b.Bytes())
+}
+
+func TestReadDOCX(t *testing.T) {
+	archive := zipArchive(t, map[string]string{
+		"word/document.xml": `<?xml version="1.0" encoding="UTF-8"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+<w:p><w:pPr><w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t>Fruit</w:t></w:r></w:p>
+<w:p><w:r><w:t xml:space="preserve">Apples are </w:t></w:r><w:r><w:t>red.</w:t></w:r></w:p>
+<w:p></w:p>
+<w:p><w:pPr><w:pStyle w:val="Heading2"/></w:pPr><w:r><w:t>Kinds</w:t></w:r></w:p>
+<w:p><w:pPr><w:numPr><w:ilvl w:val="0"/></w:numPr></w:pPr><w:r><w:t>Gala</w:t></w:r></w:p>
+</w:body></w:document>`,
+	})
+
+	text, err := ReadDOCX(archive, archive.Size())
+	assert.NoError(t, err)
+	assert.Equal(t, "# Fruit\n\nApples are red.\n\n## Kinds\n\n- Gala", text)
+
+	chunks, err := newWordSplitter(t, 100, 0).SplitDOCX(archive, archive.Size())
+	assert.NoError(t, err)
+	assert.Len(t, chunks, 2)
+	assert.Equal(t, []string{"Fruit", "Kinds"}, chunks[1].Metadata.HeadingPath)
+}
+
+func TestReadEPUB(t *testing.T) {
+	archive := zipArchive(t, map[string]string{
+		"mimetype": "application/epub+zip",
+		"META-INF/container.xml": `<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+<rootfiles><rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/></rootfiles>
+</container>`,
+		"OEBPS/content.opf": `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+<manifest>
+<item id="c2" href="text/chapter%202.xhtml" media-type="application/xhtml+xml"/>
+<item id="c1" href="text/chapter1.xhtml" media-type="application/xhtml+xml"/>
+</manifest>
+<spine><itemref idref="c1"/><itemref idref="c2"/></spine>
+</package>`,
+		"OEBPS/text/chapter1.xhtml": `<html><head><title>Ignored</title></head><body>
+<h1>One</h1><p>First   paragraph&nbsp;here.</p><ul><li>Item</li></ul></body></html>`,
+		"OEBPS/text/chapter 2.xhtml": `<html><body><h2>Two</h2><pre>x := 1
+y := 2</pre><p>Line<br/>break</p></body></html>`,
+	})
+
+	text, err := ReadEPUB(archive, archive.Size())
+	assert.NoError(t, err)
+	assert.Equal(t, "# One\n\nFirst paragraph here.\n\n- Item\n\n## Two\n\n```\nx := 1\ny := 2\n```\n\nLine break", text)
+
+	chunks, err := newWordSplitter(t, 100, 0).SplitEPUB(archive, archive.Size())
+	assert.NoError(t, err)
+	assert.Len(t, chunks, 2)
+	assert.Equal(t, []string{"One", "Two"}, chunks[1].Metadata.HeadingPath)
+}
@@ -0,0 +1,62 @@
+package semchunk
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// WithRecords makes the matches of separator the top level boundaries of the text, before
+// any other separator, and splits each record between them separately, so no chunk spans two
+// records. This suits exports of tickets or emails. If keepSeparator is set, each match starts
+// a record and is part of it, as the "From " lines of an mbox file; otherwise matches are
+// dropped, as divider lines. separator usually needs the (?m) flag to match at line starts.
+func WithRecords(separator *regexp.Regexp, keepSeparator bool) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.RecordSeparator = separator
+		opts.KeepRecordSeparator = keepSeparator
+	}
+}
+
+// records returns the [start, end) spans of the non-empty records of text, trimmed of
+// surrounding whitespace
+func (c *TextSplitter) records(text string) [][2]int {
+	matches := c.opts.RecordSeparator.FindAllStringIndex(text, -1)
+	spans := make([][2]int, 0, len(matches)+1)
+	add := func(start, end int) {
+		start = skipSpace(text, start)
+		if start < end {
+			end = start + len(strings.TrimRightFunc(text[start:end], unicode.IsSpace))
+		}
+		if start < end {
+			spans = append(spans, [2]int{start, end})
+		}
+	}
+	last := 0
+	for _, m := range matches {
+		if m[1] == m[0] {
+			// an empty match separates nothing
+			continue
+		}
+		add(last, m[0])
+		last = m[1]
+		if c.opts.KeepRecordSeparator {
+			last = m[0]
+		}
+	}
+	add(last, len(text))
+	return spans
+}
+
+// parseRecords divides text into one section per record, see WithRecords
+func (c *TextSplitter) parseRecords(text string) documentStructure {
+	records := c.records(text)
+	sections := make([]Section, len(records))
+	for i, record := range records {
+		sections[i] = Section{Start: record[0], End: record[1]}
+	}
+	return documentStructure{sections: sections}
+}
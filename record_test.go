@@ -0,0 +1,35 @@
+package semchunk
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRecords(t *testing.T) {
+	tickets := "Ticket 1 printer broken\n-----\nTicket 2 login fails\n-----\n\n-----\nTicket 3 slow disk"
+	assert.Equal(t, []string{"Ticket 1 printer broken\n-----\nTicket 2 login fails\n-----", "-----\nTicket 3 slow disk"}, newWordSplitter(t, 10, 0).Split(tickets))
+
+	divided := newWordSplitter(t, 10, 0, WithRecords(regexp.MustCompile(`(?m)^-{5,}$`), false))
+	chunks := divided.SplitChunks(tickets)
+	assert.Equal(t, []string{"Ticket 1 printer broken", "Ticket 2 login fails", "Ticket 3 slow disk"}, chunkTexts(chunks))
+	for _, chunk := range chunks {
+		assert.Equal(t, tickets[chunk.Start:chunk.End], chunk.Text)
+	}
+
+	var streamed []Chunk
+	assert.NoError(t, divided.SplitTo(tickets, ChunkSinkFunc(func(chunk Chunk) error {
+		streamed = append(streamed, chunk)
+		return nil
+	})))
+	assert.Equal(t, chunkTexts(chunks), chunkTexts(streamed))
+
+	// the separators of an mbox start the records
+	mbox := "From alice Mon\nHello Bob, lunch today?\nFrom bob Mon\nSure, at noon. See you there."
+	assert.Equal(t, []string{
+		"From alice Mon\nHello Bob, lunch today?",
+		"From bob Mon",
+		"Sure, at noon. See you there.",
+	}, newWordSplitter(t, 7, 0, WithRecords(regexp.MustCompile(`(?m)^From `), true)).Split(mbox))
+}
@@ -0,0 +1,109 @@
+package semchunk
+
+import (
+	"regexp"
+	"strings"
+)
+
+// WithRedactor applies redact to the text and Metadata.Context of every chunk before it is
+// returned or delivered, for example RedactPII to mask personal data inside the pipeline. Offsets, token counts and
+// the other metadata still describe the chunk in the input text.
+func WithRedactor(redact func(text string) string) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.Redactor = redact
+	}
+}
+
+// piiPattern is a kind of personal data found by a regular expression. Matches for which
+// valid returns false are ignored.
+type piiPattern struct {
+	category    PIICategory
+	regex       *regexp.Regexp
+	replacement string
+	valid       func(match string) bool
+}
+
+var piiPatterns = []piiPattern{
+	{category: PIIEmail, regex: presetPatterns[PresetEmails], replacement: "[EMAIL]"},
+	// card and ID numbers come before phone numbers, which would match parts of them
+	{category: PIICreditCard, regex: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), replacement: "[CARD]", valid: luhnValid},
+	{category: PIIIDNumber, regex: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`), replacement: "[ID]"},
+	{category: PIIPhone, regex: regexp.MustCompile(`(?:\+|\(|\b\d)[\d ().-]{6,}\d\b`), replacement: "[PHONE]", valid: phoneValid},
+}
+
+// RedactPII replaces email addresses, credit card numbers passing the Luhn check, US social
+// security numbers and phone numbers in text with [EMAIL], [CARD], [ID] and [PHONE]. The
+// patterns are simple and aimed at common formats; use WithRedactor with a dedicated
+// detector where recall matters.
+func RedactPII(text string) string {
+	return replacePII(text, func(pattern piiPattern, match string) string {
+		return pattern.replacement
+	})
+}
+
+// replacePII replaces the valid matches of every pattern in turn with the result of replace,
+// so a later pattern does not match what an earlier one replaced
+func replacePII(text string, replace func(pattern piiPattern, match string) string) string {
+	for _, pattern := range piiPatterns {
+		text = pattern.regex.ReplaceAllStringFunc(text, func(match string) string {
+			if pattern.valid != nil && !pattern.valid(match) {
+				return match
+			}
+			return replace(pattern, match)
+		})
+	}
+	return text
+}
+
+// phoneValid reports whether number has as many digits as a phone number with area code, or
+// as an international number if it starts with +
+func phoneValid(number string) bool {
+	digits := len(onlyDigits(number))
+	if strings.HasPrefix(number, "+") {
+		return digits >= 8 && digits <= 15
+	}
+	return digits >= 10 && digits <= 15
+}
+
+// onlyDigits returns the ASCII digits of s
+func onlyDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// luhnValid reports whether the digits of number pass the Luhn checksum of card numbers
+func luhnValid(number string) bool {
+	digits := onlyDigits(number)
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// redact applies the redactor set with WithRedactor to the text of chunks
+func (c *TextSplitter) redact(chunks []Chunk) {
+	if c.opts.Redactor == nil {
+		return
+	}
+	for i := range chunks {
+		chunks[i].Text = c.opts.Redactor(chunks[i].Text)
+		if chunks[i].Metadata.Context != "" {
+			chunks[i].Metadata.Context = c.opts.Redactor(chunks[i].Metadata.Context)
+		}
+	}
+}
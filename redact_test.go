@@ -0,0 +1,49 @@
+package semchunk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactPII(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Write to jane.doe@example.com today.", "Write to [EMAIL] today."},
+		{"Card 4111 1111 1111 1111 on file.", "Card [CARD] on file."},
+		{"Card 4111-1111-1111-1112 is invalid.", "Card 4111-1111-1111-1112 is invalid."},
+		{"Call +1 415-555-0132 or (030) 1234 5678.", "Call [PHONE] or [PHONE]."},
+		{"Chapter 12 has 300 pages.", "Chapter 12 has 300 pages."},
+		{"From 1990-2000 sales grew.", "From 1990-2000 sales grew."},
+		{"SSN 078-05-1120 on record.", "SSN [ID] on record."},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, RedactPII(tt.text), tt.text)
+	}
+}
+
+func TestWithRedactor(t *testing.T) {
+	text := "Mail jane@example.com now. Then call bob@example.org later."
+	splitter := newWordSplitter(t, 4, 0, WithRedactor(RedactPII))
+
+	chunks := splitter.SplitChunks(text)
+	assert.Equal(t, []string{"Mail [EMAIL] now.", "Then call [EMAIL] later."}, chunkTexts(chunks))
+	// offsets still refer to the input
+	assert.Equal(t, "Mail jane@example.com now.", text[chunks[0].Start:chunks[0].End])
+
+	var streamed []string
+	err := splitter.SplitTo(text, ChunkSinkFunc(func(chunk Chunk) error {
+		streamed = append(streamed, chunk.Text)
+		return nil
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, chunkTexts(chunks), streamed)
+
+	levels, err := splitter.SplitMulti(context.Background(), text, []int{8, 4})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Mail [EMAIL] now. Then call [EMAIL] later."}, chunkTexts(levels[0].Chunks))
+	assert.Equal(t, chunkTexts(chunks), chunkTexts(levels[1].Chunks))
+}
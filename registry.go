@@ -0,0 +1,161 @@
+package semchunk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Splitter splits a text of one format into chunks
+type Splitter interface {
+	SplitText(ctx context.Context, text string) ([]Chunk, error)
+}
+
+// SplitterFunc adapts an ordinary function to the Splitter interface
+type SplitterFunc func(ctx context.Context, text string) ([]Chunk, error)
+
+func (f SplitterFunc) SplitText(ctx context.Context, text string) ([]Chunk, error) {
+	return f(ctx, text)
+}
+
+// SplitterFactory creates the Splitter of a format from a configured TextSplitter
+type SplitterFactory func(ts *TextSplitter) Splitter
+
+// Names of the formats registered by default
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+	FormatOrg      = "org"
+	FormatHTML     = "html"
+	FormatCode     = "code"
+	FormatDOCX     = "docx"
+	FormatEPUB     = "epub"
+	// FormatWhisperX is a WhisperX JSON transcript, see SplitTranscript
+	FormatWhisperX = "whisperx"
+)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]SplitterFactory{
+		FormatPlain:    func(ts *TextSplitter) Splitter { return SplitterFunc(ts.SplitChunksContext) },
+		FormatCode:     func(ts *TextSplitter) Splitter { return SplitterFunc(ts.splitCode) },
+		FormatMarkdown: func(ts *TextSplitter) Splitter { return SplitterFunc(ts.SplitMarkdownContext) },
+		FormatOrg:      func(ts *TextSplitter) Splitter { return SplitterFunc(ts.SplitOrgContext) },
+		FormatHTML:     func(ts *TextSplitter) Splitter { return SplitterFunc(ts.splitHTML) },
+		FormatWhisperX: func(ts *TextSplitter) Splitter { return SplitterFunc(ts.splitWhisperX) },
+	}
+)
+
+// Register makes a splitter available under name for SplitFormat, replacing any splitter
+// registered under the same name
+func Register(name string, factory SplitterFactory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[name] = factory
+}
+
+// Formats returns the names of all registered formats in sorted order
+func Formats() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ErrUnknownFormat is returned by SplitFormat for a format nothing is registered for
+var ErrUnknownFormat = errors.New("unknown format")
+
+// SplitFormat splits text with the splitter registered for format. The format "auto" is
+// replaced by the result of Detect on text.
+func (c *TextSplitter) SplitFormat(ctx context.Context, format string, text string) ([]Chunk, error) {
+	if format == "auto" {
+		format = Detect("", text)
+	}
+	registryMu.RLock()
+	factory, ok := registry[format]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+	return factory(c).SplitText(ctx, text)
+}
+
+// ReadHTML converts an HTML document to the Markdown text that the chunks of its FormatHTML
+// splitter refer to
+func ReadHTML(text string) (string, error) {
+	return htmlToMarkdown(strings.NewReader(text))
+}
+
+// splitHTML converts an HTML document to Markdown with ReadHTML and splits it with
+// SplitMarkdown. Chunk offsets refer to the converted text.
+func (c *TextSplitter) splitHTML(ctx context.Context, text string) ([]Chunk, error) {
+	markdown, err := ReadHTML(text)
+	if err != nil {
+		return nil, err
+	}
+	return c.SplitMarkdownContext(ctx, markdown)
+}
+
+var extensionFormats = map[string]string{
+	".txt": FormatPlain, ".text": FormatPlain, ".log": FormatPlain,
+	".md": FormatMarkdown, ".markdown": FormatMarkdown, ".mdx": FormatMarkdown,
+	".org":  FormatOrg,
+	".html": FormatHTML, ".htm": FormatHTML, ".xhtml": FormatHTML,
+	".go": FormatCode, ".py": FormatCode, ".js": FormatCode, ".ts": FormatCode, ".java": FormatCode,
+	".c": FormatCode, ".h": FormatCode, ".cc": FormatCode, ".cpp": FormatCode, ".rs": FormatCode,
+	".rb": FormatCode, ".sh": FormatCode, ".cs": FormatCode, ".kt": FormatCode, ".swift": FormatCode,
+	".php": FormatCode, ".scala": FormatCode, ".sql": FormatCode,
+}
+
+var (
+	htmlSniffRegex     = regexp.MustCompile(`(?i)<(?:!doctype html|html|body|p|div|h[1-6]|br)[\s/>]`)
+	markdownSniffRegex = regexp.MustCompile("(?m)^(?: {0,3}#{1,6} \\S| {0,3}```|\\s*[-*+] \\S|\\s*\\d+\\. \\S|> )|\\]\\([^)]+\\)")
+	orgSniffRegex      = regexp.MustCompile(`(?m)^(?:\*+ \S|#\+[A-Za-z_]+:|#\+(?i:begin_))`)
+	codeSniffRegex     = regexp.MustCompile(`(?m)(?:[;{}]\s*$|^\s*(?:func|def|class|import|package|#include|public|private|const|let|var|fn|return)\b)`)
+)
+
+// Detect guesses the format of a document from the extension of filename, if it is known,
+// and otherwise from its text. It returns one of the formats registered by default that
+// SplitFormat splits text of, FormatPlain if nothing else fits. FormatWhisperX, FormatDOCX and
+// FormatEPUB are never returned; DOCX and EPUB files are read with ReadDOCX and ReadEPUB.
+func Detect(filename string, text string) string {
+	if format, ok := extensionFormats[strings.ToLower(filepath.Ext(filename))]; ok {
+		return format
+	}
+
+	sample := text
+	if len(sample) > 16*1024 {
+		sample = sample[:16*1024]
+	}
+	lines := strings.Count(sample, "\n") + 1
+	trimmed := strings.TrimSpace(sample)
+	switch {
+	case strings.HasPrefix(trimmed, "<") && len(htmlSniffRegex.FindAllStringIndex(sample, 3)) >= 1:
+		return FormatHTML
+	case len(htmlSniffRegex.FindAllStringIndex(sample, -1)) >= 3:
+		return FormatHTML
+	}
+
+	// count the lines that look like each format and pick the clearest signal
+	org := len(orgSniffRegex.FindAllStringIndex(sample, -1))
+	markdown := len(markdownSniffRegex.FindAllStringIndex(sample, -1))
+	code := len(codeSniffRegex.FindAllStringIndex(sample, -1))
+	switch {
+	case org >= 2 && org >= markdown:
+		return FormatOrg
+	case code*4 >= lines && code >= 3 && code > markdown:
+		return FormatCode
+	case markdown >= 2:
+		return FormatMarkdown
+	}
+	return FormatPlain
+}
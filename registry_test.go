@@ -0,0 +1,54 @@
+package semchunk
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		filename string
+		text     string
+		format   string
+	}{
+		{"notes.MD", "", FormatMarkdown},
+		// binary formats are not split by SplitFormat
+		{"book.epub", "", FormatPlain},
+		{"main.go", "", FormatCode},
+		{"", "<!DOCTYPE html><html><body><p>Hi</p></body></html>", FormatHTML},
+		{"", "# Title\n\nSome text with a [link](http://x).\n\n- item\n- item", FormatMarkdown},
+		{"", "#+TITLE: Notes\n* Heading\nText.\n** Sub", FormatOrg},
+		{"", "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(1)\n}\n", FormatCode},
+		{"", "Just some words. Nothing special here.", FormatPlain},
+		{"unknown.xyz", "Just some words.", FormatPlain},
+	}
+	for _, tt := range tests {
+		format := Detect(tt.filename, tt.text)
+		assert.Equal(t, tt.format, format, tt.filename+tt.text)
+		assert.Contains(t, Formats(), format)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("upper", func(ts *TextSplitter) Splitter {
+		return SplitterFunc(func(ctx context.Context, text string) ([]Chunk, error) {
+			return ts.SplitChunksContext(ctx, strings.ToUpper(text))
+		})
+	})
+	assert.Contains(t, Formats(), "upper")
+
+	splitter := newWordSplitter(t, 3, 0)
+	chunks, err := splitter.SplitFormat(context.Background(), "upper", "a b c. d e f.")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"A B C.", "D E F."}, chunkTexts(chunks))
+
+	chunks, err = splitter.SplitFormat(context.Background(), "auto", "# One\n\nA b.\n\n# Two\n\nC d.")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Two"}, chunks[len(chunks)-1].Metadata.HeadingPath)
+
+	_, err = splitter.SplitFormat(context.Background(), "nope", "text")
+	assert.Error(t, err)
+}
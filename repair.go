@@ -0,0 +1,217 @@
+package semchunk
+
+import (
+	"sort"
+	"strings"
+)
+
+// WithBoundaryRepair enables a post-pass that moves the boundary between two adjacent chunks
+// when the first chunk ends mid-sentence or with an unclosed bracket or quote. The boundary is
+// moved to the nearest sentence end within window bytes, as long as both chunks stay within
+// the chunk size. Of overlapping chunks, the end of the first and the start of the second,
+// if it is mid-sentence, are moved separately.
+func WithBoundaryRepair(window int) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.RepairWindow = window
+	}
+}
+
+// WithSentenceSnap enables a post-pass that moves the end of every chunk that does not end a
+// sentence to the nearest sentence end, forward or backward, if no more than tolerance tokens
+// lie between the two and the chunks stay within the chunk size. The start of the following
+// chunk moves along unless the chunks overlap.
+func WithSentenceSnap(tolerance int) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.SnapTolerance = tolerance
+	}
+}
+
+// repairBoundaries shifts dangling boundaries between adjacent chunks to the nearest clean
+// point, snaps chunk ends to sentence ends, pulls headings forward and guarantees the minimum
+// overlap, if configured
+func (c *TextSplitter) repairBoundaries(text string, chunks []Chunk, chunkSize int) []Chunk {
+	if len(chunks) < 2 {
+		return chunks
+	}
+	if i := c.hardBoundaryAfter(text, chunks); i > 0 {
+		// no content moves across a hard boundary
+		return append(c.repairBoundaries(text, chunks[:i:i], chunkSize), c.repairBoundaries(text, chunks[i:], chunkSize)...)
+	}
+	if c.opts.RepairWindow > 0 || c.opts.SnapTolerance > 0 {
+		ends := sentenceEnds(text, c.splitRules().terminators())
+		if c.opts.RepairWindow > 0 {
+			chunks = c.repairDangling(text, ends, chunks, chunkSize)
+		}
+		if c.opts.SnapTolerance > 0 {
+			chunks = c.snapToSentences(text, ends, chunks, chunkSize)
+		}
+	}
+	if c.opts.PullHeadings {
+		chunks = c.pullHeadings(text, chunks, chunkSize)
+	}
+	if c.opts.MinOverlap > 0 {
+		// the guaranteed overlap comes on top of the merged chunks, see WithMinOverlap
+		chunks = c.ensureOverlap(text, chunks, chunkSize+c.opts.MinOverlap)
+	}
+	return chunks
+}
+
+// repairDangling shifts boundaries after unfinished sentences, brackets or quotes and before
+// chunks starting mid-sentence, see WithBoundaryRepair. The end of a chunk and the start of the
+// next one move together if the chunks are adjacent, and separately if they overlap, as long
+// as they still meet.
+func (c *TextSplitter) repairDangling(text string, ends []int, chunks []Chunk, chunkSize int) []Chunk {
+	window := c.opts.RepairWindow
+	terminators := c.splitRules().terminators()
+
+	for i := 0; i+1 < len(chunks); i++ {
+		a, b := chunks[i], chunks[i+1]
+		if a.End < b.Start && strings.TrimSpace(text[a.End:b.Start]) != "" {
+			// only chunks that meet in the text can trade content
+			continue
+		}
+		dangling := !endsSentence(a.Text, terminators) || continuesQuote(a.Text, text[a.End:]) || !balancedBrackets(a.Text) || !balancedQuotes(a.Text)
+		if a.End <= b.Start {
+			if dangling {
+				c.moveBoundary(text, ends, chunks, i, chunkSize, window)
+			}
+			continue
+		}
+
+		if dangling {
+			for _, p := range nearestEnds(ends, a.End, b.Start, b.End, window) {
+				if count := c.countTokenFunc(text[a.Start:p]); count <= chunkSize {
+					setSpan(&chunks[i], text, a.Start, p, count)
+					break
+				}
+			}
+		}
+		if !startsSentence(text, ends, b.Start) {
+			for _, e := range nearestEnds(ends, b.Start, a.Start, chunks[i].End+1, window) {
+				start := skipSpace(text, e)
+				if start == b.Start || start > chunks[i].End || start >= b.End {
+					continue
+				}
+				if count := c.countTokenFunc(text[start:b.End]); count <= chunkSize {
+					setSpan(&chunks[i+1], text, start, b.End, count)
+					break
+				}
+			}
+		}
+	}
+	return chunks
+}
+
+// moveBoundary moves the boundary between the adjacent chunks i and i+1 to the nearest sentence
+// end within window bytes at which both stay within chunkSize
+func (c *TextSplitter) moveBoundary(text string, ends []int, chunks []Chunk, i int, chunkSize int, window int) {
+	a, b := chunks[i], chunks[i+1]
+	for _, p := range nearestEnds(ends, a.End, a.Start, b.End, window) {
+		start := skipSpace(text, p)
+		if start >= b.End {
+			continue
+		}
+		counts := c.countTokensBatch([]string{text[a.Start:p], text[start:b.End]})
+		if counts[0] > chunkSize || counts[1] > chunkSize {
+			continue
+		}
+		setSpan(&chunks[i], text, a.Start, p, counts[0])
+		setSpan(&chunks[i+1], text, start, b.End, counts[1])
+		return
+	}
+}
+
+// setSpan moves chunk to [start, end) of text, which counts tokens. The boundary it was moved
+// to is a sentence boundary.
+func setSpan(chunk *Chunk, text string, start, end int, tokens int) {
+	chunk.Start, chunk.End, chunk.Text = start, end, text[start:end]
+	chunk.Tokens = tokens
+	chunk.Metadata.Level = LevelSentence
+}
+
+// startsSentence reports whether pos is the start of text or of a sentence after one of ends
+func startsSentence(text string, ends []int, pos int) bool {
+	if pos == skipSpace(text, 0) {
+		return true
+	}
+	i := sort.SearchInts(ends, pos+1)
+	return i > 0 && skipSpace(text, ends[i-1]) == pos
+}
+
+// snapToSentences moves chunk ends to nearby sentence ends, see WithSentenceSnap
+func (c *TextSplitter) snapToSentences(text string, ends []int, chunks []Chunk, chunkSize int) []Chunk {
+	terminators := c.splitRules().terminators()
+	for i := 0; i+1 < len(chunks); i++ {
+		a, b := chunks[i], chunks[i+1]
+		if endsSentence(a.Text, terminators) && !continuesQuote(a.Text, text[a.End:]) {
+			continue
+		}
+		adjacent := a.End <= b.Start
+		if adjacent && strings.TrimSpace(text[a.End:b.Start]) != "" {
+			continue
+		}
+
+		lo := a.Start
+		if !adjacent {
+			// the chunks must still meet
+			lo = b.Start
+		}
+		for _, p := range nearestEnds(ends, a.End, lo, b.End, len(text)) {
+			start := b.Start
+			if adjacent {
+				start = skipSpace(text, p)
+			}
+			if start >= b.End {
+				continue
+			}
+			var moved string
+			if p < a.End {
+				moved = text[p:a.End]
+			} else {
+				moved = text[a.End:p]
+			}
+			counts := c.countTokensBatch([]string{moved, text[a.Start:p], text[start:b.End]})
+			if counts[0] > c.opts.SnapTolerance || counts[1] > chunkSize || counts[2] > chunkSize {
+				continue
+			}
+			setSpan(&chunks[i], text, a.Start, p, counts[1])
+			setSpan(&chunks[i+1], text, start, b.End, counts[2])
+			break
+		}
+	}
+	return chunks
+}
+
+// nearestEnds returns the sentence ends strictly inside (lo, hi) and within window of pos,
+// ordered by their distance to pos
+func nearestEnds(ends []int, pos, lo, hi, window int) []int {
+	candidates := make([]int, 0)
+	for _, end := range ends {
+		if end <= lo || end >= hi || end == pos {
+			continue
+		}
+		if end < pos-window || end > pos+window {
+			continue
+		}
+		candidates = append(candidates, end)
+	}
+	distance := func(p int) int {
+		if p < pos {
+			return pos - p
+		}
+		return p - pos
+	}
+	// insertion sort, there are only a few candidates
+	for i := 1; i < len(candidates); i++ {
+		for j := i; j > 0 && distance(candidates[j]) < distance(candidates[j-1]); j-- {
+			candidates[j], candidates[j-1] = candidates[j-1], candidates[j]
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,65 @@
+package semchunk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoundaryRepair(t *testing.T) {
+	text := "Alpha beta (gamma\ndelta) epsilon. Zeta eta."
+
+	plain := newWordSplitter(t, 6, 0)
+	assert.Equal(t, []string{"Alpha beta (gamma", "delta) epsilon. Zeta eta."}, plain.Split(text))
+
+	repaired := newWordSplitter(t, 6, 0, WithBoundaryRepair(20))
+	chunks := repaired.SplitChunks(text)
+	assert.Equal(t, []string{"Alpha beta (gamma\ndelta) epsilon.", "Zeta eta."}, chunkTexts(chunks))
+	for _, chunk := range chunks {
+		assert.Equal(t, text[chunk.Start:chunk.End], chunk.Text)
+	}
+
+	// the window is too small to reach a clean point
+	narrow := newWordSplitter(t, 6, 0, WithBoundaryRepair(5))
+	assert.Equal(t, plain.Split(text), narrow.Split(text))
+}
+
+func TestBoundaryRepairOverlap(t *testing.T) {
+	text := "Alpha beta. Gamma\ndelta epsilon\nzeta. Eta\ntheta iota."
+	plain := newWordSplitter(t, 5, 2)
+	assert.Equal(t, []string{"Alpha beta. Gamma\ndelta epsilon", "delta epsilon\nzeta. Eta", "zeta. Eta\ntheta iota."}, plain.Split(text))
+
+	// the second chunk ends and starts mid-sentence, both are repaired
+	chunks := newWordSplitter(t, 5, 2, WithBoundaryRepair(30)).SplitChunks(text)
+	assert.Equal(t, []string{"Alpha beta. Gamma\ndelta epsilon", "Gamma\ndelta epsilon\nzeta.", "zeta. Eta\ntheta iota."}, chunkTexts(chunks))
+	for _, chunk := range chunks {
+		assert.Equal(t, text[chunk.Start:chunk.End], chunk.Text)
+		assert.Equal(t, len(strings.Fields(chunk.Text)), chunk.Tokens)
+	}
+	assert.Equal(t, LevelSentence, chunks[1].Metadata.Level)
+}
+
+func TestSentenceEnds(t *testing.T) {
+	text := `He said "stop." Then he left... 然后。OK`
+	assert.Equal(t, []int{15, 31, 41}, sentenceEnds(text, sentenceTerminators))
+}
+
+func TestWithSentenceSnap(t *testing.T) {
+	text := "Alpha beta gamma delta\nepsilon. Zeta eta theta."
+	assert.Equal(t, []string{"Alpha beta gamma delta", "epsilon. Zeta eta theta."}, newWordSplitter(t, 5, 0).Split(text))
+
+	chunks := newWordSplitter(t, 5, 0, WithSentenceSnap(1)).SplitChunks(text)
+	assert.Equal(t, []string{"Alpha beta gamma delta\nepsilon.", "Zeta eta theta."}, chunkTexts(chunks))
+	for _, chunk := range chunks {
+		assert.Equal(t, text[chunk.Start:chunk.End], chunk.Text)
+	}
+
+	// snapping backward
+	text = "Alpha beta gamma. Delta\nepsilon zeta eta."
+	assert.Equal(t, []string{"Alpha beta gamma.", "Delta\nepsilon zeta eta."}, newWordSplitter(t, 4, 0, WithSentenceSnap(1)).Split(text))
+
+	// the sentence end is further away than the tolerance
+	text = "Alpha beta\ngamma delta epsilon. Zeta."
+	assert.Equal(t, newWordSplitter(t, 4, 0).Split(text), newWordSplitter(t, 4, 0, WithSentenceSnap(2)).Split(text))
+}
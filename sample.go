@@ -0,0 +1,118 @@
+package semchunk
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// SampleStrategy is the way Sample draws chunks
+type SampleStrategy int
+
+const (
+	// SampleUniform draws every chunk with the same probability
+	SampleUniform SampleStrategy = iota
+	// SampleStratified spreads the sample evenly over the sections of the documents, taking
+	// chunks from every document and section in turn, each in random order
+	SampleStratified
+	// SampleLongest takes the chunks with the most tokens
+	SampleLongest
+)
+
+// SampleOption configures Sample
+type SampleOption struct {
+	// Seed seeds the random source, so the same seed draws the same sample
+	Seed int64
+}
+
+// WithSampleSeed seeds the random source of Sample, so the same seed draws the same sample
+func WithSampleSeed(seed int64) func(*SampleOption) {
+	return func(opts *SampleOption) {
+		if opts == nil {
+			opts = &SampleOption{}
+		}
+		opts.Seed = seed
+	}
+}
+
+// Sample draws n chunks from chunks, for example to build an evaluation set from a chunked
+// corpus. The draw only depends on the chunks and the seed, 0 unless set with WithSampleSeed.
+// Chunks are returned in their input order, except for SampleLongest, which returns the
+// longest chunk first. If n is at least the number of chunks, all chunks are returned.
+func Sample(chunks []Chunk, n int, strategy SampleStrategy, opts ...func(*SampleOption)) []Chunk {
+	sampleOpts := &SampleOption{}
+	for _, opt := range opts {
+		opt(sampleOpts)
+	}
+	if n <= 0 {
+		return []Chunk{}
+	}
+	if n > len(chunks) {
+		n = len(chunks)
+	}
+	rng := rand.New(rand.NewSource(sampleOpts.Seed))
+
+	var picked []int
+	switch strategy {
+	case SampleLongest:
+		picked = make([]int, len(chunks))
+		for i := range picked {
+			picked[i] = i
+		}
+		sort.SliceStable(picked, func(a, b int) bool {
+			return chunkLength(chunks[picked[a]]) > chunkLength(chunks[picked[b]])
+		})
+		picked = picked[:n]
+	case SampleStratified:
+		picked = sampleStrata(chunks, n, rng)
+		sort.Ints(picked)
+	default:
+		picked = rng.Perm(len(chunks))[:n]
+		sort.Ints(picked)
+	}
+
+	sample := make([]Chunk, len(picked))
+	for i, j := range picked {
+		sample[i] = chunks[j]
+	}
+	return sample
+}
+
+// chunkLength is the token count of chunk, or its length in bytes if it was not counted
+func chunkLength(chunk Chunk) int {
+	if chunk.Tokens > 0 {
+		return chunk.Tokens
+	}
+	return len(chunk.Text)
+}
+
+// sampleStrata returns the indexes of n chunks taken round-robin from the strata of chunks,
+// a stratum being a section of a document, with strata and their chunks visited in random order
+func sampleStrata(chunks []Chunk, n int, rng *rand.Rand) []int {
+	strata := make([][]int, 0)
+	byKey := make(map[string]int)
+	for i, chunk := range chunks {
+		key := chunk.Metadata.DocumentID + "\x00" + strings.Join(chunk.Metadata.HeadingPath, "\x00")
+		s, ok := byKey[key]
+		if !ok {
+			s = len(strata)
+			byKey[key] = s
+			strata = append(strata, nil)
+		}
+		strata[s] = append(strata[s], i)
+	}
+	rng.Shuffle(len(strata), func(a, b int) { strata[a], strata[b] = strata[b], strata[a] })
+	for _, stratum := range strata {
+		rng.Shuffle(len(stratum), func(a, b int) { stratum[a], stratum[b] = stratum[b], stratum[a] })
+	}
+
+	picked := make([]int, 0, n)
+	for round := 0; len(picked) < n; round++ {
+		for _, stratum := range strata {
+			if round < len(stratum) && len(picked) < n {
+				picked = append(picked, stratum[round])
+			}
+		}
+	}
+	return picked
+}
@@ -0,0 +1,40 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSample(t *testing.T) {
+	chunks := make([]Chunk, 0)
+	for i, doc := range []string{"a", "a", "a", "a", "a", "a", "b", "c"} {
+		chunks = append(chunks, Chunk{Index: i, Text: "chunk", Tokens: i%4 + 1, Metadata: Metadata{DocumentID: doc}})
+	}
+	indexes := func(sample []Chunk) []int {
+		result := make([]int, len(sample))
+		for i, chunk := range sample {
+			result[i] = chunk.Index
+		}
+		return result
+	}
+
+	uniform := Sample(chunks, 3, SampleUniform, WithSampleSeed(7))
+	assert.Len(t, uniform, 3)
+	assert.IsIncreasing(t, indexes(uniform))
+	assert.Equal(t, uniform, Sample(chunks, 3, SampleUniform, WithSampleSeed(7)))
+
+	// every document is represented before any document is drawn twice
+	stratified := Sample(chunks, 3, SampleStratified, WithSampleSeed(7))
+	docs := make(map[string]bool)
+	for _, chunk := range stratified {
+		docs[chunk.Metadata.DocumentID] = true
+	}
+	assert.Len(t, docs, 3)
+	assert.IsIncreasing(t, indexes(stratified))
+
+	assert.Equal(t, []int{3, 7, 2}, indexes(Sample(chunks, 3, SampleLongest)))
+
+	assert.Len(t, Sample(chunks, 20, SampleUniform), len(chunks))
+	assert.Empty(t, Sample(chunks, 0, SampleStratified))
+}
@@ -0,0 +1,101 @@
+package semchunk
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LevelScript is the level of splits made where the text changes script, see
+// WithScriptSegmentation
+const LevelScript SplitLevel = "script"
+
+// minScriptRun is the weight a run of one script needs to be split off on its own, so names
+// and terms in another script do not break up a sentence. Letters weigh 1, or 2 in unspaced
+// scripts, where a letter is often a word.
+const minScriptRun = 16
+
+// WithScriptSegmentation splits mixed-script text into runs of consistent script, such as an
+// English paragraph inside a Chinese document, before splitting at punctuation. Every run is
+// then split with the separators of its own script, instead of full-width punctuation taking
+// precedence over the whole text. Scripts written without spaces (Han, Kana, Hangul) are told
+// apart from all others; runs of fewer than 16 letters, or 8 in unspaced scripts, stay with
+// their neighbours.
+func WithScriptSegmentation(enabled bool) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.ScriptSegmentation = enabled
+	}
+}
+
+// isUnspacedScript reports whether r belongs to a script written without spaces between words
+func isUnspacedScript(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// scriptRun is a stretch of text whose letters are of one kind of script
+type scriptRun struct {
+	unspaced bool
+	// start is the offset of the first letter, end the offset after the last one
+	start, end int
+	weight     int
+}
+
+// splitScripts splits text where it changes between spaced and unspaced scripts
+func (r splitRules) splitScripts(text string) (textSplit, bool) {
+	terminators := r.terminators()
+	var runs []scriptRun
+	for i, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		unspaced := isUnspacedScript(r)
+		end := i + utf8.RuneLen(r)
+		weight := 1
+		if unspaced {
+			weight = 2
+		}
+		if n := len(runs); n > 0 && runs[n-1].unspaced == unspaced {
+			runs[n-1].end = end
+			runs[n-1].weight += weight
+			continue
+		}
+		runs = append(runs, scriptRun{unspaced: unspaced, start: i, end: end, weight: weight})
+	}
+
+	// fold short runs into the preceding run, or the following one at the start
+	merged := make([]scriptRun, 0, len(runs))
+	for i, run := range runs {
+		switch {
+		case len(merged) > 0 && (run.weight < minScriptRun || merged[len(merged)-1].unspaced == run.unspaced):
+			last := &merged[len(merged)-1]
+			last.end = run.end
+			last.weight += run.weight
+		case len(merged) == 0 && run.weight < minScriptRun && i+1 < len(runs):
+			runs[i+1].start = run.start
+			runs[i+1].weight += run.weight
+		default:
+			merged = append(merged, run)
+		}
+	}
+	if len(merged) < 2 {
+		return textSplit{}, false
+	}
+
+	offsets := make([]int, 0, len(merged)-1)
+	for i := 1; i < len(merged); i++ {
+		// keep opening punctuation attached to the first letter of the run
+		offset := merged[i].start
+		for offset > merged[i-1].end {
+			r, size := utf8.DecodeLastRuneInString(text[:offset])
+			if unicode.IsSpace(r) || endsSentence(string(r), terminators) || strings.ContainsRune(",;:，；：、", r) {
+				break
+			}
+			offset -= size
+		}
+		offsets = append(offsets, offset)
+	}
+	return splitAtOffsets(text, offsets, LevelScript)
+}
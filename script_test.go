@@ -0,0 +1,58 @@
+package semchunk
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitScripts(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "english paragraph in chinese text",
+			text: "这是中文的第一句话，内容比较长。 The quick brown fox jumps over the lazy dog. Then it sleeps. 然后我们继续写中文，直到结束。",
+			want: []string{"这是中文的第一句话，内容比较长。", "The quick brown fox jumps over the lazy dog. Then it sleeps.", "然后我们继续写中文，直到结束。"},
+		},
+		{
+			name: "short terms stay in the sentence",
+			text: "我们使用 Go 语言和 Kubernetes 来部署服务。",
+		},
+		{
+			name: "opening punctuation stays with the run",
+			text: "这是一段比较长的中文文本，用于测试分段功能的效果。(Quoted english text that is long enough to count.)",
+			want: []string{"这是一段比较长的中文文本，用于测试分段功能的效果。", "(Quoted english text that is long enough to count.)"},
+		},
+		{
+			name: "single script",
+			text: "Only english text here, no other script at all.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, ok := splitRules{}.splitScripts(tt.text)
+			assert.Equal(t, tt.want != nil, ok)
+			assert.Equal(t, tt.want, ts.splits)
+			for i, split := range ts.splits {
+				assert.Equal(t, split, tt.text[ts.starts[i]:ts.starts[i]+len(split)])
+			}
+		})
+	}
+}
+
+func TestWithScriptSegmentation(t *testing.T) {
+	text := "这是第一句中文。Short english sentence one. Another english sentence two. 中文继续，然后结束。"
+
+	// without segmentation the full-width comma splits the last sentence first
+	splitter, err := NewTextSplitter(30, 0, utf8.RuneCountInString)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"这是第一句中文", "Short english sentence one.", "Another english sentence two.", "中文继续", "然后结束"}, splitter.Split(text))
+
+	splitter, err = NewTextSplitter(30, 0, utf8.RuneCountInString, WithScriptSegmentation(true))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"这是第一句中文", "Short english sentence one.", "Another english sentence two.", "中文继续，然后结束。"}, splitter.Split(text))
+}
@@ -1,22 +1,165 @@
 package semchunk
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // TextSplitter handles the semantic chunking of text
 type TextSplitter struct {
 	chunkSize      int
 	countTokenFunc func(text string) int
+	batchCounter   BatchTokenCounter
+	encoder        TokenEncoder
 	overlap        int
 	opts           *TextSplitterOption
+	// budget is set on the copy of the splitter that splits one text, see withBudget
+	budget *splitBudget
 }
 
 type TextSplitterOption struct {
 	PreserveURLs     bool
 	PreservePatterns []*regexp.Regexp
+	// PatternResolution decides between overlapping preserve pattern matches, see WithPatternResolution
+	PatternResolution PatternResolution
+
+	// EstimateTokens enables two-pass proportional estimation, see WithTokenEstimation
+	EstimateTokens      bool
+	EstimationTolerance float64
+
+	// RepairWindow enables the boundary repair pass, see WithBoundaryRepair
+	RepairWindow int
+	// TOC decides what happens to table of contents chunks, see WithTOCDetection
+	TOC TOCMode
+	// PullHeadings moves trailing headings into the next chunk, see WithHeadingPullForward
+	PullHeadings bool
+	// MinOverlap is the number of tokens consecutive chunks share at least, see WithMinOverlap
+	MinOverlap int
+	// SnapTolerance enables snapping chunk ends to sentence ends, see WithSentenceSnap
+	SnapTolerance int
+
+	// Annotator is run on every chunk by SplitChunksContext, see WithChunkAnnotator
+	Annotator            ChunkAnnotator
+	AnnotatorConcurrency int
+	// SplitConcurrency is the number of documents SplitAll splits at the same time
+	SplitConcurrency int
+
+	// LangChain replaces semantic splitting by LangChain's algorithm, see WithLangChainCompat
+	LangChain *LangChainOptions
+	// SemchunkCompat replaces semantic splitting by the algorithm of the Python semchunk
+	// library, see WithSemchunkCompat
+	SemchunkCompat bool
+
+	// Dividers splits at divider lines and leaves them out, see WithDividers
+	Dividers bool
+
+	// ScriptSegmentation splits mixed-script text into runs first, see WithScriptSegmentation
+	ScriptSegmentation bool
+
+	// SizeUnit is the unit chunk size and overlap are measured in, see WithSizeUnit
+	SizeUnit SizeUnit
+	// SafetyMargin is the fraction by which the chunk size is reduced, see WithSafetyMargin
+	SafetyMargin float64
+	// CheckCounter runs the token counter on probe texts on construction, see WithCounterCheck
+	CheckCounter bool
+
+	// ContextGenerator is run on every chunk by SplitDocument, see WithContextGenerator
+	ContextGenerator    ContextGenerator
+	PrependContext      bool
+	ContextExcerptBytes int
+	ContextBudget       int
+
+	// Whitespace replaces the characters split at between words, see WithWhitespace
+	Whitespace      string
+	whitespaceClass string
+	whitespaceRegex *regexp.Regexp
+	isSpace         func(rune) bool
+
+	// InvisibleChars sets how zero-width and formatting characters are handled, see WithInvisibleChars
+	InvisibleChars InvisibleMode
+
+	// Merger groups splits into chunks, see WithMerger
+	Merger Merger
+
+	// StopDescent keeps pieces whole instead of splitting them further, see WithStopDescent
+	StopDescent func(piece string, tokens int) bool
+
+	// SectionBudget sets the chunk size of each section of a structured document, see WithSectionBudget
+	SectionBudget func(section Section) int
+
+	// RecordSeparator divides the text into records split separately, see WithRecords
+	RecordSeparator     *regexp.Regexp
+	KeepRecordSeparator bool
+	// HardBoundaries are separators no chunk spans, see WithHardBoundaries
+	HardBoundaries []string
+
+	// NewlineTiers splits at paragraph and line breaks separately, see WithNewlineTiers
+	NewlineTiers *NewlineTiers
+
+	// PageMarkers are split at before anything else, see WithPageMarkers
+	PageMarkers []string
+
+	// BoundaryDetector finds additional boundaries, see WithBoundaryDetector
+	BoundaryDetector BoundaryDetector
+	BoundaryMinScore float64
+
+	// OversizedMatches and OversizedThreshold decide what happens to preserved matches larger
+	// than the chunk size, see WithOversizedMatches
+	OversizedMatches   OversizedPolicy
+	OversizedThreshold int
+	// WholeMatch and WholeMatchWrap decide what happens to a text that is one preserved match
+	// larger than the chunk size, see WithWholeMatch
+	WholeMatch     WholeMatchPolicy
+	WholeMatchWrap int
+	// Blobs and BlobMinLength set how base64 and hex blobs are handled, see WithBlobs
+	Blobs         BlobMode
+	BlobMinLength int
+	// SentenceTerminators and ClauseSeparators replace the default punctuation split at when
+	// not nil, see WithSentenceTerminators
+	SentenceTerminators []string
+	ClauseSeparators    []string
+	// NoClauseSplitting disables splitting at clause separators, see WithClauseSplitting
+	NoClauseSplitting bool
+	// ChunkSuffix is appended to every chunk, see WithChunkSuffix
+	ChunkSuffix string
+	// NoParagraphBreakOverlap suppresses overlap at paragraph breaks, see
+	// WithParagraphBreakOverlap
+	NoParagraphBreakOverlap bool
+	// NumericTables and RepeatTableHeader keep the rows of plain text tables whole, see
+	// WithNumericTables
+	NumericTables     bool
+	RepeatTableHeader bool
+	// AlgorithmVersion freezes the splitting algorithm at a version, see WithAlgorithmVersion
+	AlgorithmVersion string
+
+	// RelativeOverlap replaces the configured overlap, see WithRelativeOverlap
+	RelativeOverlap float64
+
+	// MinContentRunes drops chunks with fewer letters and digits, see WithMinContentRunes
+	MinContentRunes int
+	// MaxChunkBytes caps chunks in bytes on top of the chunk size, see WithMaxChunkBytes
+	MaxChunkBytes int
+
+	// MaxSplitOps and Timeout abort pathological inputs, see WithMaxSplitOps and WithTimeout
+	MaxSplitOps int
+	Timeout     time.Duration
+
+	// Redactor rewrites the text of every chunk, see WithRedactor
+	Redactor func(text string) string
+
+	// DetectPII tags chunks with the PIICategories found in them, see WithPIIDetection
+	DetectPII     bool
+	PIICategories []PIICategory
+
+	// Denylist marks or drops the chunks matching it, see WithDenylist
+	Denylist     []*regexp.Regexp
+	DenylistMode DenylistMode
 }
 
 func WithPreserveURLs(preserveURLs bool) func(*TextSplitterOption) {
@@ -41,24 +184,12 @@ func WithPreservePatterns(preservePatterns ...string) func(*TextSplitterOption)
 	}
 }
 
-// NewTextSplitter creates a new TextSplitter instance
+// NewTextSplitter creates a new TextSplitter instance. It validates the chunk size, the overlap
+// and all options, and returns every violation found, joined into one error.
 func NewTextSplitter[K int | float32](chunkSize int, overlap K, countTokenFunc func(text string) int, opts ...func(*TextSplitterOption)) (*TextSplitter, error) {
-	var overlapInt int
-	if overlapFloat, ok := any(overlap).(float32); ok {
-		if overlapFloat < 0 || overlapFloat > 1 {
-			return nil, fmt.Errorf("overlap must be between 0 and 1")
-		}
-		overlapInt = int(overlapFloat * float32(chunkSize))
-	} else if overlapInt, ok := any(overlap).(int); ok {
-		if overlapInt < 0 || overlapInt > chunkSize {
-			return nil, fmt.Errorf("overlap must be between 0 and chunkSize")
-		}
-	}
-
 	ts := &TextSplitter{
 		chunkSize:      chunkSize,
 		countTokenFunc: countTokenFunc,
-		overlap:        overlapInt,
 		opts:           &TextSplitterOption{},
 	}
 
@@ -66,11 +197,122 @@ func NewTextSplitter[K int | float32](chunkSize int, overlap K, countTokenFunc f
 		opt(ts.opts)
 	}
 
+	errs := ts.opts.validate()
+	if chunkSize <= 0 {
+		errs = append(errs, fmt.Errorf("chunk size must be positive, got %d", chunkSize))
+	}
+
+	if count := ts.opts.SizeUnit.counter(); count != nil {
+		ts.countTokenFunc = count
+	} else if countTokenFunc == nil {
+		errs = append(errs, fmt.Errorf("a token counter is required unless a size unit is set"))
+	}
+
+	if margin := ts.opts.SafetyMargin; margin != 0 {
+		if margin < 0 || margin >= 1 {
+			errs = append(errs, fmt.Errorf("safety margin must be between 0 and 1"))
+		} else {
+			ts.chunkSize = int(float64(chunkSize) * (1 - margin))
+			if ts.chunkSize < 1 {
+				ts.chunkSize = 1
+			}
+		}
+	}
+
+	if n := ts.opts.MinOverlap; n > 0 {
+		if n >= ts.chunkSize {
+			errs = append(errs, fmt.Errorf("minimum overlap must be less than chunkSize"))
+		} else {
+			ts.chunkSize -= n
+		}
+	}
+
+	if ts.opts.ChunkSuffix != "" && ts.countTokenFunc != nil {
+		if n := ts.countTokenFunc(ts.opts.ChunkSuffix); n >= ts.chunkSize {
+			errs = append(errs, fmt.Errorf("chunk suffix of %d tokens must be smaller than the chunk size", n))
+		} else {
+			ts.chunkSize -= n
+		}
+	}
+
+	if overlapFloat, ok := any(overlap).(float32); ok {
+		if overlapFloat < 0 || overlapFloat >= 1 {
+			errs = append(errs, fmt.Errorf("overlap must be between 0 and 1, got %g", overlapFloat))
+		}
+		ts.overlap = int(overlapFloat * float32(ts.chunkSize))
+	} else if overlapTokens, ok := any(overlap).(int); ok {
+		if overlapTokens < 0 || overlapTokens > 0 && overlapTokens >= chunkSize {
+			errs = append(errs, fmt.Errorf("overlap must be between 0 and chunkSize, got %d", overlapTokens))
+		}
+		ts.overlap = overlapTokens
+		if ts.overlap > ts.chunkSize {
+			ts.overlap = ts.chunkSize
+		}
+		if ts.opts.AlgorithmVersion == AlgorithmV1 {
+			// the first release dropped integer overlaps
+			ts.overlap = 0
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid splitter configuration: %w", err)
+	}
+
+	if ts.opts.CheckCounter {
+		if err := checkCounter(ts.countTokenFunc); err != nil {
+			return nil, err
+		}
+	}
+
 	return ts, nil
 }
 
+// WithStopDescent keeps a piece that does not fit the chunk size whole when stop returns true
+// for it, instead of splitting it further, for example to never cut tables or code blocks.
+// stop is called with the piece and its token count. Pieces kept whole become chunks of their
+// own, marked Oversized if they exceed the chunk size.
+func WithStopDescent(stop func(piece string, tokens int) bool) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.StopDescent = stop
+	}
+}
+
+// WithSafetyMargin reduces the chunk size by the fraction margin, e.g. 0.05 for 5%, to absorb
+// differences between the tokenizer used for counting and the tokenizer of the model the
+// chunks are for. An overlap given as a ratio applies to the reduced size.
+func WithSafetyMargin(margin float64) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.SafetyMargin = margin
+	}
+}
+
+// WithRelativeOverlap makes the overlap between consecutive chunks a fraction of the size of
+// the chunk being overlapped instead of a fraction of chunkSize, so short chunks are not almost
+// entirely repeated in their neighbour. It replaces the overlap passed to NewTextSplitter.
+func WithRelativeOverlap(ratio float64) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.RelativeOverlap = ratio
+	}
+}
+
+// overlapFor returns the overlap in tokens after a chunk of size tokens
+func (c *TextSplitter) overlapFor(size int) int {
+	if c.opts != nil && c.opts.RelativeOverlap > 0 {
+		return int(c.opts.RelativeOverlap * float64(size))
+	}
+	return c.overlap
+}
+
 var urlRegex = regexp.MustCompile(`(https?|ftp|file|www)(:|.)(//)?[-A-Za-z0-9+&@#/%?=~_|!:,.;]+[-A-Za-z0-9+&@#/%=~_|]`)
-var whitespaceRegex = regexp.MustCompile(`\s+`)
 var fullWidthSentenceTerminators = []string{
 	"。", "？", "！",
 }
@@ -90,6 +332,21 @@ var clauseSeparators = []string{
 var nonWhitespaceSemanticSplitters = append(sentenceTerminators, clauseSeparators...)
 var fullWidthNonWhitespaceSemanticSpliters = append(fullWidthSentenceTerminators, fullWidthClauseSparators...)
 
+// precederTiers are the sets of punctuation whitespace is split after, tried in order. All
+// sentence terminators are split at in one pass, clause separators one at a time.
+var precederTiers = newPrecederTiers(sentenceTerminators, clauseSeparators)
+
+func newPrecederTiers(terminators []string, separators []string) [][]string {
+	tiers := make([][]string, 0, len(separators)+1)
+	if len(terminators) > 0 {
+		tiers = append(tiers, terminators)
+	}
+	for _, separator := range separators {
+		tiers = append(tiers, []string{separator})
+	}
+	return tiers
+}
+
 func longestSplitter(splitters []string) string {
 	if len(splitters) == 0 {
 		return ""
@@ -104,18 +361,115 @@ func longestSplitter(splitters []string) string {
 	return longest
 }
 
+// SplitLevel describes the kind of separator a piece of text was split at
+type SplitLevel string
+
+const (
+	LevelParagraph  SplitLevel = "paragraph"
+	LevelLine       SplitLevel = "line"
+	LevelPattern    SplitLevel = "pattern"
+	LevelSentence   SplitLevel = "sentence"
+	LevelClause     SplitLevel = "clause"
+	LevelWhitespace SplitLevel = "whitespace"
+	LevelChar       SplitLevel = "char"
+	LevelToken      SplitLevel = "token"
+)
+
+// textSplit is the result of splitting a text at one separator level
+type textSplit struct {
+	splitter     string
+	isWhitespace bool
+	level        SplitLevel
+	splits       []string
+	// starts are the offsets of the splits in the text, if they are not separated by splitter
+	starts []int
+	// separate keeps the splits from being merged with each other
+	separate bool
+	// atomic keeps splits that do not fit the chunk size whole instead of splitting them further
+	atomic bool
+	// scores are the scores of the boundaries before the splits, if a BoundaryDetector found them
+	scores []float64
+	// detached are the splits before list item markers were attached to their items
+	detached []string
+}
+
+// addTrimmed appends text[start:end], trimmed of surrounding whitespace, to the splits unless
+// nothing is left of it
+func (ts *textSplit) addTrimmed(text string, start, end int) {
+	piece := strings.TrimLeftFunc(text[start:end], unicode.IsSpace)
+	start = end - len(piece)
+	piece = strings.TrimRightFunc(piece, unicode.IsSpace)
+	if piece != "" {
+		ts.splits = append(ts.splits, piece)
+		ts.starts = append(ts.starts, start)
+	}
+}
+
+// addAround appends the spans of text, which are sorted and do not overlap, and the text
+// between them to the splits like addTrimmed
+func (ts *textSplit) addAround(text string, spans [][2]int) {
+	last := 0
+	for _, span := range spans {
+		ts.addTrimmed(text, last, span[0])
+		ts.addTrimmed(text, span[0], span[1])
+		last = span[1]
+	}
+	ts.addTrimmed(text, last, len(text))
+}
+
 // innerSplit splits text using the most semantically meaningful splitter possible
 func innerSplit(text string, preservePatterns []*regexp.Regexp) (string, bool, []string) {
+	ts := semanticSplit(text, splitRules{preservePatterns: preservePatterns})
+	return ts.splitter, ts.isWhitespace, ts.splits
+}
+
+// semanticSplit splits text using the most semantically meaningful splitter possible
+// and reports the level of the splitter it used
+func semanticSplit(text string, rules splitRules) textSplit {
 	splitterIsWhitespace := true
 
+	// Try splitting at page markers
+	if ts, ok := splitPages(text, rules.pageMarkers); ok {
+		return ts
+	}
+
+	// Try splitting around blobs
+	if rules.blobMinLength > 0 {
+		if ts, ok := splitBlobs(text, rules.blobMinLength); ok {
+			return ts
+		}
+	}
+
+	// Try splitting at divider lines
+	if rules.dividers {
+		if ts, ok := splitDividers(text); ok {
+			return ts
+		}
+	}
+
+	// Try splitting around and inside numeric tables
+	if rules.numericTables {
+		if ts, ok := splitTables(text); ok {
+			return ts
+		}
+	}
+
 	// Try splitting at newlines
-	if strings.Contains(text, "\n") || strings.Contains(text, "\r") {
+	if rules.newlineTiers != nil {
+		if ts, ok := splitNewlineTiers(text, rules.newlineTiers); ok {
+			return ts
+		}
+	} else if strings.Contains(text, "\n") || strings.Contains(text, "\r") {
 		re := regexp.MustCompile(`[\r\n]+`)
 		matches := re.FindAllString(text, -1)
 		if len(matches) > 0 {
 			// Find the longest consecutive newlines
 			splitter := longestSplitter(matches)
-			return splitter, splitterIsWhitespace, strings.Split(text, splitter)
+			level := LevelLine
+			if lineBreaks(splitter) > 1 {
+				level = LevelParagraph
+			}
+			return textSplit{splitter: splitter, isWhitespace: splitterIsWhitespace, level: level, splits: strings.Split(text, splitter)}
 		}
 	}
 
@@ -125,81 +479,81 @@ func innerSplit(text string, preservePatterns []*regexp.Regexp) (string, bool, [
 		matches := re.FindAllString(text, -1)
 		if len(matches) > 0 {
 			splitter := longestSplitter(matches)
-			return splitter, splitterIsWhitespace, strings.Split(text, splitter)
+			return textSplit{splitter: splitter, isWhitespace: splitterIsWhitespace, level: LevelWhitespace, splits: strings.Split(text, splitter)}
 		}
 	}
 
 	// Check preserve patterns if they exist
 	// if any of the preservePatterns are found, split around them to keep the pattern intact
-	for _, pattern := range preservePatterns {
-		matches := pattern.FindAllStringIndex(text, -1)
-		if len(matches) > 0 {
-			// Split the text while keeping the pattern
-			parts := make([]string, 0)
-			lastIndex := 0
-			for _, match := range matches {
-				start, end := match[0], match[1]
-
-				// Add the text before the pattern
-				if start > lastIndex {
-					parts = append(parts, text[lastIndex:start])
-				}
-
-				// Add the pattern itself
-				parts = append(parts, text[start:end])
-
-				lastIndex = end
-			}
+	if matches := rules.preserveMatches(text); len(matches) > 0 {
+		return textSplit{splitter: "", isWhitespace: splitterIsWhitespace, level: LevelPattern, splits: splitAround(text, matches)}
+	}
 
-			// Add any remaining text
-			if lastIndex < len(text) {
-				parts = append(parts, text[lastIndex:])
-			}
+	// Try splitting at the boundaries found by the custom detector
+	if ts, ok := rules.detectBoundaries(text); ok {
+		return ts
+	}
 
-			return "", splitterIsWhitespace, parts
+	// Try splitting where the script changes
+	if rules.scriptSegmentation {
+		if ts, ok := rules.splitScripts(text); ok {
+			return ts
 		}
 	}
 
-	for _, splitter := range fullWidthNonWhitespaceSemanticSpliters {
+	for _, splitter := range rules.fullWidthSplitters() {
 		if strings.Contains(text, splitter) {
-			splitterIsWhitespace = false
-			return splitter, splitterIsWhitespace, strings.Split(text, splitter)
+			return rules.splitPunctuation(text, splitter)
 		}
 	}
 
 	// Try splitting at whitespace
-	if ContainsSpace(text) {
-		matches := whitespaceRegex.FindAllString(text, -1)
+	if rules.containsSpace(text) {
+		matches := rules.whitespaceRegex().FindAllString(text, -1)
 		if len(matches) > 0 {
 			splitter := longestSplitter(matches)
 
 			// If splitter is single character, try to find whitespace preceded by semantic splitters
-			if len(splitter) == 1 {
-				for _, preceder := range nonWhitespaceSemanticSplitters {
-					escapedPreceder := regexp.QuoteMeta(preceder)
-					re := regexp.MustCompile(escapedPreceder + `(\s)`)
+			if utf8.RuneCountInString(splitter) == 1 {
+				for _, preceders := range rules.precederTiers() {
+					re := rules.precededWhitespace(preceders...)
 					if matches := re.FindStringSubmatch(text); matches != nil {
 						splitter = matches[1]
-						parts := LookbehindSplit(text, preceder, splitter)
-						return splitter, splitterIsWhitespace, parts
+						parts := SplitAfterAny(text, preceders, splitter)
+						return textSplit{splitter: splitter, isWhitespace: splitterIsWhitespace, level: rules.punctuationLevel(preceders[0]), splits: rules.attachMarkers(parts, splitter), detached: parts}
 					}
 				}
 			}
 
-			return splitter, splitterIsWhitespace, strings.Split(text, splitter)
+			parts := strings.Split(text, splitter)
+			return textSplit{splitter: splitter, isWhitespace: splitterIsWhitespace, level: LevelWhitespace, splits: rules.attachMarkers(parts, splitter), detached: parts}
 		}
 	}
 
 	// Try non-whitespace semantic splitters
-	for _, splitter := range nonWhitespaceSemanticSplitters {
+	for _, splitter := range rules.nonWhitespaceSplitters() {
 		if strings.Contains(text, splitter) {
-			splitterIsWhitespace = false
-			return splitter, splitterIsWhitespace, strings.Split(text, splitter)
+			return rules.splitPunctuation(text, splitter)
 		}
 	}
 
 	// If no semantic splitter found, split into characters
-	return "", splitterIsWhitespace, strings.Split(text, "")
+	return textSplit{splitter: "", isWhitespace: splitterIsWhitespace, level: LevelChar, splits: strings.Split(text, "")}
+}
+
+// punctuationLevel reports whether a punctuation splitter ends a sentence or a clause
+func (r splitRules) punctuationLevel(splitter string) SplitLevel {
+	for _, terminator := range r.terminators() {
+		if splitter == terminator {
+			return LevelSentence
+		}
+	}
+	for _, terminator := range fullWidthSentenceTerminators {
+		if splitter == terminator {
+			return LevelSentence
+		}
+	}
+	return LevelClause
 }
 
 func estimateSize(size int, splitSize int, splitterSize int, appendSplitter bool) int {
@@ -213,91 +567,299 @@ func estimateSize(size int, splitSize int, splitterSize int, appendSplitter bool
 // mergeSplits merges splits until a chunk size is reached
 func (c *TextSplitter) mergeSplits(splits []string, splitSizes []int, splitter string, chunkSize int) []string {
 	result := make([]string, 0)
-	toMerge := make([]string, 0)
-	splitterSize := c.countTokenFunc(splitter)
+	for _, window := range c.mergeWindows(splitSizes, c.countTokenFunc(splitter), chunkSize, c.overlapFor) {
+		merged := strings.Join(splits[window[0]:window[1]], splitter)
+		if len(merged) > 0 {
+			result = append(result, merged)
+		}
+	}
+	return result
+}
 
-	windowStart := 0
-	size := 0
-	for i, split := range splits {
-		l := splitSizes[i]
+// mergeWindows groups consecutive splits into [start, end) windows of split indices whose
+// estimated size stays within chunkSize, overlapping consecutive windows by overlap
+func (c *TextSplitter) mergeWindows(splitSizes []int, splitterSize int, chunkSize int, overlap func(size int) int) [][2]int {
+	return c.merger().Merge(splitSizes, splitterSize, chunkSize, overlap)
+}
 
-		if estimateSize(size, l, splitterSize, len(toMerge) > 0) > chunkSize {
-			merged := strings.Join(toMerge, splitter)
-			if len(merged) > 0 {
-				result = append(result, merged)
-			}
+// merger returns the configured Merger, GreedyMerger by default
+func (c *TextSplitter) merger() Merger {
+	if c.opts != nil && c.opts.Merger != nil {
+		return c.opts.Merger
+	}
+	return GreedyMerger{}
+}
 
-			if c.overlap > 0 {
-				// keeps popping from the front of toMerge until the size is less than the overlap
-				for size > c.overlap ||
-					(estimateSize(size, l, splitterSize, len(toMerge) > 0) > chunkSize && size > 0) {
-					size -= splitSizes[windowStart]
-					if len(toMerge) > 1 {
-						size -= splitterSize
-					}
-					windowStart++
-					toMerge = toMerge[1:]
+// splitOffsets returns the byte offset of every split relative to the text it was split from
+func splitOffsets(splits []string, splitter string) []int {
+	offsets := make([]int, len(splits))
+	offset := 0
+	for i, split := range splits {
+		offsets[i] = offset
+		offset += len(split) + len(splitter)
+	}
+	return offsets
+}
 
-				}
-			} else {
-				windowStart = i
-				toMerge = make([]string, 0)
-				size = 0
-			}
+// separatorSize returns the size of the largest separator between consecutive splits of text,
+// which is more than the size of splitter if a multi-character terminator or trimmed
+// whitespace separates them
+func (c *TextSplitter) separatorSize(text string, splits []string, starts []int, splitter string) int {
+	size := c.countTokenFunc(splitter)
+	counted := map[string]bool{splitter: true}
+	for i := 1; i < len(splits); i++ {
+		end := starts[i-1] + len(splits[i-1])
+		if end >= starts[i] {
+			continue
 		}
-
-		// still have a chace that single split exceeds chunkSize
-		toMerge = append(toMerge, split)
-		size += l
-		if len(toMerge) > 1 {
-			size += splitterSize
+		separator := text[end:starts[i]]
+		if counted[separator] {
+			continue
+		}
+		counted[separator] = true
+		if n := c.countTokenFunc(separator); n > size {
+			size = n
 		}
 	}
-	if len(toMerge) > 0 {
-		merged := strings.Join(toMerge, splitter)
-		if len(merged) > 0 {
-			result = append(result, merged)
+	return size
+}
+
+// mergeChunks merges consecutive splits into chunks, offset is the position of the splits' parent text
+// and scores are the scores of their boundaries, nil if they were not scored
+func (c *TextSplitter) mergeChunks(text string, offset int, splits []string, starts []int, splitSizes []int, scores []float64, splitter string, chunkSize int, level SplitLevel, depth int) []Chunk {
+	separatorSize := c.separatorSize(text, splits, starts, splitter)
+	var windows [][2]int
+	if merger, ok := c.merger().(ScoredMerger); ok && scores != nil {
+		windows = merger.MergeScored(splitSizes, scores, separatorSize, chunkSize, c.overlapAt(level))
+	} else {
+		windows = c.mergeWindows(splitSizes, separatorSize, chunkSize, c.overlapAt(level))
+	}
+	chunks := make([]Chunk, 0)
+	for _, window := range windows {
+		if window[0] == window[1] {
+			continue
+		}
+		start := starts[window[0]]
+		end := starts[window[1]-1] + len(splits[window[1]-1])
+		if end > start {
+			chunks = append(chunks, Chunk{
+				Text:     text[start:end],
+				Start:    offset + start,
+				End:      offset + end,
+				Metadata: Metadata{Level: level, Depth: depth},
+			})
 		}
 	}
+	return chunks
+}
 
-	return result
+// split recursively splits text, offset is the position of text in the input
+func (c *TextSplitter) split(text string, offset int, chunkSize int, recursionDepth int) []Chunk {
+	rets := make([]Chunk, 0)
+	_ = c.walk(text, offset, chunkSize, recursionDepth, func(chunk Chunk) error {
+		rets = append(rets, chunk)
+		return nil
+	})
+	return rets
 }
 
-func (c *TextSplitter) split(text string, chunkSize int, recursionDepth int) []string {
-	rets := make([]string, 0)
+// walk splits text recursively like split, handing every chunk to emit as soon as it is
+// merged. It stops at the first error returned by emit.
+func (c *TextSplitter) walk(text string, offset int, chunkSize int, recursionDepth int, emit func(Chunk) error) error {
+	ts := semanticSplit(text, c.splitRules())
+	splitter, splits := ts.splitter, ts.splits
+	if err := c.budget.charge(len(splits)); err != nil {
+		return err
+	}
+	if len(splits) == 1 && splits[0] == text {
+		if ts.level == LevelPattern {
+			return c.walkWholeMatch(text, offset, chunkSize, recursionDepth, emit)
+		}
+		if ts.atomic || ts.level == LevelChar || c.countTokenFunc(text) <= chunkSize {
+			// the text can not or need not be split any further
+			return emit(Chunk{
+				Text:     text,
+				Start:    offset,
+				End:      offset + len(text),
+				Metadata: Metadata{Level: ts.level, Depth: recursionDepth},
+			})
+		}
+		if len(ts.detached) > 1 {
+			// the text is a list item marker attached to an item that does not fit, split it off
+			ts.splits = ts.detached
+		} else {
+			// the splitter left the text whole, fall back to characters instead of emitting it oversized
+			ts = textSplit{isWhitespace: true, level: LevelChar, splits: strings.Split(text, "")}
+		}
+		splitter, splits = ts.splitter, ts.splits
+	}
+	starts := ts.starts
+	if starts == nil {
+		starts = splitOffsets(splits, splitter)
+	}
+	return c.walkSplits(text, offset, ts, starts, chunkSize, recursionDepth, emit)
+}
 
-	splitter, _, splits := innerSplit(text, c.opts.PreservePatterns)
+// walkSplits merges the splits of text, which start at the given offsets in text, into chunks
+// and recurses into the splits that are too large on their own
+func (c *TextSplitter) walkSplits(text string, offset int, ts textSplit, starts []int, chunkSize int, recursionDepth int, emit func(Chunk) error) error {
+	splitter, splits := ts.splitter, ts.splits
 
 	goodSplits := make([]string, 0)
+	goodStarts := make([]int, 0)
 	goodSplitSizes := make([]int, 0)
+	var goodScores []float64
+	flush := func() error {
+		merges := c.mergeChunks(text, offset, goodSplits, goodStarts, goodSplitSizes, goodScores, splitter, chunkSize, ts.level, recursionDepth)
+		for _, merge := range merges {
+			if err := emit(merge); err != nil {
+				return err
+			}
+		}
+		goodSplits = make([]string, 0)
+		goodStarts = make([]int, 0)
+		goodSplitSizes = make([]int, 0)
+		if goodScores != nil {
+			goodScores = make([]float64, 0)
+		}
+		return nil
+	}
 
-	for _, split := range splits {
-		l := c.countTokenFunc(split)
+	splitSizes := c.countTokensBatch(splits)
+	for i, split := range splits {
+		l := splitSizes[i]
 		if l < chunkSize {
 			goodSplits = append(goodSplits, split)
+			goodStarts = append(goodStarts, starts[i])
 			goodSplitSizes = append(goodSplitSizes, l)
+			if ts.scores != nil {
+				goodScores = append(goodScores, ts.scores[i])
+			}
+			if ts.separate {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
 			continue
 		}
 		if len(goodSplits) > 0 {
-			merges := c.mergeSplits(goodSplits, goodSplitSizes, splitter, chunkSize)
-
-			rets = append(rets, merges...)
-			goodSplits = make([]string, 0)
-			goodSplitSizes = make([]int, 0)
+			if err := flush(); err != nil {
+				return err
+			}
 		}
 
-		newSplits := c.split(split, chunkSize, recursionDepth+1)
-		rets = append(rets, newSplits...)
+		if ts.level == LevelPattern && l > chunkSize && c.isPreserved(split) {
+			if err := c.walkOversizedMatch(split, offset+starts[i], l, chunkSize, recursionDepth+1, emit); err != nil {
+				return err
+			}
+			continue
+		}
+		if ts.atomic || c.opts.StopDescent != nil && c.opts.StopDescent(split, l) {
+			if err := emit(Chunk{
+				Text:     split,
+				Start:    offset + starts[i],
+				End:      offset + starts[i] + len(split),
+				Metadata: Metadata{Level: ts.level, Depth: recursionDepth + 1, Oversized: l > chunkSize},
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := c.walk(split, offset+starts[i], chunkSize, recursionDepth+1, emit); err != nil {
+			return err
+		}
 	}
 
 	if len(goodSplits) > 0 {
-		merges := c.mergeSplits(goodSplits, goodSplitSizes, splitter, chunkSize)
-		rets = append(rets, merges...)
+		return flush()
 	}
+	return nil
+}
 
-	return rets
+// splitChunks splits text into chunks carrying their offsets, without metadata
+func (c *TextSplitter) splitChunks(text string) []Chunk {
+	chunks := c.fitTokens(c.splitRaw(text), c.chunkSize)
+	return c.fitBytes(c.repairBoundaries(text, chunks, c.chunkSize))
+}
+
+// splitRaw splits text into chunks before the post-processing passes of splitChunks
+func (c *TextSplitter) splitRaw(text string) []Chunk {
+	switch {
+	case c.opts.LangChain != nil:
+		return c.splitLangChain(text)
+	case c.opts.SemchunkCompat:
+		return c.splitSemchunk(text)
+	case c.opts.EstimateTokens:
+		return c.splitEstimated(text, c.chunkSize)
+	}
+	return c.split(text, 0, c.chunkSize, 0)
+}
+
+// splitChunksContext is splitChunks, aborted when ctx is done or a configured limit is exceeded.
+// Invisible characters are removed first if configured; the returned offsets refer to text.
+func (c *TextSplitter) splitChunksContext(ctx context.Context, text string, annotate bool) ([]Chunk, error) {
+	return c.splitParsed(ctx, text, annotate, nil)
+}
+
+// splitParsed is splitChunksContext for structured documents: if parse is not nil, the text
+// is divided into the sections it returns, which are split separately
+func (c *TextSplitter) splitParsed(ctx context.Context, text string, annotate bool, parse structureParser) ([]Chunk, error) {
+	clean, positions := c.cleanText(text)
+	chunks, err := c.splitClean(ctx, clean, parse)
+	if err != nil {
+		return nil, err
+	}
+	return c.newFinisher(text, clean, positions, annotate).finish(chunks), nil
+}
+
+// splitClean splits clean, a text cleaned by cleanText, into the chunks that are finished by
+// a finisher. If parse is not nil, or records or hard boundaries are configured, the text is
+// divided into sections, which are split separately.
+func (c *TextSplitter) splitClean(ctx context.Context, clean string, parse structureParser) ([]Chunk, error) {
+	limited := c.withBudget(ctx)
+	var chunks []Chunk
+	if doc := c.segments(clean, parse); doc == nil {
+		chunks = limited.splitChunks(clean)
+	} else {
+		chunks = limited.splitStructure(clean, *doc)
+	}
+	if err := limited.budget.error(); err != nil {
+		return nil, err
+	}
+	return chunks, nil
 }
 
+// Split splits text into chunks of at most chunkSize tokens. It returns nil if splitting is
+// aborted by WithMaxSplitOps or WithTimeout.
 func (c *TextSplitter) Split(text string) []string {
-	return c.split(text, c.chunkSize, 0)
+	chunks, err := c.splitChunksContext(context.Background(), text, false)
+	if err != nil {
+		return nil
+	}
+	return chunkTexts(chunks)
+}
+
+// SplitWithSize splits text like Split, but into chunks of at most chunkSize tokens instead
+// of the configured size, so one splitter can serve several budgets. The safety margin,
+// minimum overlap and chunk suffix apply to chunkSize as they do to the configured size, and
+// the overlap is scaled with it. It returns nil if the reduced chunk size is not positive.
+func (c *TextSplitter) SplitWithSize(text string, chunkSize int) []string {
+	chunkSize = c.reducedSize(chunkSize)
+	if chunkSize <= 0 {
+		return nil
+	}
+	return c.withChunkSize(chunkSize).Split(text)
+}
+
+// reducedSize returns the chunk size splitting uses for a configured size of chunkSize, which
+// leaves room for the safety margin, the minimum overlap and the chunk suffix, like
+// NewTextSplitter does for the configured size. The result may not be positive.
+func (c *TextSplitter) reducedSize(chunkSize int) int {
+	if margin := c.opts.SafetyMargin; margin > 0 && chunkSize > 0 {
+		chunkSize = int(float64(chunkSize) * (1 - margin))
+		if chunkSize < 1 {
+			chunkSize = 1
+		}
+	}
+	return chunkSize - c.opts.MinOverlap - c.suffixTokens()
 }
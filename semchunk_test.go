@@ -4,6 +4,7 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -46,6 +47,20 @@ func TestInnerSplit(t *testing.T) {
 			isWhitespace: true,
 			want:         []string{"First sentence.", "Second sentence.", "Third sentence."},
 		},
+		{
+			name:         "Mixed sentence terminators",
+			text:         "Is it done? Yes it is. Great! Then ship it",
+			splitter:     " ",
+			isWhitespace: true,
+			want:         []string{"Is it done?", "Yes it is.", "Great!", "Then ship it"},
+		},
+		{
+			name:         "Clause separators after sentence terminators",
+			text:         "First, second; third. Fourth, fifth",
+			splitter:     " ",
+			isWhitespace: true,
+			want:         []string{"First, second; third.", "Fourth, fifth"},
+		},
 		{
 			name:         "Chinese sentence",
 			text:         "文字识别（Optical Character Recognition，OCR）基于腾讯优图实验室的深度学习技术，将图片上的文字内容，智能识别成为可编辑的文本。OCR 支持身份证、名片等卡证类和票据类的印刷体识别，也支持运单等手写体识别，支持提供定制化服务，可以有效地代替人工录入信息。",
@@ -242,3 +257,87 @@ func TestInnerSplitWithPreserveURLs(t *testing.T) {
 	}
 
 }
+
+func TestSplitWithSize(t *testing.T) {
+	text := "One two three. Four five six. Seven eight nine. Ten eleven twelve."
+	splitter := newWordSplitter(t, 6, 0)
+	assert.Equal(t, []string{"One two three. Four five six.", "Seven eight nine. Ten eleven twelve."}, splitter.Split(text))
+	assert.Equal(t, []string{"One two three.", "Four five six.", "Seven eight nine.", "Ten eleven twelve."}, splitter.SplitWithSize(text, 3))
+	assert.Equal(t, newWordSplitter(t, 12, 0).Split(text), splitter.SplitWithSize(text, 12))
+	// the configured size is unchanged
+	assert.Equal(t, splitter.Split(text), newWordSplitter(t, 6, 0).Split(text))
+
+	margin := newWordSplitter(t, 6, 0, WithSafetyMargin(0.5))
+	assert.Equal(t, margin.SplitWithSize(text, 6), splitter.SplitWithSize(text, 3))
+	assert.Nil(t, splitter.SplitWithSize(text, 0))
+}
+
+func TestIntegerOverlap(t *testing.T) {
+	text := "a b c d e f g h"
+	splitter := newWordSplitter(t, 4, 2)
+	assert.Equal(t, 2, splitter.overlap)
+	// an overlap of 2 tokens is the same as half of the chunk size
+	half, err := NewTextSplitter(4, float32(0.5), func(text string) int { return len(strings.Fields(text)) })
+	assert.NoError(t, err)
+	assert.Equal(t, half.Split(text), splitter.Split(text))
+	assert.NotEqual(t, newWordSplitter(t, 4, 0).Split(text), splitter.Split(text))
+}
+
+func TestUnsplittableText(t *testing.T) {
+	// every character is larger than the chunk size and is kept whole
+	splitter, err := NewTextSplitter(2, 0, func(text string) int { return 3 * utf8.RuneCountInString(text) })
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, splitter.Split("ab"))
+
+	// text the splitters leave whole is split into characters if it does not fit
+	splitter, err = NewTextSplitter(10, 0, nil, WithSizeUnit(SizeRunes))
+	assert.NoError(t, err)
+	text := "1. Supercalifragilisticexpialidocious"
+	chunks := splitter.Split(text)
+	// the list item marker is split off the item it does not fit with, not cut up with it
+	assert.Equal(t, []string{"1.", "Supercalif", "ragilistic", "expialidoc", "ious"}, chunks)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, utf8.RuneCountInString(chunk), 10, chunk)
+	}
+}
+
+func TestWithSafetyMargin(t *testing.T) {
+	countWords := func(text string) int { return len(strings.Fields(text)) }
+	splitter, err := NewTextSplitter(10, float32(0.5), countWords, WithSafetyMargin(0.2))
+	assert.NoError(t, err)
+	assert.Equal(t, 8, splitter.chunkSize)
+	assert.Equal(t, 4, splitter.overlap)
+	for _, chunk := range splitter.Split(strings.Repeat("one two three four five. ", 10)) {
+		assert.LessOrEqual(t, countWords(chunk), 8)
+	}
+
+	splitter, err = NewTextSplitter(10, 9, countWords, WithSafetyMargin(0.5))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, splitter.overlap)
+
+	_, err = NewTextSplitter(10, 0, countWords, WithSafetyMargin(1))
+	assert.Error(t, err)
+}
+
+func TestWithStopDescent(t *testing.T) {
+	text := "Some intro words here.\n\n| a | b |\n| 1 | 2 |\n| 3 | 4 |\n\nClosing words are here."
+	isTable := func(piece string, tokens int) bool { return strings.HasPrefix(piece, "|") }
+
+	chunks := newWordSplitter(t, 6, 0, WithStopDescent(isTable)).SplitChunks(text)
+	assert.Equal(t, []string{
+		"Some intro words here.",
+		"| a | b |\n| 1 | 2 |\n| 3 | 4 |",
+		"Closing words are here.",
+	}, chunkTexts(chunks))
+	assert.True(t, chunks[1].Metadata.Oversized)
+	assert.Equal(t, "| a | b |\n| 1 | 2 |\n| 3 | 4 |", text[chunks[1].Start:chunks[1].End])
+
+	// without the predicate the table is split by rows
+	assert.Equal(t, []string{
+		"Some intro words here.",
+		"| a | b |",
+		"| 1 | 2 |",
+		"| 3 | 4 |",
+		"Closing words are here.",
+	}, newWordSplitter(t, 6, 0).Split(text))
+}
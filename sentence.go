@@ -0,0 +1,141 @@
+package semchunk
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var blankLineRegex = regexp.MustCompile(`\r?\n[ \t]*\r?\n`)
+
+// sentenceEnds returns the byte offsets just after every sentence end in text.
+// A sentence ends after a run of terminators and closing punctuation that is followed by
+// whitespace or the end of text; full-width terminators end a sentence on their own. The
+// terminator of a list item marker such as "1." does not end a sentence, nor does quoted
+// speech followed by its attribution, as in `"Stop!" she cried.`
+func sentenceEnds(text string, terminators []string) []int {
+	ends := make([]int, 0)
+	last := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		fullWidth := isFullWidthTerminator(r)
+		if !fullWidth && !isTerminator(r, terminators) {
+			i += size
+			continue
+		}
+
+		// consume the run of terminators and closing punctuation
+		j := i + size
+		for j < len(text) {
+			next, nextSize := utf8.DecodeRuneInString(text[j:])
+			if !isTerminator(next, terminators) && !isFullWidthTerminator(next) && !strings.ContainsRune(closingPunctuation, next) {
+				break
+			}
+			j += nextSize
+		}
+
+		if next, _ := utf8.DecodeRuneInString(text[j:]); (fullWidth || j == len(text) || unicode.IsSpace(next)) && !isEnumerationMarker(text[last:j]) && !continuesQuote(text[:j], text[j:]) {
+			ends = append(ends, j)
+			last = j
+		}
+		i = j
+	}
+	return ends
+}
+
+func isTerminator(r rune, terminators []string) bool {
+	for _, terminator := range terminators {
+		if string(r) == terminator {
+			return true
+		}
+	}
+	return false
+}
+
+func isFullWidthTerminator(r rune) bool {
+	for _, terminator := range fullWidthSentenceTerminators {
+		if string(r) == terminator {
+			return true
+		}
+	}
+	return false
+}
+
+// skipSpace returns the offset of the first non-whitespace byte at or after i
+func skipSpace(text string, i int) int {
+	for i < len(text) {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+// sentenceSpans returns the byte spans of the sentences in text, with surrounding whitespace
+// trimmed. Blank lines also end a sentence, so headings and list items without terminators
+// are not glued to the following paragraph.
+func sentenceSpans(text string, terminators []string) [][2]int {
+	boundaries := sentenceEnds(text, terminators)
+	for _, match := range blankLineRegex.FindAllStringIndex(text, -1) {
+		boundaries = append(boundaries, match[0])
+	}
+	sort.Ints(boundaries)
+
+	spans := make([][2]int, 0, len(boundaries)+1)
+	start := 0
+	for _, end := range append(boundaries, len(text)) {
+		if end < start {
+			continue
+		}
+		s := skipSpace(text, start)
+		e := end
+		for e > s {
+			r, size := utf8.DecodeLastRuneInString(text[:e])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			e -= size
+		}
+		if e > s {
+			spans = append(spans, [2]int{s, e})
+		}
+		start = end
+	}
+	return spans
+}
+
+// SplitSentenceWindows emits every sentence of text as its own chunk and attaches the
+// window sentences before and after it as the chunk's Context, implementing sentence window
+// retrieval: embed the sentence, but hand the surrounding window to the model.
+// Sentences are not split further, even when they exceed the chunk size. A negative window
+// is treated as 0.
+func (c *TextSplitter) SplitSentenceWindows(text string, window int) []Chunk {
+	if window < 0 {
+		window = 0
+	}
+	spans := sentenceSpans(text, c.splitRules().terminators())
+	chunks := make([]Chunk, len(spans))
+	for i, span := range spans {
+		lo, hi := i-window, i+window
+		if lo < 0 {
+			lo = 0
+		}
+		if hi > len(spans)-1 {
+			hi = len(spans) - 1
+		}
+		chunks[i] = Chunk{
+			Text:  text[span[0]:span[1]],
+			Start: span[0],
+			End:   span[1],
+			Metadata: Metadata{
+				Level:   LevelSentence,
+				Context: text[spans[lo][0]:spans[hi][1]],
+			},
+		}
+	}
+	return c.newFinisher(text, text, nil, true).finish(chunks)
+}
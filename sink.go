@@ -0,0 +1,181 @@
+package semchunk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+)
+
+// ChunkSink receives chunks as they are produced by SplitTo
+type ChunkSink interface {
+	Write(chunk Chunk) error
+}
+
+// ChunkSinkFunc adapts an ordinary function to the ChunkSink interface
+type ChunkSinkFunc func(chunk Chunk) error
+
+func (f ChunkSinkFunc) Write(chunk Chunk) error {
+	return f(chunk)
+}
+
+// ChannelSink sends every chunk on a channel, blocking until it is received
+type ChannelSink chan<- Chunk
+
+func (s ChannelSink) Write(chunk Chunk) error {
+	s <- chunk
+	return nil
+}
+
+// JSONLSink writes every chunk as one line of JSON
+type JSONLSink struct {
+	enc *json.Encoder
+}
+
+func NewJSONLSink(w io.Writer) *JSONLSink {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &JSONLSink{enc: enc}
+}
+
+func (s *JSONLSink) Write(chunk Chunk) error {
+	return s.enc.Encode(chunk)
+}
+
+// streamBatchSize is the number of chunks SplitTo post-processes and counts at a time
+const streamBatchSize = 64
+
+// SplitTo splits text like SplitChunks, but writes chunks to sink as they are produced
+// instead of collecting them, so only a small batch of chunks is held in memory at a time.
+// Chunks are written in text order, numbered by Seq.
+// It stops at the first error returned by sink, or when a limit set by WithMaxSplitOps or
+// WithTimeout is exceeded.
+func (c *TextSplitter) SplitTo(text string, sink ChunkSink) error {
+	return c.splitTo(context.Background(), text, sink)
+}
+
+func (c *TextSplitter) splitTo(ctx context.Context, text string, sink ChunkSink) error {
+	clean, positions := c.cleanText(text)
+	c = c.withBudget(ctx)
+	stream := &chunkStream{sink: sink, finisher: c.newFinisher(text, clean, positions, true)}
+	doc := c.segments(clean, nil)
+	if doc == nil {
+		return stream.split(c, clean, 0, nil)
+	}
+	// segments are split like the sections of splitStructure
+	for _, section := range doc.sections {
+		start, end := sectionBounds(clean, section)
+		if end <= start {
+			continue
+		}
+		if err := stream.split(c.forSection(section), clean[start:end], start, section.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chunkStream applies the post-processing passes of splitChunks and a finisher to batches of
+// chunks and writes them to a sink
+type chunkStream struct {
+	// c is the splitter of the segment being split, text the segment, which starts at offset
+	// in the text being split, and path its heading path
+	c        *TextSplitter
+	text     string
+	offset   int
+	path     []string
+	sink     ChunkSink
+	finisher *finisher
+	pending  []Chunk
+}
+
+// split splits text, a segment starting at offset, with c like splitChunks does and writes its
+// chunks
+func (s *chunkStream) split(c *TextSplitter, text string, offset int, path []string) error {
+	s.c, s.text, s.offset, s.path = c, text, offset, path
+	var err error
+	if c.opts.EstimateTokens || c.opts.LangChain != nil || c.opts.SemchunkCompat {
+		// estimation needs all chunks to decide which ones to count exactly
+		for _, chunk := range c.splitRaw(text) {
+			if err = s.push(chunk); err != nil {
+				break
+			}
+		}
+	} else {
+		err = c.walk(text, 0, c.chunkSize, 0, s.push)
+	}
+	if err != nil {
+		return err
+	}
+	if err := c.budget.error(); err != nil {
+		return err
+	}
+	// boundary repair does not move content between segments
+	return s.flush(true)
+}
+
+func (s *chunkStream) push(chunk Chunk) error {
+	s.pending = append(s.pending, s.c.fitTokens([]Chunk{chunk}, s.c.chunkSize)...)
+	if len(s.pending) < streamBatchSize {
+		return nil
+	}
+	return s.flush(false)
+}
+
+// flush writes the pending chunks to the sink. Unless final, the last chunk is held back,
+// because boundary repair may still move its end.
+func (s *chunkStream) flush(final bool) error {
+	s.pending = s.c.fitBytes(s.c.repairBoundaries(s.text, s.pending, s.c.chunkSize))
+	n := len(s.pending)
+	if !final {
+		n--
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	rest := s.pending[n:]
+	index := s.finisher.index
+	ready := make([]Chunk, n)
+	for i, chunk := range s.pending[:n] {
+		chunk.Start += s.offset
+		chunk.End += s.offset
+		chunk.Metadata.HeadingPath = s.path
+		ready[i] = chunk
+	}
+	ready = s.finisher.finish(ready)
+	for i := range ready {
+		ready[i].Seq = int64(index + i + 1)
+	}
+	for _, chunk := range ready {
+		if err := s.sink.Write(chunk); err != nil {
+			return err
+		}
+	}
+	s.pending = append(make([]Chunk, 0, streamBatchSize), rest...)
+	return nil
+}
+
+// SplitChan splits text in a separate goroutine and sends the chunks on the returned channel
+// as they are produced. The channel is unbuffered, so splitting blocks while the consumer is
+// busy. Both channels are closed when splitting ends; the error channel receives at most one
+// error, which is the context's error if ctx is cancelled before all chunks are delivered.
+func (c *TextSplitter) SplitChan(ctx context.Context, text string) (<-chan Chunk, <-chan error) {
+	chunks := make(chan Chunk)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		defer close(chunks)
+		err := c.splitTo(ctx, text, ChunkSinkFunc(func(chunk Chunk) error {
+			select {
+			case chunks <- chunk:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}))
+		if err != nil {
+			errs <- err
+		}
+	}()
+	return chunks, errs
+}
@@ -0,0 +1,132 @@
+package semchunk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitTo(t *testing.T) {
+	text := strings.Repeat("Apples are red. Bananas are yellow, and cherries are dark.\n\n", 50)
+	for _, opts := range [][]func(*TextSplitterOption){
+		nil,
+		{WithBoundaryRepair(20)},
+		{WithTokenEstimation(0.2)},
+	} {
+		splitter := newWordSplitter(t, 7, 2, opts...)
+		expected := splitter.SplitChunks(text)
+		for i := range expected {
+			expected[i].Seq = int64(i + 1)
+		}
+
+		var got []Chunk
+		err := splitter.SplitTo(text, ChunkSinkFunc(func(chunk Chunk) error {
+			got = append(got, chunk)
+			return nil
+		}))
+		assert.NoError(t, err)
+		assert.Greater(t, len(got), streamBatchSize)
+		assert.Equal(t, expected, got)
+	}
+}
+
+func TestSplitToHardBoundaries(t *testing.T) {
+	long := strings.Repeat("Apples are red. Bananas are yellow, and cherries are dark. ", 30)
+	texts := []string{
+		"\n ",
+		"\r\n ",
+		"## One\n\n" + long + "\n\n## Two\n\nShort part.  \n\n",
+		"\n\n" + long + " " + strings.Repeat("x", 120) + "   \n",
+	}
+	for _, opts := range [][]func(*TextSplitterOption){
+		{WithHardBoundaries("##")},
+		{WithHardBoundaries("##"), WithDividers(true)},
+		{WithHardBoundaries("##"), WithPreservePatterns(`x+`), WithWholeMatch(WholeMatchWrap, 50)},
+		{WithHardBoundaries("##"), WithMerger(BalancedMerger{}), WithBoundaryRepair(20)},
+		{WithRecords(regexp.MustCompile(`\n## `), true), WithBoundaryRepair(20)},
+	} {
+		splitter, err := NewTextSplitter(10, 0, utf8.RuneCountInString, opts...)
+		assert.NoError(t, err)
+		for _, text := range texts {
+			expected := splitter.SplitChunks(text)
+			for i := range expected {
+				expected[i].Seq = int64(i + 1)
+			}
+			got := make([]Chunk, 0)
+			assert.NoError(t, splitter.SplitTo(text, ChunkSinkFunc(func(chunk Chunk) error {
+				got = append(got, chunk)
+				return nil
+			})))
+			assert.Equal(t, expected, got, "%q", text)
+		}
+	}
+}
+
+func TestSplitToSinkError(t *testing.T) {
+	errFull := errors.New("full")
+	written := 0
+	err := newWordSplitter(t, 3, 0).SplitTo("a b c. d e f. g h i.", ChunkSinkFunc(func(chunk Chunk) error {
+		if written == 1 {
+			return errFull
+		}
+		written++
+		return nil
+	}))
+	assert.ErrorIs(t, err, errFull)
+	assert.Equal(t, 1, written)
+}
+
+func TestJSONLSink(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, newWordSplitter(t, 3, 0).SplitTo("a b c. d e f.", NewJSONLSink(&buf)))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	var chunk Chunk
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &chunk))
+	assert.Equal(t, "d e f.", chunk.Text)
+	assert.Equal(t, 1, chunk.Index)
+}
+
+func TestChannelSink(t *testing.T) {
+	ch := make(chan Chunk)
+	go func() {
+		defer close(ch)
+		assert.NoError(t, newWordSplitter(t, 3, 0).SplitTo("a b c. d e f.", ChannelSink(ch)))
+	}()
+
+	texts := make([]string, 0)
+	for chunk := range ch {
+		texts = append(texts, chunk.Text)
+	}
+	assert.Equal(t, []string{"a b c.", "d e f."}, texts)
+}
+
+func TestSplitChan(t *testing.T) {
+	chunks, errs := newWordSplitter(t, 3, 0).SplitChan(context.Background(), "a b c. d e f. g h i.")
+	texts := make([]string, 0)
+	for chunk := range chunks {
+		texts = append(texts, chunk.Text)
+	}
+	assert.NoError(t, <-errs)
+	assert.Equal(t, []string{"a b c.", "d e f.", "g h i."}, texts)
+}
+
+func TestSplitChanCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	chunks, errs := newWordSplitter(t, 3, 0).SplitChan(ctx, strings.Repeat("a b c. ", 1000))
+
+	<-chunks
+	cancel()
+	for range chunks {
+		// drain chunks that were sent before the cancellation was noticed
+	}
+	assert.ErrorIs(t, <-errs, context.Canceled)
+}
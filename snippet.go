@@ -0,0 +1,177 @@
+package semchunk
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// snippetBytesPerToken bounds the bytes per token when SplitAround decides how much of the
+// text around the offset to look at
+const snippetBytesPerToken = 16
+
+// SplitAround returns a chunk of at most budget tokens around the byte offset in text, such
+// as a keyword hit, for generating snippets at query time without splitting the whole text.
+// The chunk is made of whole sentences, starting with the sentence containing offset and
+// growing alternately forward and backward while it fits. If that sentence alone exceeds the
+// budget, it is split like any text and the piece containing offset is returned. The chunk
+// does not span a hard boundary, see WithHardBoundaries. A budget of 0 uses the chunk size of
+// the splitter. It returns an empty chunk if text is empty.
+func (c *TextSplitter) SplitAround(text string, offset int, budget int) Chunk {
+	if text == "" {
+		return Chunk{}
+	}
+	budget = c.snippetBudget(budget)
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(text) {
+		offset = len(text)
+	}
+	for offset > 0 && offset < len(text) && !utf8.RuneStart(text[offset]) {
+		offset--
+	}
+
+	return c.finishSnippets(text, []Chunk{c.snippet(text, offset, budget)})[0]
+}
+
+// snippet finds the chunk returned by SplitAround
+func (c *TextSplitter) snippet(text string, offset int, budget int) Chunk {
+	// only the sentences within reach of the budget are looked at; the first and last
+	// sentences of a region cut from the text may be incomplete and are left out
+	reach := budget * snippetBytesPerToken
+	lo, hi := offset-reach, offset+reach
+	if lo < 0 {
+		lo = 0
+	}
+	if hi > len(text) {
+		hi = len(text)
+	}
+	for lo > 0 && !utf8.RuneStart(text[lo]) {
+		lo--
+	}
+	for hi < len(text) && !utf8.RuneStart(text[hi]) {
+		hi++
+	}
+	// nor does the chunk span a hard boundary
+	segmentStart, segmentEnd := c.hardSegment(text, offset, lo, hi)
+	if lo < segmentStart {
+		lo = segmentStart
+	}
+	if hi > segmentEnd {
+		hi = segmentEnd
+	}
+	spans := sentenceSpans(text[lo:hi], c.splitRules().terminators())
+	for i := range spans {
+		spans[i][0] += lo
+		spans[i][1] += lo
+	}
+	if lo > segmentStart && len(spans) > 1 && spans[0][1] <= offset {
+		spans = spans[1:]
+	}
+	if hi < segmentEnd && len(spans) > 1 && spans[len(spans)-1][0] > offset {
+		spans = spans[:len(spans)-1]
+	}
+	if len(spans) == 0 {
+		return Chunk{Start: offset, End: offset, Metadata: Metadata{Level: LevelSentence}}
+	}
+
+	// the sentence containing offset, or the first one after it
+	center := len(spans) - 1
+	for i, span := range spans {
+		if offset < span[1] {
+			center = i
+			break
+		}
+	}
+	first, last := center, center
+	if c.countTokenFunc(text[spans[center][0]:spans[center][1]]) > budget {
+		return c.splitSentenceAround(text, spans[center], offset, budget)
+	}
+	for grew := true; grew; {
+		grew = false
+		if last+1 < len(spans) && c.countTokenFunc(text[spans[first][0]:spans[last+1][1]]) <= budget {
+			last++
+			grew = true
+		}
+		if first > 0 && c.countTokenFunc(text[spans[first-1][0]:spans[last][1]]) <= budget {
+			first--
+			grew = true
+		}
+	}
+	start, end := spans[first][0], spans[last][1]
+	return Chunk{Text: text[start:end], Start: start, End: end, Metadata: Metadata{Level: LevelSentence}}
+}
+
+// splitSentenceAround splits a sentence larger than budget and returns the piece containing
+// offset, or the nearest one
+func (c *TextSplitter) splitSentenceAround(text string, sentence [2]int, offset int, budget int) Chunk {
+	pieces := c.withChunkSize(budget).split(text[sentence[0]:sentence[1]], sentence[0], budget, 0)
+	if len(pieces) == 0 {
+		return Chunk{Start: offset, End: offset, Metadata: Metadata{Level: LevelSentence}}
+	}
+	best := pieces[0]
+	for _, piece := range pieces {
+		if piece.Start <= offset {
+			best = piece
+		}
+		if offset < piece.End {
+			break
+		}
+	}
+	return best
+}
+
+// SplitHighlights returns chunks of at most budget tokens, each containing at least one of
+// the byte ranges spans, such as the matches of a search engine, for snippeting. Chunks are
+// made of whole sentences where possible, like those of SplitAround, do not overlap, and are
+// in text order; the spans starting in each chunk are stored in Metadata.Highlights. Spans
+// outside text are ignored. A budget of 0 uses the chunk size of the splitter.
+func (c *TextSplitter) SplitHighlights(text string, spans [][2]int, budget int) []Chunk {
+	budget = c.snippetBudget(budget)
+	sorted := make([][2]int, 0, len(spans))
+	for _, span := range spans {
+		if span[0] >= 0 && span[0] < span[1] && span[1] <= len(text) {
+			sorted = append(sorted, span)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i][0] < sorted[j][0] })
+
+	chunks := make([]Chunk, 0)
+	prevEnd := 0
+	for _, span := range sorted {
+		if n := len(chunks); n > 0 && span[0] < chunks[n-1].End {
+			chunks[n-1].Metadata.Highlights = append(chunks[n-1].Metadata.Highlights, span)
+			continue
+		}
+		// look only after the previous chunk, so chunks do not overlap
+		chunk := c.snippet(text[prevEnd:], span[0]-prevEnd, budget)
+		chunk.Start += prevEnd
+		chunk.End += prevEnd
+		if chunk.End <= span[0] {
+			continue
+		}
+		chunk.Metadata.Highlights = [][2]int{span}
+		chunks = append(chunks, chunk)
+		prevEnd = chunk.End
+	}
+	return c.finishSnippets(text, chunks)
+}
+
+// finishSnippets finishes the snippets of text, keeping all of them since they were asked for
+func (c *TextSplitter) finishSnippets(text string, chunks []Chunk) []Chunk {
+	f := c.newFinisher(text, text, nil, true)
+	f.keep = true
+	return f.finish(chunks)
+}
+
+// snippetBudget returns the tokens left of budget for the text of a snippet after the chunk
+// suffix, at least one, or the chunk size if budget is not positive
+func (c *TextSplitter) snippetBudget(budget int) int {
+	if budget <= 0 {
+		return c.chunkSize
+	}
+	if budget -= c.suffixTokens(); budget < 1 {
+		return 1
+	}
+	return budget
+}
@@ -0,0 +1,76 @@
+package semchunk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitAround(t *testing.T) {
+	text := "One two three. Four five six. Seven eight nine. Ten eleven twelve. Thirteen fourteen fifteen."
+	splitter := newWordSplitter(t, 100, 0)
+
+	hit := strings.Index(text, "eight")
+	chunk := splitter.SplitAround(text, hit, 6)
+	assert.Equal(t, "Seven eight nine. Ten eleven twelve.", chunk.Text)
+	assert.Equal(t, text[chunk.Start:chunk.End], chunk.Text)
+	assert.Equal(t, 6, chunk.Tokens)
+	assert.Equal(t, LevelSentence, chunk.Metadata.Level)
+
+	// near the end, the chunk grows backward
+	chunk = splitter.SplitAround(text, len(text)-1, 7)
+	assert.Equal(t, "Ten eleven twelve. Thirteen fourteen fifteen.", chunk.Text)
+
+	// a sentence larger than the budget is split around the offset
+	chunk = splitter.SplitAround(text, hit, 2)
+	assert.Equal(t, "Seven eight", chunk.Text)
+	assert.Equal(t, strings.Index(text, "Seven"), chunk.Start)
+
+	// only the text within reach of the budget is looked at
+	long := strings.Repeat("Filler words here. ", 1000) + "The needle is here. " + strings.Repeat("More filler now. ", 1000)
+	chunk = splitter.SplitAround(long, strings.Index(long, "needle"), 4)
+	assert.Equal(t, "The needle is here.", chunk.Text)
+
+	assert.Equal(t, Chunk{}, splitter.SplitAround("", 0, 4))
+
+	// the chunk does not grow across hard boundaries
+	pages := "Intro text. More intro.\fPage two starts. The needle sits here. Page two ends.\fPage three."
+	hard := newWordSplitter(t, 100, 0, WithHardBoundaries("\f"))
+	chunk = hard.SplitAround(pages, strings.Index(pages, "needle"), 20)
+	assert.Equal(t, "Page two starts. The needle sits here. Page two ends.", chunk.Text)
+	assert.Equal(t, pages[chunk.Start:chunk.End], chunk.Text)
+	assert.Equal(t, "Page three.", hard.SplitAround(pages, len(pages)-1, 20).Text)
+	// the separator starts the text after it
+	assert.Equal(t, "Page two starts. The needle sits here. Page two ends.", hard.SplitAround(pages, strings.Index(pages, "\f"), 20).Text)
+}
+
+func TestSplitHighlights(t *testing.T) {
+	text := "Alpha beta gamma. Delta epsilon zeta. Eta theta iota. Kappa lambda mu. Nu xi omicron."
+	splitter := newWordSplitter(t, 100, 0)
+	span := func(word string) [2]int {
+		i := strings.Index(text, word)
+		return [2]int{i, i + len(word)}
+	}
+
+	chunks := splitter.SplitHighlights(text, [][2]int{span("omicron"), span("beta"), span("zeta"), {-1, 2}, {5, 500}}, 6)
+	assert.Equal(t, []string{"Alpha beta gamma. Delta epsilon zeta.", "Kappa lambda mu. Nu xi omicron."}, chunkTexts(chunks))
+	assert.Equal(t, [][2]int{span("beta"), span("zeta")}, chunks[0].Metadata.Highlights)
+	assert.Equal(t, [][2]int{span("omicron")}, chunks[1].Metadata.Highlights)
+	for i, chunk := range chunks {
+		assert.Equal(t, i, chunk.Index)
+		assert.Equal(t, text[chunk.Start:chunk.End], chunk.Text)
+		assert.LessOrEqual(t, chunk.Tokens, 6)
+	}
+
+	// chunks do not overlap, even for spans in neighbouring sentences
+	chunks = splitter.SplitHighlights(text, [][2]int{span("gamma"), span("Delta")}, 3)
+	assert.Equal(t, []string{"Alpha beta gamma.", "Delta epsilon zeta."}, chunkTexts(chunks))
+
+	assert.Empty(t, splitter.SplitHighlights(text, nil, 6))
+
+	pages := "Intro text. More intro.\fPage two starts. Page two ends."
+	hard := newWordSplitter(t, 100, 0, WithHardBoundaries("\f"))
+	chunks = hard.SplitHighlights(pages, [][2]int{{0, 5}, {len(pages) - 5, len(pages)}}, 20)
+	assert.Equal(t, []string{"Intro text. More intro.", "Page two starts. Page two ends."}, chunkTexts(chunks))
+}
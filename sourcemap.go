@@ -0,0 +1,62 @@
+package semchunk
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// SourceMap relates chunks to the spans of the documents they were cut from, so the text of
+// a retrieved chunk can be highlighted in its source or audited later
+type SourceMap struct {
+	Version int              `json:"version"`
+	Chunks  []SourceMapEntry `json:"chunks"`
+}
+
+// SourceMapEntry locates one chunk in its document
+type SourceMapEntry struct {
+	ChunkID    string `json:"chunk_id"`
+	DocumentID string `json:"document_id,omitempty"`
+	// Start and End are the byte offsets of the chunk in the document
+	Start int `json:"start"`
+	End   int `json:"end"`
+	// Line and LineEnd are the first and last line of the chunk, from 1
+	Line    int `json:"line,omitempty"`
+	LineEnd int `json:"line_end,omitempty"`
+	// Section is the heading path of the section the chunk is in, outermost first
+	Section []string `json:"section,omitempty"`
+}
+
+// ChunkID identifies a chunk by its document ID and index, as "doc#3", or only by its index
+// if it has no document ID
+func ChunkID(chunk Chunk) string {
+	id := strconv.Itoa(chunk.Index)
+	if chunk.Metadata.DocumentID != "" {
+		id = chunk.Metadata.DocumentID + "#" + id
+	}
+	return id
+}
+
+// NewSourceMap builds the source map of chunks
+func NewSourceMap(chunks []Chunk) SourceMap {
+	m := SourceMap{Version: 1, Chunks: make([]SourceMapEntry, len(chunks))}
+	for i, chunk := range chunks {
+		m.Chunks[i] = SourceMapEntry{
+			ChunkID:    ChunkID(chunk),
+			DocumentID: chunk.Metadata.DocumentID,
+			Start:      chunk.Start,
+			End:        chunk.End,
+			Line:       chunk.Metadata.Line,
+			LineEnd:    chunk.Metadata.LineEnd,
+			Section:    chunk.Metadata.HeadingPath,
+		}
+	}
+	return m
+}
+
+// WriteSourceMap writes the source map of chunks to w as indented JSON
+func WriteSourceMap(w io.Writer, chunks []Chunk) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(NewSourceMap(chunks))
+}
@@ -0,0 +1,37 @@
+package semchunk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceMap(t *testing.T) {
+	splitter := newWordSplitter(t, 4, 0)
+	chunks, err := splitter.SplitMarkdownContext(context.Background(), "# Intro\n\nFirst words here.\n\n## Details\n\nSecond words here.\n")
+	assert.NoError(t, err)
+	for i := range chunks {
+		chunks[i].Metadata.DocumentID = "guide.md"
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteSourceMap(&buf, chunks))
+	var m SourceMap
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, NewSourceMap(chunks), m)
+
+	assert.Equal(t, 1, m.Version)
+	assert.Len(t, m.Chunks, len(chunks))
+	last := m.Chunks[len(m.Chunks)-1]
+	assert.Equal(t, "guide.md#"+strconv.Itoa(len(chunks)-1), last.ChunkID)
+	assert.Equal(t, "guide.md", last.DocumentID)
+	assert.Equal(t, []string{"Intro", "Details"}, last.Section)
+	assert.Equal(t, 7, last.Line)
+	assert.Equal(t, chunks[len(chunks)-1].End, last.End)
+
+	assert.Equal(t, "2", ChunkID(Chunk{Index: 2}))
+}
@@ -0,0 +1,131 @@
+package semchunk
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// WithSplitConcurrency sets how many documents SplitAll and SplitAllTo split at the same time,
+// default GOMAXPROCS
+func WithSplitConcurrency(n int) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.SplitConcurrency = n
+	}
+}
+
+// SplitAll splits every document with SplitDocument, several at a time, and returns their
+// chunks in the order of docs. The result does not depend on scheduling: the chunks of every
+// document are exactly those SplitDocument returns, with the same indices. The first error
+// stops the remaining documents and is returned.
+func (c *TextSplitter) SplitAll(ctx context.Context, docs []Document) ([][]Chunk, error) {
+	results := make([][]Chunk, len(docs))
+	err := c.splitDocuments(ctx, docs, func(i int, chunks []Chunk) error {
+		results[i] = chunks
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// SplitAllTo splits documents like SplitAll and writes their chunks to sink, all chunks of a
+// document before those of the next one, in the order of docs. Chunks are numbered by Seq
+// across the whole stream, so consumers that process them in parallel can restore the order.
+// Only a few documents beyond the one being written are split ahead.
+func (c *TextSplitter) SplitAllTo(ctx context.Context, docs []Document, sink ChunkSink) error {
+	var seq int64
+	return c.splitDocuments(ctx, docs, func(i int, chunks []Chunk) error {
+		for _, chunk := range chunks {
+			seq++
+			chunk.Seq = seq
+			if err := sink.Write(chunk); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// splitDocuments splits docs concurrently and calls emit with the chunks of every document in
+// the order of docs
+func (c *TextSplitter) splitDocuments(ctx context.Context, docs []Document, emit func(i int, chunks []Chunk) error) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	workers := c.opts.SplitConcurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(docs) {
+		workers = len(docs)
+	}
+
+	type result struct {
+		chunks []Chunk
+		err    error
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	results := make([]chan result, len(docs))
+	for i := range results {
+		results[i] = make(chan result, 1)
+	}
+	// window bounds how far splitting runs ahead of emit
+	window := make(chan struct{}, 2*workers)
+	next := make(chan int)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(next)
+		for i := range docs {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case next <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				chunks, err := c.SplitDocument(ctx, docs[i])
+				results[i] <- result{chunks, err}
+			}
+		}()
+	}
+
+	for i := range docs {
+		var r result
+		select {
+		case r = <-results[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if r.err != nil {
+			return fmt.Errorf("document %d: %w", i, r.err)
+		}
+		if err := emit(i, r.chunks); err != nil {
+			return err
+		}
+		<-window
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package semchunk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testDocuments returns documents whose first entries are the slowest to split, so workers
+// finish them out of order
+func testDocuments(n int) []Document {
+	docs := make([]Document, n)
+	for i := range docs {
+		docs[i] = Document{
+			ID:   fmt.Sprintf("doc%d", i),
+			Text: strings.Repeat(fmt.Sprintf("Sentence %d of the document. ", i), 5+(n-i)%7),
+		}
+	}
+	return docs
+}
+
+func newSlowSplitter(t *testing.T, opts ...func(*TextSplitterOption)) *TextSplitter {
+	splitter, err := NewTextSplitter(8, 2, func(text string) int {
+		if strings.Contains(text, "Sentence 0 ") || strings.Contains(text, "Sentence 1 ") {
+			time.Sleep(100 * time.Microsecond)
+		}
+		return len(strings.Fields(text))
+	}, opts...)
+	assert.NoError(t, err)
+	return splitter
+}
+
+func TestSplitAll(t *testing.T) {
+	docs := testDocuments(20)
+	splitter := newSlowSplitter(t, WithSplitConcurrency(8))
+
+	got, err := splitter.SplitAll(context.Background(), docs)
+	assert.NoError(t, err)
+	if assert.Len(t, got, len(docs)) {
+		for i, doc := range docs {
+			expected, err := splitter.SplitDocument(context.Background(), doc)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, got[i])
+		}
+	}
+
+	got, err = splitter.SplitAll(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestSplitAllTo(t *testing.T) {
+	docs := testDocuments(20)
+	splitter := newSlowSplitter(t, WithSplitConcurrency(8))
+
+	var got []Chunk
+	err := splitter.SplitAllTo(context.Background(), docs, ChunkSinkFunc(func(chunk Chunk) error {
+		got = append(got, chunk)
+		return nil
+	}))
+	assert.NoError(t, err)
+
+	var expected []Chunk
+	for _, doc := range docs {
+		chunks, err := splitter.SplitDocument(context.Background(), doc)
+		assert.NoError(t, err)
+		expected = append(expected, chunks...)
+	}
+	for i := range expected {
+		expected[i].Seq = int64(i + 1)
+	}
+	assert.Equal(t, expected, got)
+}
+
+func TestSplitAllError(t *testing.T) {
+	errFailed := errors.New("failed")
+	splitter := newSlowSplitter(t, WithContextGenerator(func(ctx context.Context, req ContextRequest) (string, error) {
+		if strings.Contains(req.Chunk.Text, "Sentence 3 ") {
+			return "", errFailed
+		}
+		return "", nil
+	}, false))
+
+	_, err := splitter.SplitAll(context.Background(), testDocuments(10))
+	assert.ErrorIs(t, err, errFailed)
+	assert.ErrorContains(t, err, "document 3")
+
+	written := 0
+	err = splitter.SplitAllTo(context.Background(), testDocuments(10), ChunkSinkFunc(func(chunk Chunk) error {
+		written++
+		return nil
+	}))
+	assert.ErrorIs(t, err, errFailed)
+	assert.Greater(t, written, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = splitter.SplitAll(ctx, testDocuments(10))
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,38 @@
+package semchunk
+
+import "context"
+
+// SplitIntoN splits text into about n chunks of even size, for example to show a document
+// as n cards. It searches for the smallest chunk size that splits text into at most n
+// chunks, so it returns fewer chunks only if text cannot be split into n, and merges splits
+// with BalancedMerger unless another merger is set. The overlap is scaled with the chunk
+// size. Text is split once per step of the binary search, about log2 of its token count
+// times. It returns nil if n is not positive.
+func (c *TextSplitter) SplitIntoN(text string, n int) []Chunk {
+	if n <= 0 {
+		return nil
+	}
+	splitter := c
+	if c.opts.Merger == nil {
+		opts := *c.opts
+		opts.Merger = BalancedMerger{}
+		balanced := *c
+		balanced.opts = &opts
+		splitter = &balanced
+	}
+
+	low, high := 1, c.countTokenFunc(text)
+	if high < 1 {
+		high = 1
+	}
+	for low < high {
+		mid := (low + high) / 2
+		chunks, err := splitter.withChunkSize(mid).splitChunksContext(context.Background(), text, false)
+		if err == nil && len(chunks) <= n {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+	return splitter.withChunkSize(high).SplitChunks(text)
+}
@@ -0,0 +1,31 @@
+package semchunk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitIntoN(t *testing.T) {
+	text := strings.Repeat("One two three four. Five six seven. ", 10)
+	splitter := newWordSplitter(t, 5, 0)
+
+	for _, n := range []int{1, 2, 3, 5, 10} {
+		chunks := splitter.SplitIntoN(text, n)
+		assert.Len(t, chunks, n, "n=%d", n)
+		min, max := chunks[0].Tokens, chunks[0].Tokens
+		for _, chunk := range chunks {
+			if chunk.Tokens < min {
+				min = chunk.Tokens
+			}
+			if chunk.Tokens > max {
+				max = chunk.Tokens
+			}
+		}
+		assert.LessOrEqual(t, max-min, 4, "n=%d", n)
+		assert.Equal(t, 0, chunks[0].Start)
+	}
+
+	assert.Nil(t, splitter.SplitIntoN(text, 0))
+}
@@ -0,0 +1,282 @@
+package semchunk
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Section is a part of a structured document that starts at a heading
+type Section struct {
+	// Heading is the text of the heading without markup, empty for text before the first heading
+	Heading string `json:"heading"`
+	// Level is the heading level, from 1 for top level headings, 0 for text before the first heading
+	Level int `json:"level"`
+	// Path holds the headings of the section and its ancestors, outermost first
+	Path []string `json:"path,omitempty"`
+	// Start and End are the byte offsets of the section, including its heading, in the text
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// documentStructure is the result of parsing a structured document
+type documentStructure struct {
+	sections []Section
+	// blocks are the spans, such as code blocks, that should be kept in one chunk
+	blocks [][2]int
+	// footnotes are the footnote definitions by label, referenced with footnoteSyntax
+	footnotes      map[string]string
+	footnoteSyntax footnoteSyntax
+}
+
+// structureParser parses the sections and atomic blocks of a structured document
+type structureParser func(text string) documentStructure
+
+// SplitMarkdown splits a Markdown document along its headings. Chunks never span two
+// sections and carry the heading path of their section in Metadata.HeadingPath. Fenced code
+// blocks are kept in one chunk unless they exceed the chunk size on their own. Footnotes and
+// numbered references used in a chunk are listed with their definitions in Metadata.Footnotes.
+func (c *TextSplitter) SplitMarkdown(text string) []Chunk {
+	chunks, _ := c.SplitMarkdownContext(context.Background(), text)
+	return chunks
+}
+
+// SplitMarkdownContext is SplitMarkdown, returning an error if splitting is aborted
+func (c *TextSplitter) SplitMarkdownContext(ctx context.Context, text string) ([]Chunk, error) {
+	return c.splitParsed(ctx, text, true, parseMarkdown)
+}
+
+// Outline returns the sections of a Markdown document that start at a heading, in document
+// order, as SplitMarkdown divides it. A section ends where the next heading of any level
+// starts; text before the first heading is not part of the outline.
+func Outline(text string) []Section {
+	return headingSections(parseMarkdown(text).sections)
+}
+
+// headingSections leaves out the section before the first heading
+func headingSections(sections []Section) []Section {
+	outline := make([]Section, 0, len(sections))
+	for _, section := range sections {
+		if section.Level > 0 {
+			outline = append(outline, section)
+		}
+	}
+	return outline
+}
+
+var (
+	atxHeadingRegex    = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	setextHeadingRegex = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	codeFenceRegex     = regexp.MustCompile("^ {0,3}(```+|~~~+)")
+)
+
+// textLine is a line of a text without its line break
+type textLine struct {
+	text       string
+	start, end int
+}
+
+// textLines returns the lines of text; end is the offset of the line break or the end of text
+func textLines(text string) []textLine {
+	lines := make([]textLine, 0)
+	for start := 0; start < len(text); {
+		end := strings.IndexByte(text[start:], '\n')
+		next := start + end + 1
+		if end < 0 {
+			end = len(text) - start
+			next = len(text)
+		}
+		line := strings.TrimSuffix(text[start:start+end], "\r")
+		lines = append(lines, textLine{line, start, start + len(line)})
+		start = next
+	}
+	return lines
+}
+
+// closesFence reports whether line closes the code block opened by fence
+func closesFence(line string, fence string) bool {
+	m := codeFenceRegex.FindStringSubmatch(line)
+	return m != nil && m[1][0] == fence[0] && len(m[1]) >= len(fence) && strings.TrimSpace(line[len(m[0]):]) == ""
+}
+
+// parseMarkdown finds the ATX and setext headings and the fenced code blocks of a Markdown text
+func parseMarkdown(text string) documentStructure {
+	var doc documentStructure
+	headings := make([]Section, 0)
+
+	lines := textLines(text)
+	fence, fenceStart := "", 0
+	for i, line := range lines {
+		if fence != "" {
+			if closesFence(line.text, fence) {
+				doc.blocks = append(doc.blocks, [2]int{fenceStart, line.end})
+				fence = ""
+			}
+			continue
+		}
+		if m := codeFenceRegex.FindStringSubmatch(line.text); m != nil {
+			fence, fenceStart = m[1], line.start
+			continue
+		}
+		if m := atxHeadingRegex.FindStringSubmatch(line.text); m != nil {
+			headings = append(headings, Section{Heading: strings.TrimSpace(m[2]), Level: len(m[1]), Start: line.start})
+			continue
+		}
+		if m := setextHeadingRegex.FindStringSubmatch(line.text); m != nil && i > 0 {
+			prev := lines[i-1]
+			if strings.TrimSpace(prev.text) == "" || atxHeadingRegex.MatchString(prev.text) {
+				continue
+			}
+			level := 1
+			if m[1][0] == '-' {
+				level = 2
+			}
+			if n := len(headings); n > 0 && headings[n-1].Start == prev.start {
+				continue
+			}
+			headings = append(headings, Section{Heading: strings.TrimSpace(prev.text), Level: level, Start: prev.start})
+		}
+	}
+	if fence != "" {
+		// an unclosed fence runs to the end of the document
+		doc.blocks = append(doc.blocks, [2]int{fenceStart, len(text)})
+	}
+
+	doc.sections = buildSections(text, headings)
+	doc.footnotes, doc.footnoteSyntax = markdownFootnotes.parse(lines), markdownFootnotes
+	return doc
+}
+
+// buildSections completes headings, which must be ordered by Start, into sections covering
+// all of text, filling in their paths and ends
+func buildSections(text string, headings []Section) []Section {
+	sections := make([]Section, 0, len(headings)+1)
+	if len(headings) == 0 || strings.TrimSpace(text[:headings[0].Start]) != "" {
+		sections = append(sections, Section{Start: 0})
+	}
+
+	stack := make([]Section, 0)
+	for _, heading := range headings {
+		for len(stack) > 0 && stack[len(stack)-1].Level >= heading.Level {
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, heading)
+		heading.Path = make([]string, len(stack))
+		for i, parent := range stack {
+			heading.Path[i] = parent.Heading
+		}
+		sections = append(sections, heading)
+	}
+
+	for i := range sections {
+		if i+1 < len(sections) {
+			sections[i].End = sections[i+1].Start
+		} else {
+			sections[i].End = len(text)
+		}
+	}
+	return sections
+}
+
+// splitStructure splits every section of doc separately and tags the chunks with the section's heading path
+func (c *TextSplitter) splitStructure(text string, doc documentStructure) []Chunk {
+	chunks := make([]Chunk, 0)
+	for _, section := range doc.sections {
+		if c.budget.error() != nil {
+			break
+		}
+		start, end := sectionBounds(text, section)
+		if end <= start {
+			continue
+		}
+		splitter := c.forSection(section)
+		blocks := make([][2]int, 0)
+		for _, block := range doc.blocks {
+			if block[0] >= start && block[1] <= end {
+				blocks = append(blocks, [2]int{block[0] - start, block[1] - start})
+			}
+		}
+
+		for _, chunk := range splitter.splitBlocks(text[start:end], blocks) {
+			chunk.Start += start
+			chunk.End += start
+			chunk.Metadata.HeadingPath = section.Path
+			chunks = append(chunks, chunk)
+		}
+	}
+	if len(doc.footnotes) > 0 {
+		doc.footnoteSyntax.attach(chunks, doc.footnotes)
+	}
+	return chunks
+}
+
+// sectionBounds returns the start and end of section in text without the blank lines around
+// it, which are left out of its chunks
+func sectionBounds(text string, section Section) (int, int) {
+	start := skipSpace(text, section.Start)
+	return start, start + len(strings.TrimRightFunc(text[start:section.End], unicode.IsSpace))
+}
+
+// WithSectionBudget sets the chunk size of every section of a structured document to the
+// result of budget, for example to give reference sections larger chunks than FAQs. A result
+// of 0 or less keeps the configured chunk size. The overlap is scaled with the chunk size.
+func WithSectionBudget(budget func(section Section) int) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.SectionBudget = budget
+	}
+}
+
+// forSection returns the splitter for section, a copy with the chunk size set by
+// WithSectionBudget if it differs from the configured one. The budget is reduced like the
+// configured chunk size, see reducedSize, but never below 1.
+func (c *TextSplitter) forSection(section Section) *TextSplitter {
+	if c.opts.SectionBudget == nil {
+		return c
+	}
+	size := c.opts.SectionBudget(section)
+	if size <= 0 {
+		return c
+	}
+	if size = c.reducedSize(size); size < 1 {
+		size = 1
+	}
+	return c.withChunkSize(size)
+}
+
+// withChunkSize returns a copy of the splitter with the chunk size set to size, which is
+// used as it is, and the overlap scaled with it
+func (c *TextSplitter) withChunkSize(size int) *TextSplitter {
+	if size == c.chunkSize {
+		return c
+	}
+	splitter := *c
+	splitter.chunkSize = size
+	splitter.overlap = c.overlap * size / c.chunkSize
+	return &splitter
+}
+
+// splitBlocks splits text like splitChunks, but keeps each of the given spans together unless
+// it exceeds the chunk size on its own
+func (c *TextSplitter) splitBlocks(text string, blocks [][2]int) []Chunk {
+	if len(blocks) == 0 {
+		return c.splitChunks(text)
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i][0] < blocks[j][0] })
+
+	// the text between blocks and the blocks themselves become the top level splits
+	ts := textSplit{splitter: "", isWhitespace: true, level: LevelParagraph}
+	ts.addAround(text, blocks)
+
+	chunks := make([]Chunk, 0)
+	_ = c.walkSplits(text, 0, ts, ts.starts, c.chunkSize, 0, func(chunk Chunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	chunks = c.fitTokens(chunks, c.chunkSize)
+	return c.fitBytes(c.repairBoundaries(text, chunks, c.chunkSize))
+}
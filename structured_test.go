@@ -0,0 +1,82 @@
+package semchunk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	text := "Intro text.\n\n# Title\n\nBody.\n\n## Part\n\n```\n# not a heading\n```\n\nSetext\n------\n\n# Next"
+	doc := parseMarkdown(text)
+
+	headings := make([]string, 0)
+	paths := make([][]string, 0)
+	for _, section := range doc.sections {
+		headings = append(headings, section.Heading)
+		paths = append(paths, section.Path)
+	}
+	assert.Equal(t, []string{"", "Title", "Part", "Setext", "Next"}, headings)
+	assert.Equal(t, [][]string{nil, {"Title"}, {"Title", "Part"}, {"Title", "Setext"}, {"Next"}}, paths)
+	assert.Equal(t, len(text), doc.sections[len(doc.sections)-1].End)
+
+	assert.Len(t, doc.blocks, 1)
+	assert.Equal(t, "```\n# not a heading\n```", text[doc.blocks[0][0]:doc.blocks[0][1]])
+}
+
+func TestOutline(t *testing.T) {
+	text := "Intro text.\n\n# Title\n\nBody.\n\n## Part\n\nMore.\n"
+	assert.Equal(t, []Section{
+		{Heading: "Title", Level: 1, Path: []string{"Title"}, Start: 13, End: 29},
+		{Heading: "Part", Level: 2, Path: []string{"Title", "Part"}, Start: 29, End: len(text)},
+	}, Outline(text))
+	assert.Empty(t, Outline("No headings here."))
+
+	outline := OrgOutline("* TODO Plan :work:\nSteps.\n** Step one\n")
+	assert.Equal(t, []string{"Plan", "Step one"}, []string{outline[0].Heading, outline[1].Heading})
+	assert.Equal(t, 2, outline[1].Level)
+}
+
+func TestSplitMarkdown(t *testing.T) {
+	text := "# Fruit\n\nApples are red.\n\n## Code\n\nSee this:\n\n```\nfor a in b:\n    eat(a)\n```\n\nDone."
+	chunks := newWordSplitter(t, 8, 0).SplitMarkdown(text)
+
+	assert.Equal(t, []string{
+		"# Fruit\n\nApples are red.",
+		"## Code\n\nSee this:",
+		"```\nfor a in b:\n    eat(a)\n```\n\nDone.",
+	}, chunkTexts(chunks))
+	assert.Equal(t, []string{"Fruit"}, chunks[0].Metadata.HeadingPath)
+	assert.Equal(t, []string{"Fruit", "Code"}, chunks[2].Metadata.HeadingPath)
+	for _, chunk := range chunks {
+		assert.Equal(t, chunk.Text, text[chunk.Start:chunk.End])
+	}
+}
+
+func TestWithSectionBudget(t *testing.T) {
+	text := "# Guide\n\none two three four five six\n\n# Appendix\n\none two three four five six"
+	splitter := newWordSplitter(t, 3, 0, WithSectionBudget(func(section Section) int {
+		if section.Heading == "Appendix" {
+			return 10
+		}
+		return 0
+	}))
+	assert.Equal(t, []string{
+		"# Guide",
+		"one two three",
+		"four five six",
+		"# Appendix\n\none two three four five six",
+	}, chunkTexts(splitter.SplitMarkdown(text)))
+
+	// the budget leaves room for the suffix like the configured chunk size does
+	splitter = newWordSplitter(t, 3, 0, WithChunkSuffix(" <eoc>"), WithSectionBudget(func(section Section) int {
+		return 8
+	}))
+	chunks, err := splitter.SplitMarkdownContext(context.Background(), text)
+	assert.NoError(t, err)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, chunk.Tokens, 8, chunk.Text)
+	}
+	assert.Equal(t, "one two three four five six <eoc>", chunks[len(chunks)-1].Text)
+}
@@ -0,0 +1,42 @@
+package semchunk
+
+// WithChunkSuffix appends suffix, such as an end-of-chunk token some embedding models and
+// rerankers expect, to the text of every chunk. The chunk size is reduced by the tokens of
+// suffix, so chunks with the suffix still fit, and Chunk.Tokens includes them. Offsets still
+// describe the chunk in the input text, without the suffix.
+func WithChunkSuffix(suffix string) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.ChunkSuffix = suffix
+	}
+}
+
+// suffixTokens returns the number of tokens of the chunk suffix
+func (c *TextSplitter) suffixTokens() int {
+	if c.opts.ChunkSuffix == "" {
+		return 0
+	}
+	return c.countTokenFunc(c.opts.ChunkSuffix)
+}
+
+// appendSuffix appends the chunk suffix to chunks and, if recount is true, counts their
+// tokens again
+func (c *TextSplitter) appendSuffix(chunks []Chunk, recount bool) {
+	if c.opts.ChunkSuffix == "" {
+		return
+	}
+	texts := make([]string, len(chunks))
+	for i := range chunks {
+		chunks[i].Text += c.opts.ChunkSuffix
+		texts[i] = chunks[i].Text
+	}
+	if !recount {
+		return
+	}
+	for i, count := range c.countTokensBatch(texts) {
+		chunks[i].Metadata.NewTokens += count - chunks[i].Tokens
+		chunks[i].Tokens = count
+	}
+}
@@ -0,0 +1,49 @@
+package semchunk
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithChunkSuffix(t *testing.T) {
+	text := "one two three four five six seven eight"
+
+	splitter := newWordSplitter(t, 4, 0, WithChunkSuffix(" </s>"))
+	assert.Equal(t, []string{"one two three </s>", "four five six </s>", "seven eight </s>"}, splitter.Split(text))
+
+	chunks := splitter.SplitChunks(text)
+	for _, chunk := range chunks {
+		assert.LessOrEqual(t, chunk.Tokens, 4)
+		assert.Equal(t, text[chunk.Start:chunk.End]+" </s>", chunk.Text)
+	}
+	assert.Equal(t, 4, chunks[0].Tokens)
+	assert.Equal(t, 4, chunks[0].Metadata.NewTokens)
+
+	var streamed []Chunk
+	assert.NoError(t, splitter.SplitTo(text, ChunkSinkFunc(func(chunk Chunk) error {
+		streamed = append(streamed, chunk)
+		return nil
+	})))
+	assert.Equal(t, chunkTexts(chunks), chunkTexts(streamed))
+	assert.Equal(t, chunks[0].Tokens, streamed[0].Tokens)
+
+	levels, err := splitter.SplitMulti(context.Background(), text, []int{8, 4})
+	assert.NoError(t, err)
+	for _, level := range levels {
+		for _, chunk := range level.Chunks {
+			assert.True(t, strings.HasSuffix(chunk.Text, " </s>"))
+			assert.False(t, strings.HasSuffix(chunk.Text, " </s> </s>"))
+			assert.LessOrEqual(t, chunk.Tokens, level.ChunkSize)
+		}
+	}
+	_, err = splitter.SplitMulti(context.Background(), text, []int{8, 1})
+	assert.Error(t, err)
+
+	assert.Equal(t, "four five six </s>", splitter.SplitAround(text, strings.Index(text, "five"), 4).Text)
+
+	_, err = NewTextSplitter(1, 0, func(string) int { return 1 }, WithChunkSuffix("</s>"))
+	assert.Error(t, err)
+}
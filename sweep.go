@@ -0,0 +1,136 @@
+package semchunk
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"sync"
+)
+
+// SweepConfig is one chunking configuration compared by Sweep
+type SweepConfig struct {
+	ChunkSize int
+	Overlap   float32
+	// Options are applied after the options of the splitter Sweep is called on
+	Options []func(*TextSplitterOption)
+}
+
+// Report describes the chunks one SweepConfig produces
+type Report struct {
+	Config SweepConfig
+	Chunks []Chunk
+	// Err is set if the configuration is invalid or splitting failed
+	Err          error
+	Count        int
+	Tokens       int
+	MinTokens    int
+	MaxTokens    int
+	MeanTokens   float64
+	StdDevTokens float64
+	// MeanQuality is the mean ChunkQuality of the chunks
+	MeanQuality float64
+}
+
+// Sweep splits text under every configuration concurrently and reports statistics for each,
+// in the order of configs. The configurations share the token counter of the splitter through
+// a cache, so pieces produced by several of them, which is most of the pieces at the coarser
+// levels, are counted only once.
+func (c *TextSplitter) Sweep(text string, configs []SweepConfig) []Report {
+	cache := newTokenCache(c, 0)
+	base := *c.opts
+
+	reports := make([]Report, len(configs))
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(configs) {
+		workers = len(configs)
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				reports[i] = c.sweepOne(text, configs[i], base, cache)
+			}
+		}()
+	}
+	for i := range configs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return reports
+}
+
+// sweepOne splits text under one configuration
+func (c *TextSplitter) sweepOne(text string, cfg SweepConfig, base TextSplitterOption, cache *tokenCache) Report {
+	report := Report{Config: cfg}
+	opts := append([]func(*TextSplitterOption){func(opts *TextSplitterOption) { *opts = base.clone() }}, cfg.Options...)
+	ts, err := NewTextSplitter(cfg.ChunkSize, cfg.Overlap, cache.CountTokens, opts...)
+	if err != nil {
+		report.Err = err
+		return report
+	}
+	if c.batchCounter != nil {
+		ts.batchCounter = cache
+	}
+	ts.encoder = c.encoder
+
+	report.Chunks, report.Err = ts.SplitChunksContext(context.Background(), text)
+	report.Count = len(report.Chunks)
+	if report.Count == 0 {
+		return report
+	}
+	report.MinTokens = math.MaxInt
+	quality := 0.0
+	for _, chunk := range report.Chunks {
+		report.Tokens += chunk.Tokens
+		if chunk.Tokens < report.MinTokens {
+			report.MinTokens = chunk.Tokens
+		}
+		if chunk.Tokens > report.MaxTokens {
+			report.MaxTokens = chunk.Tokens
+		}
+		quality += chunk.Metadata.Quality
+	}
+	report.MeanTokens = float64(report.Tokens) / float64(report.Count)
+	report.MeanQuality = quality / float64(report.Count)
+	variance := 0.0
+	for _, chunk := range report.Chunks {
+		d := float64(chunk.Tokens) - report.MeanTokens
+		variance += d * d
+	}
+	report.StdDevTokens = math.Sqrt(variance / float64(report.Count))
+	return report
+}
+
+// clone returns a copy of o that shares no slices or option structs with it, so options
+// appending to or changing them, such as WithPreserveURLs, leave o as it is
+func (o TextSplitterOption) clone() TextSplitterOption {
+	o.PreservePatterns = cloneSlice(o.PreservePatterns)
+	o.HardBoundaries = cloneSlice(o.HardBoundaries)
+	o.PageMarkers = cloneSlice(o.PageMarkers)
+	o.SentenceTerminators = cloneSlice(o.SentenceTerminators)
+	o.ClauseSeparators = cloneSlice(o.ClauseSeparators)
+	o.PIICategories = cloneSlice(o.PIICategories)
+	o.Denylist = cloneSlice(o.Denylist)
+	if o.LangChain != nil {
+		langChain := *o.LangChain
+		langChain.Separators = cloneSlice(langChain.Separators)
+		o.LangChain = &langChain
+	}
+	if o.NewlineTiers != nil {
+		tiers := *o.NewlineTiers
+		o.NewlineTiers = &tiers
+	}
+	return o
+}
+
+// cloneSlice returns a copy of s, nil if s is nil
+func cloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(s)), s...)
+}
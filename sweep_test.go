@@ -0,0 +1,76 @@
+package semchunk
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSweep(t *testing.T) {
+	var calls int64
+	countWords := func(text string) int {
+		atomic.AddInt64(&calls, 1)
+		return len(strings.Fields(text))
+	}
+	splitter, err := NewTextSplitter(100, 0, countWords)
+	assert.NoError(t, err)
+
+	text := "One two three. Four five six. Seven eight nine ten."
+	reports := splitter.Sweep(text, []SweepConfig{
+		{ChunkSize: 4},
+		{ChunkSize: 100},
+		{ChunkSize: 4, Overlap: 2},
+		{ChunkSize: 6, Options: []func(*TextSplitterOption){WithRelativeOverlap(0.5)}},
+	})
+	assert.Len(t, reports, 4)
+
+	assert.NoError(t, reports[0].Err)
+	assert.Equal(t, []string{"One two three.", "Four five six.", "Seven eight nine ten."}, chunkTexts(reports[0].Chunks))
+	assert.Equal(t, 3, reports[0].MinTokens)
+	assert.Equal(t, 4, reports[0].MaxTokens)
+	assert.Equal(t, 10, reports[0].Tokens)
+	assert.InDelta(t, 10.0/3, reports[0].MeanTokens, 1e-9)
+	assert.InDelta(t, 0.4714, reports[0].StdDevTokens, 1e-4)
+
+	assert.Equal(t, 1, reports[1].Count)
+	assert.Equal(t, reports[0].Chunks, splitter.mustSplit(t, 4, text))
+	assert.ErrorContains(t, reports[2].Err, "overlap must be between 0 and 1")
+	assert.NoError(t, reports[3].Err)
+}
+
+// mustSplit splits text with a copy of the splitter using chunkSize
+func (c *TextSplitter) mustSplit(t *testing.T, chunkSize int, text string) []Chunk {
+	ts, err := NewTextSplitter(chunkSize, 0, c.countTokenFunc)
+	assert.NoError(t, err)
+	chunks, err := ts.SplitChunksContext(context.Background(), text)
+	assert.NoError(t, err)
+	return chunks
+}
+
+func TestSweepOptionsNotShared(t *testing.T) {
+	// three patterns leave room for a fourth in the backing array the configs append to
+	splitter := newWordSplitter(t, 100, 0, WithPreservePatterns("x", "y", "z"))
+	assert.Less(t, len(splitter.opts.PreservePatterns), cap(splitter.opts.PreservePatterns))
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	text := "one two three four five six"
+	patterns := []string{"two three", "four five"}
+	expected := make([][]string, len(patterns))
+	for i, pattern := range patterns {
+		expected[i] = newWordSplitter(t, 2, 0, WithPreservePatterns("x", "y", "z", pattern)).Split(text)
+	}
+	assert.NotEqual(t, expected[0], expected[1])
+
+	configs := make([]SweepConfig, 0, 40)
+	for i := 0; i < 40; i++ {
+		configs = append(configs, SweepConfig{ChunkSize: 2, Options: []func(*TextSplitterOption){WithPreservePatterns(patterns[i%2])}})
+	}
+	for i, report := range splitter.Sweep(text, configs) {
+		assert.Equal(t, expected[i%2], chunkTexts(report.Chunks))
+	}
+	assert.Len(t, splitter.opts.PreservePatterns, 3)
+}
@@ -0,0 +1,166 @@
+package semchunk
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// WithNumericTables detects whitespace-aligned numeric tables in plain text, such as
+// financial statements and reports, and splits them between rows only. Rows are never split
+// into cells; a row larger than the chunk size is kept whole and flagged with
+// Metadata.Oversized. If repeatHeader is true, the header row detected above a table is
+// prepended to every chunk of the table but the first and stored in Metadata.ContextPrefix;
+// such chunks may exceed the chunk size by the size of the header.
+func WithNumericTables(repeatHeader bool) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.NumericTables = true
+		opts.RepeatTableHeader = repeatHeader
+	}
+}
+
+// minTableRows is the number of consecutive numeric rows that make a table
+const minTableRows = 2
+
+var (
+	// tableCellSeparator separates the cells of a row: tabs or runs of two or more spaces
+	tableCellSeparator = regexp.MustCompile(`\t+| {2,}`)
+	// numericCell matches numbers, amounts and percentages, negative ones in parentheses
+	numericCell = regexp.MustCompile(`^[-+]?[$€£¥]?\(?[-+]?\d[\d,.']*\)?%?$`)
+	// yearCell matches years, which label the columns of a header row rather than being data
+	yearCell = regexp.MustCompile(`^(?:19|20)\d\d$`)
+)
+
+// numericTable is a table found by findNumericTables
+type numericTable struct {
+	// start and end are the offsets of the table, including its header row
+	start, end int
+	// rowsStart is the offset of the first row after the header
+	rowsStart int
+	// header is the header row, empty if none was found
+	header string
+}
+
+// tableCells returns the cells of a line, nil if it has fewer than two
+func tableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil
+	}
+	cells := tableCellSeparator.Split(line, -1)
+	if len(cells) < 2 {
+		return nil
+	}
+	return cells
+}
+
+// isNumericRow reports whether at least half of the cells are numeric
+func isNumericRow(cells []string) bool {
+	numeric := 0
+	for _, cell := range cells {
+		if numericCell.MatchString(cell) {
+			numeric++
+		}
+	}
+	return len(cells) >= 2 && 2*numeric >= len(cells)
+}
+
+// isHeaderRow reports whether the cells are labels or years only
+func isHeaderRow(cells []string) bool {
+	for _, cell := range cells {
+		if numericCell.MatchString(cell) && !yearCell.MatchString(cell) {
+			return false
+		}
+	}
+	return len(cells) >= 2
+}
+
+// findNumericTables returns the runs of at least minTableRows consecutive numeric rows in
+// text. The header of a table is its first row if that row only holds labels and years, as in
+// "Item  2022  2023", or else the line of cells directly above it.
+func findNumericTables(text string) []numericTable {
+	tables := make([]numericTable, 0)
+	lines := textLines(text)
+	for i := 0; i < len(lines); {
+		if !isNumericRow(tableCells(lines[i].text)) {
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(lines) && isNumericRow(tableCells(lines[j].text)) {
+			j++
+		}
+		if j-i >= minTableRows {
+			table := numericTable{start: lines[i].start, end: lines[j-1].end, rowsStart: lines[i].start}
+			if j-i > minTableRows && isHeaderRow(tableCells(lines[i].text)) {
+				table.rowsStart = lines[i+1].start
+				table.header = strings.TrimSpace(lines[i].text)
+			} else if i > 0 && tableCells(lines[i-1].text) != nil {
+				table.start = lines[i-1].start
+				table.header = strings.TrimSpace(lines[i-1].text)
+			}
+			tables = append(tables, table)
+		}
+		i = j
+	}
+	return tables
+}
+
+// splitTables splits text around the numeric tables in it or, if text is a single table,
+// between its rows, which are kept whole
+func splitTables(text string) (textSplit, bool) {
+	tables := findNumericTables(text)
+	if len(tables) == 0 {
+		return textSplit{}, false
+	}
+	if len(tables) == 1 && strings.TrimSpace(text[:tables[0].start]) == "" && strings.TrimSpace(text[tables[0].end:]) == "" {
+		ts := textSplit{splitter: "\n", isWhitespace: true, level: LevelLine, atomic: true}
+		for _, line := range textLines(text) {
+			if row := strings.TrimRightFunc(line.text, unicode.IsSpace); strings.TrimSpace(row) != "" {
+				ts.splits = append(ts.splits, row)
+				ts.starts = append(ts.starts, line.start)
+			}
+		}
+		return ts, len(ts.splits) > 1
+	}
+
+	ts := textSplit{splitter: "", isWhitespace: true, level: LevelParagraph, separate: true}
+	spans := make([][2]int, len(tables))
+	for i, table := range tables {
+		spans[i] = [2]int{table.start, table.end}
+	}
+	ts.addAround(text, spans)
+	return ts, true
+}
+
+// headedTables returns the numeric tables of text with a header row if headers are repeated,
+// see WithNumericTables
+func (c *TextSplitter) headedTables(text string) []numericTable {
+	if !c.opts.NumericTables || !c.opts.RepeatTableHeader {
+		return nil
+	}
+	tables := make([]numericTable, 0)
+	for _, table := range findNumericTables(text) {
+		if table.header != "" {
+			tables = append(tables, table)
+		}
+	}
+	return tables
+}
+
+// repeatTableHeaders prepends the header row of tables to the chunks starting inside a table
+// after its header. Their tokens are counted later, when the chunks are annotated.
+func repeatTableHeaders(chunks []Chunk, tables []numericTable) {
+	for i, chunk := range chunks {
+		for _, table := range tables {
+			if chunk.Start >= table.rowsStart && chunk.Start < table.end {
+				chunks[i].Text = table.header + "\n" + chunk.Text
+				addContextPrefix(&chunks[i], table.header)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package semchunk
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testTable = `Item         2022      2023
+Revenue      1,200     1,350
+Costs        (800)     (870)
+Margin       33.3%     35.6%
+Headcount    12        14`
+
+func TestFindNumericTables(t *testing.T) {
+	text := "Results for the year.\n\n" + testTable + "\n\nSee the notes below."
+	tables := findNumericTables(text)
+	assert.Equal(t, 1, len(tables))
+	assert.Equal(t, "Item         2022      2023", tables[0].header)
+	assert.Equal(t, testTable, text[tables[0].start:tables[0].end])
+	assert.Equal(t, strings.Index(text, "Revenue"), tables[0].rowsStart)
+
+	tables = findNumericTables("Name    Value    Share\nA       10       50%\nB       10       50%")
+	assert.Equal(t, 1, len(tables))
+	assert.Equal(t, "Name    Value    Share", tables[0].header)
+	assert.Equal(t, 0, tables[0].start)
+
+	assert.Empty(t, findNumericTables("Plain prose  with two spaces.\nAnd more  of it."))
+	assert.Empty(t, findNumericTables("Total    12\nJust a line."))
+}
+
+func TestWithNumericTables(t *testing.T) {
+	text := "Results for the year.\n\n" + testTable + "\n\nSee the notes below."
+
+	// without the table tier, rows larger than the chunk size are scattered
+	plain := newWordSplitter(t, 2, 0)
+	assert.NotContains(t, plain.Split(testTable), "Revenue      1,200     1,350")
+
+	splitter := newWordSplitter(t, 7, 0, WithNumericTables(false))
+	assert.Equal(t, []string{
+		"Results for the year.",
+		"Item         2022      2023\nRevenue      1,200     1,350",
+		"Costs        (800)     (870)\nMargin       33.3%     35.6%",
+		"Headcount    12        14",
+		"See the notes below.",
+	}, splitter.Split(text))
+
+	// rows larger than the chunk size are kept whole
+	narrow := newWordSplitter(t, 2, 0, WithNumericTables(false))
+	chunks := narrow.SplitChunks(testTable)
+	assert.Equal(t, strings.Split(testTable, "\n"), chunkTexts(chunks))
+	assert.True(t, chunks[1].Metadata.Oversized)
+
+	repeating := newWordSplitter(t, 7, 0, WithNumericTables(true))
+	chunks = repeating.SplitChunks(text)
+	assert.Equal(t, []string{
+		"Results for the year.",
+		"Item         2022      2023\nRevenue      1,200     1,350",
+		"Item         2022      2023\nCosts        (800)     (870)\nMargin       33.3%     35.6%",
+		"Item         2022      2023\nHeadcount    12        14",
+		"See the notes below.",
+	}, chunkTexts(chunks))
+	assert.Equal(t, "", chunks[1].Metadata.ContextPrefix)
+	assert.Equal(t, "Item         2022      2023", chunks[2].Metadata.ContextPrefix)
+	assert.Equal(t, strings.Index(text, "Costs"), chunks[2].Start)
+	assert.Equal(t, len(strings.Fields(chunks[2].Text)), chunks[2].Tokens)
+
+	// a generated context situates the chunk before the table header
+	generating := newWordSplitter(t, 7, 0, WithNumericTables(true), WithContextGenerator(func(context.Context, ContextRequest) (string, error) {
+		return "Annual report.", nil
+	}, false))
+	chunks, err := generating.SplitDocument(context.Background(), Document{Text: text})
+	assert.NoError(t, err)
+	assert.Equal(t, "Annual report.\n\nItem         2022      2023", chunks[2].Metadata.ContextPrefix)
+	assert.Equal(t, "Annual report.", chunks[1].Metadata.ContextPrefix)
+
+	var streamed []Chunk
+	assert.NoError(t, repeating.SplitTo(text, ChunkSinkFunc(func(chunk Chunk) error {
+		streamed = append(streamed, chunk)
+		return nil
+	})))
+	assert.Equal(t, chunkTexts(chunks), chunkTexts(streamed))
+}
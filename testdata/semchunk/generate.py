@@ -0,0 +1,58 @@
+"""Writes fixtures.json, the chunks expected from WithSemchunkCompat, with the semchunk
+library itself, pinned to the version WithSemchunkCompat follows:
+
+    pip install semchunk==3.0.0 && python3 generate.py
+
+The generator field of fixtures.json records the version that produced the fixtures.
+"""
+
+import json
+
+import semchunk
+from importlib.metadata import version
+
+VERSION = "3.0.0"
+
+if version("semchunk") != VERSION:
+    raise SystemExit(f"semchunk {version('semchunk')} is installed, the fixtures need semchunk {VERSION}")
+
+generator = "semchunk " + VERSION
+
+
+def chunk(text, chunk_size, counter):
+    return semchunk.chunk(text, chunk_size, counter, memoize=False, offsets=True)
+
+
+COUNTERS = {
+    "words": lambda text: len(text.split()),
+    "chars": len,
+}
+
+TEXTS = [
+    "The quick brown fox jumps over the lazy dog. It barked! Did the fox care? Not at all; it ran away, quickly.",
+    "First paragraph, with a few words in it.\n\nSecond paragraph: longer, with clauses (and brackets) and more words.\nA line.\r\nAnother line.",
+    "Tabs\tseparate\tthese\twords.\tAnd\tthese\ttoo.",
+    "字符串分割测试。这是第二句话，包含逗号！第三句话？还有更多的中文文本用于测试分割。",
+    "path/to/some-file.txt&other=value;key:value,more-stuff",
+    "No punctuation or spaces here",
+    "Mixed   spacing  between words.  Two spaces after sentences.  Another one here.",
+    "A sentence ending with an ellipsis… and then “quoted text” follows — with a dash.",
+]
+
+cases = []
+for text in TEXTS:
+    for counter in ("words", "chars"):
+        sizes = (3, 6, 12) if counter == "words" else (10, 25, 60)
+        for size in sizes:
+            chunks, offsets = chunk(text, size, COUNTERS[counter])
+            cases.append({
+                "text": text,
+                "chunk_size": size,
+                "counter": counter,
+                "chunks": list(chunks),
+                "offsets": [list(o) for o in offsets],
+            })
+
+with open("fixtures.json", "w", encoding="utf-8") as f:
+    json.dump({"generator": generator, "cases": cases}, f, ensure_ascii=False, indent=1)
+    f.write("\n")
@@ -0,0 +1,85 @@
+//go:build ignore
+
+// generate writes the fixtures of TestAlgorithmV1Fixtures: the chunks of the first release,
+// 173b76e, for pseudo-random texts. AlgorithmV1 must keep reproducing them. Run it against a
+// checkout of that release:
+//
+//	git worktree add /tmp/v1 173b76e
+//	cp testdata/v1/generate.go /tmp/v1/
+//	(cd /tmp/v1 && go run generate.go) > testdata/v1/fixtures.json
+//	git worktree remove --force /tmp/v1
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	semchunk "github.com/sanbaiw/semtxtsplitter"
+)
+
+type fixture struct {
+	Text      string `json:"text"`
+	ChunkSize int    `json:"chunk_size"`
+	Counter   string `json:"counter"`
+	// Overlap is an overlap in tokens if it is a whole number, or else a fraction
+	Overlap float64  `json:"overlap"`
+	Chunks  []string `json:"chunks"`
+}
+
+var pieces = []string{
+	"the", "quick", "brown", "fox", "jumps", "over", "lazy", "dog", "Go", "splitter",
+	"1.", "2)", "e.g.", "Wait...", "what?!", "Yes!", "no.", "end.", "(aside)", "\"quoted\"",
+	"key:", "value;", "first,", "-", "—", "…", "https://example.com/a?b=c",
+	"今天天气很好。", "我们去公园，", "散步吧！", "café", "naïve",
+	" ", " ", " ", " ", "\n", "\n\n", "\t", " ",
+}
+
+var counters = map[string]func(string) int{
+	"words": func(text string) int { return len(strings.Fields(text)) },
+	"chars": utf8.RuneCountInString,
+}
+
+func main() {
+	rng := rand.New(rand.NewSource(1))
+	fixtures := make([]fixture, 0)
+	for i := 0; i < 120; i++ {
+		var b strings.Builder
+		for n := 5 + rng.Intn(60); n > 0; n-- {
+			b.WriteString(pieces[rng.Intn(len(pieces))])
+			if rng.Intn(3) > 0 {
+				b.WriteString(" ")
+			}
+		}
+		text := b.String()
+		counter := "words"
+		chunkSize := 3 + rng.Intn(10)
+		if i%2 == 1 {
+			counter = "chars"
+			chunkSize = 10 + rng.Intn(40)
+		}
+
+		var splitter *semchunk.TextSplitter
+		var err error
+		var overlap float64
+		if i%3 == 0 {
+			overlap = float64(float32(rng.Intn(5)) / 10)
+			splitter, err = semchunk.NewTextSplitter(chunkSize, float32(overlap), counters[counter])
+		} else {
+			overlap = float64(rng.Intn(chunkSize))
+			splitter, err = semchunk.NewTextSplitter(chunkSize, int(overlap), counters[counter])
+		}
+		if err != nil {
+			panic(err)
+		}
+		fixtures = append(fixtures, fixture{Text: text, ChunkSize: chunkSize, Counter: counter, Overlap: overlap, Chunks: splitter.Split(text)})
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(map[string]any{"generator": "semtxtsplitter 173b76e", "cases": fixtures}); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,120 @@
+package semchunk
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TOCMode decides what happens to chunks that look like a table of contents
+type TOCMode int
+
+const (
+	// TOCKeep keeps table of contents chunks like any other chunk
+	TOCKeep TOCMode = iota
+	// TOCMark sets Metadata.TOC on table of contents chunks, so a retriever can down-weight them
+	TOCMark
+	// TOCDrop leaves table of contents chunks out of the output
+	TOCDrop
+)
+
+// tOCModeNames are the names TOCMode values are serialized as, in the order of the values
+var tOCModeNames = []string{"keep", "mark", "drop"}
+
+func (m TOCMode) MarshalText() ([]byte, error) {
+	return marshalEnum(m, tOCModeNames)
+}
+
+func (m *TOCMode) UnmarshalText(text []byte) error {
+	return unmarshalEnum(text, tOCModeNames, m)
+}
+
+// WithTOCDetection detects chunks that look like a table of contents, an index or a list of
+// links, which otherwise dominate retrieval over long manuals with chunks of no value, and
+// marks or drops them according to mode
+func WithTOCDetection(mode TOCMode) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.TOC = mode
+	}
+}
+
+const (
+	// minTOCLines is the number of entries a table of contents has at least
+	minTOCLines = 3
+	// tocRatio is the fraction of the lines of a table of contents that are entries
+	tocRatio = 0.6
+)
+
+// tocPage is a page number: arabic, or a lower case roman numeral up to xxxix as used for the
+// front matter
+const tocPage = `(?:\d{1,4}|x{0,3}(?:ix|iv|v?i{0,3}))`
+
+var (
+	// tocLeaderRegex matches titles followed by dot leaders and a page number
+	tocLeaderRegex = regexp.MustCompile(`^.{1,160}?[ \t]*(?:\.[ \t]*){2,}` + tocPage + `$`)
+	// tocColumnRegex matches titles followed by a gap of two spaces or a tab and a page number,
+	// which are entries if the gap is a tab or the page numbers are aligned
+	tocColumnRegex = regexp.MustCompile(`^.{1,160}?([ \t]{2,}|\t)` + tocPage + `$`)
+	tocLinkRegex   = regexp.MustCompile(
+		// list items or lines that are only links
+		`^(?:[-*+]|\d+[.)])?[ \t]*\[[^\]]+\]\([^)]+\)$` +
+			// lines that are only a link target
+			`|^(?:[-*+][ \t]*)?https?://\S+$`)
+)
+
+// IsTOC reports whether text looks like a table of contents: most of its lines, and at least
+// three, are entries. Entries are titles followed by dot leaders and a page number, titles
+// followed by a tab or a right-aligned column of page numbers, or links.
+func IsTOC(text string) bool {
+	lines, entries := 0, 0
+	// columns counts the page number lines by the column they end at
+	columns := make(map[int]int)
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		lines++
+		switch {
+		case tocLeaderRegex.MatchString(trimmed) || tocLinkRegex.MatchString(trimmed):
+			entries++
+		default:
+			if m := tocColumnRegex.FindStringSubmatch(trimmed); m == nil {
+				continue
+			} else if strings.Contains(m[1], "\t") {
+				entries++
+			} else {
+				columns[utf8.RuneCountInString(line)]++
+			}
+		}
+	}
+	column := 0
+	for _, n := range columns {
+		if n > column {
+			column = n
+		}
+	}
+	if column >= 2 {
+		entries += column
+	}
+	return entries >= minTOCLines && float64(entries) >= tocRatio*float64(lines)
+}
+
+// dropTOC leaves out the chunks that look like a table of contents if TOCDrop is configured
+func (c *TextSplitter) dropTOC(chunks []Chunk) []Chunk {
+	if c.opts.TOC != TOCDrop {
+		return chunks
+	}
+	kept := chunks[:0]
+	for _, chunk := range chunks {
+		if !IsTOC(chunk.Text) {
+			kept = append(kept, chunk)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,52 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTOC(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want bool
+	}{
+		{"dot leaders", "Contents\n1 Introduction ........ 1\n2 Installation ....... 5\n3 Usage .......... 12", true},
+		{"page numbers", "Preface          ix\nGetting started   1\nAdvanced topics  37", true},
+		{"tabs", "Preface\tix\nGetting started\t1\nAdvanced topics\t37", true},
+		{"unaligned page numbers", "Preface  ix\nGetting started  1\nAdvanced topics  37", false},
+		{"roman words", "He spoke of Rome and of mix\nThe meal came in parts and so did\nWe sat down, then we had vi", false},
+		{"years", "The company was founded in 1998\nIts first product shipped in 2001\nIt was sold in 2015", false},
+		{"links", "- [Install](install.md)\n- [Configure](config.md)\n- [Deploy](deploy.md)", true},
+		{"prose", "The tool is installed in 3 steps. First download it.\nThen run it 2 times.", false},
+		{"too short", "Introduction ..... 1\nUsage ..... 4", false},
+		{"mostly prose", "Intro ... 1\nUsage ... 2\nAPI ... 3\nThis manual describes the tool.\nIt covers all commands.\nRead it in order.", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsTOC(tt.text))
+		})
+	}
+}
+
+func TestWithTOCDetection(t *testing.T) {
+	text := "1 Introduction ..... 1\n2 Installation ..... 5\n3 Usage ..... 12\n\nThe tool splits text into chunks for retrieval."
+
+	chunks := newWordSplitter(t, 12, 0, WithTOCDetection(TOCMark)).SplitChunks(text)
+	assert.Len(t, chunks, 2)
+	assert.True(t, chunks[0].Metadata.TOC)
+	assert.False(t, chunks[1].Metadata.TOC)
+
+	dropping := newWordSplitter(t, 12, 0, WithTOCDetection(TOCDrop))
+	chunks = dropping.SplitChunks(text)
+	assert.Equal(t, []string{"The tool splits text into chunks for retrieval."}, chunkTexts(chunks))
+	assert.Equal(t, 0, chunks[0].Index)
+
+	var streamed []string
+	assert.NoError(t, dropping.SplitTo(text, ChunkSinkFunc(func(chunk Chunk) error {
+		streamed = append(streamed, chunk.Text)
+		return nil
+	})))
+	assert.Equal(t, chunkTexts(chunks), streamed)
+}
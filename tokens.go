@@ -0,0 +1,84 @@
+package semchunk
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+// ErrNoEncoder is returned by the token range functions for splitters whose counter does not
+// implement TokenEncoder
+var ErrNoEncoder = errors.New("token counter does not implement TokenEncoder")
+
+// SplitTokenRanges splits text like SplitChunks and tokenizes it once as a whole. It returns
+// the tokens of text together with the chunks, whose Metadata.TokenStart and
+// Metadata.TokenEnd hold the [start, end) range of the tokens covering them, for late chunking,
+// where the whole document is embedded once and the token embeddings are pooled per chunk.
+// Tokens that straddle a chunk boundary belong to both chunks. It needs a counter that
+// implements TokenEncoder, see NewTextSplitterWithCounter.
+func (c *TextSplitter) SplitTokenRanges(ctx context.Context, text string) ([]int, []Chunk, error) {
+	if c.encoder == nil {
+		return nil, nil, ErrNoEncoder
+	}
+	chunks, err := c.SplitChunksContext(ctx, text)
+	if err != nil {
+		return nil, nil, err
+	}
+	tokens := c.encoder.Encode(text)
+	offsets := c.tokenOffsets(tokens, len(text))
+	for i, chunk := range chunks {
+		chunks[i].Metadata.TokenStart, chunks[i].Metadata.TokenEnd = tokenRange(offsets, chunk.Start, chunk.End)
+	}
+	return tokens, chunks, nil
+}
+
+// tokenOffsets returns the byte offset in the text of every token and, last, the length of
+// the text. Tokens are decoded one by one; tokenizers whose tokens do not decode on their
+// own, such as byte-level tokens splitting a character, are decoded as growing prefixes.
+func (c *TextSplitter) tokenOffsets(tokens []int, length int) []int {
+	offsets := make([]int, len(tokens)+1)
+	for i, token := range tokens {
+		offsets[i+1] = offsets[i] + len(c.encoder.Decode([]int{token}))
+	}
+	if offsets[len(tokens)] == length {
+		return offsets
+	}
+	for i := range tokens {
+		offsets[i+1] = len(c.encoder.Decode(tokens[:i+1]))
+	}
+	return offsets
+}
+
+// tokenRange returns the [start, end) range of the tokens overlapping the bytes [from, to)
+func tokenRange(offsets []int, from, to int) (int, int) {
+	n := len(offsets) - 1
+	// the first token ending after from
+	start := sort.Search(n, func(i int) bool { return offsets[i+1] > from })
+	// the first token starting at or after to
+	end := sort.Search(n, func(i int) bool { return offsets[i] >= to })
+	if end < start {
+		end = start
+	}
+	return start, end
+}
+
+// Span is the [Start, End) range of the tokens of a chunk in the token sequence of its document
+type Span struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// TokenSpans returns the token span of every chunk of text, in chunk order, for
+// late-interaction indexing that pools token embeddings of the whole document per chunk.
+// It returns nil if the counter does not implement TokenEncoder, see SplitTokenRanges.
+func (c *TextSplitter) TokenSpans(text string) []Span {
+	_, chunks, err := c.SplitTokenRanges(context.Background(), text)
+	if err != nil {
+		return nil
+	}
+	spans := make([]Span, len(chunks))
+	for i, chunk := range chunks {
+		spans[i] = Span{Start: chunk.Metadata.TokenStart, End: chunk.Metadata.TokenEnd}
+	}
+	return spans
+}
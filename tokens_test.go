@@ -0,0 +1,92 @@
+package semchunk
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var wordTokenRegex = regexp.MustCompile(`\s*\S+|\s+`)
+
+// wordEncoder makes a token of every word with the whitespace before it
+type wordEncoder struct {
+	vocabulary map[string]int
+	words      []string
+}
+
+func newWordEncoder() *wordEncoder {
+	return &wordEncoder{vocabulary: make(map[string]int)}
+}
+
+func (e *wordEncoder) CountTokens(text string) int { return len(e.Encode(text)) }
+
+func (e *wordEncoder) Encode(text string) []int {
+	tokens := make([]int, 0)
+	for _, word := range wordTokenRegex.FindAllString(text, -1) {
+		id, ok := e.vocabulary[word]
+		if !ok {
+			id = len(e.words)
+			e.vocabulary[word] = id
+			e.words = append(e.words, word)
+		}
+		tokens = append(tokens, id)
+	}
+	return tokens
+}
+
+func (e *wordEncoder) Decode(tokens []int) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteString(e.words[token])
+	}
+	return b.String()
+}
+
+func TestSplitTokenRanges(t *testing.T) {
+	text := "One two three. Four five six. Seven eight."
+	splitter, err := NewTextSplitterWithCounter(4, 0, newWordEncoder())
+	assert.NoError(t, err)
+
+	tokens, chunks, err := splitter.SplitTokenRanges(context.Background(), text)
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 8)
+	assert.Equal(t, []string{"One two three.", "Four five six.", "Seven eight."}, chunkTexts(chunks))
+	assert.Equal(t, [][2]int{{0, 3}, {3, 6}, {6, 8}}, [][2]int{
+		{chunks[0].Metadata.TokenStart, chunks[0].Metadata.TokenEnd},
+		{chunks[1].Metadata.TokenStart, chunks[1].Metadata.TokenEnd},
+		{chunks[2].Metadata.TokenStart, chunks[2].Metadata.TokenEnd},
+	})
+
+	_, _, err = newWordSplitter(t, 4, 0).SplitTokenRanges(context.Background(), text)
+	assert.ErrorIs(t, err, ErrNoEncoder)
+}
+
+func TestTokenSpans(t *testing.T) {
+	splitter, err := NewTextSplitterWithCounter(4, 0, newWordEncoder())
+	assert.NoError(t, err)
+	assert.Equal(t, []Span{{0, 3}, {3, 6}, {6, 8}}, splitter.TokenSpans("One two three. Four five six. Seven eight."))
+
+	assert.Nil(t, newWordSplitter(t, 4, 0).TokenSpans("One two three."))
+}
+
+func TestTokenRange(t *testing.T) {
+	// tokens "ab", "cd", "ef"
+	offsets := []int{0, 2, 4, 6}
+	tests := []struct {
+		from, to   int
+		start, end int
+	}{
+		{0, 6, 0, 3},
+		{2, 4, 1, 2},
+		// tokens straddling the boundaries are included
+		{1, 5, 0, 3},
+		{2, 2, 1, 1},
+	}
+	for _, tt := range tests {
+		start, end := tokenRange(offsets, tt.from, tt.to)
+		assert.Equal(t, [2]int{tt.start, tt.end}, [2]int{start, end}, "%d-%d", tt.from, tt.to)
+	}
+}
@@ -0,0 +1,140 @@
+package semchunk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Transcript is a speech transcript with timestamps, as written by WhisperX
+type Transcript struct {
+	Segments []TranscriptSegment `json:"segments"`
+}
+
+// TranscriptSegment is a stretch of speech of one speaker. Times are in seconds.
+type TranscriptSegment struct {
+	Start   float64          `json:"start"`
+	End     float64          `json:"end"`
+	Text    string           `json:"text"`
+	Speaker string           `json:"speaker,omitempty"`
+	Words   []TranscriptWord `json:"words,omitempty"`
+}
+
+// TranscriptWord is a word of a segment. Words that could not be aligned, such as numbers,
+// have no times.
+type TranscriptWord struct {
+	Word    string   `json:"word"`
+	Start   *float64 `json:"start,omitempty"`
+	End     *float64 `json:"end,omitempty"`
+	Speaker string   `json:"speaker,omitempty"`
+}
+
+// ReadWhisperX reads a transcript in the JSON format of WhisperX
+func ReadWhisperX(r io.Reader) (Transcript, error) {
+	var transcript Transcript
+	err := json.NewDecoder(r).Decode(&transcript)
+	return transcript, err
+}
+
+// splitWhisperX reads a WhisperX transcript from text and splits it with SplitTranscript.
+// Chunk offsets refer to the text of the transcript built by SplitTranscript.
+func (c *TextSplitter) splitWhisperX(ctx context.Context, text string) ([]Chunk, error) {
+	transcript, err := ReadWhisperX(strings.NewReader(text))
+	if err != nil {
+		return nil, err
+	}
+	return c.SplitTranscript(ctx, transcript)
+}
+
+// timedSpan is a stretch of a transcript's text spoken from start to end seconds
+type timedSpan struct {
+	from, to   int
+	start, end float64
+}
+
+// SplitTranscript splits a transcript into chunks carrying the time range they cover in
+// Metadata.StartTime and Metadata.EndTime, and their speakers in Metadata.Speakers.
+// Consecutive segments of a speaker form a turn, written as one paragraph prefixed with the
+// speaker, and turns are only split if they exceed the chunk size on their own. Times come
+// from the words of the segments where available, otherwise from the segments.
+func (c *TextSplitter) SplitTranscript(ctx context.Context, transcript Transcript) ([]Chunk, error) {
+	var text strings.Builder
+	spans := make([]timedSpan, 0)
+	// turns are the spans of the turns in the text, speakers their speakers
+	turns, speakers := make([][2]int, 0), make([]string, 0)
+	for _, segment := range transcript.Segments {
+		content := strings.TrimSpace(segment.Text)
+		if content == "" {
+			continue
+		}
+		if len(turns) == 0 || segment.Speaker != speakers[len(speakers)-1] {
+			if len(turns) > 0 {
+				turns[len(turns)-1][1] = text.Len()
+				text.WriteString("\n\n")
+			}
+			turns = append(turns, [2]int{text.Len(), 0})
+			speakers = append(speakers, segment.Speaker)
+			if segment.Speaker != "" {
+				text.WriteString(segment.Speaker + ": ")
+			}
+		} else {
+			text.WriteString(" ")
+		}
+
+		offset := text.Len()
+		text.WriteString(content)
+		spans = append(spans, segmentSpans(content, offset, segment)...)
+	}
+	if len(turns) > 0 {
+		turns[len(turns)-1][1] = text.Len()
+	}
+
+	chunks, err := c.splitParsed(ctx, text.String(), true, func(text string) documentStructure {
+		return documentStructure{sections: []Section{{Start: 0, End: len(text)}}, blocks: turns}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, chunk := range chunks {
+		if first := sort.Search(len(spans), func(j int) bool { return spans[j].to > chunk.Start }); first < len(spans) && spans[first].from < chunk.End {
+			chunks[i].Metadata.StartTime = spans[first].start
+		}
+		if last := sort.Search(len(spans), func(j int) bool { return spans[j].from >= chunk.End }) - 1; last >= 0 && spans[last].to > chunk.Start {
+			chunks[i].Metadata.EndTime = spans[last].end
+		}
+		seen := make(map[string]bool)
+		for j, turn := range turns {
+			if speaker := speakers[j]; speaker != "" && !seen[speaker] && turn[0] < chunk.End && turn[1] > chunk.Start {
+				seen[speaker] = true
+				chunks[i].Metadata.Speakers = append(chunks[i].Metadata.Speakers, speaker)
+			}
+		}
+	}
+	return chunks, nil
+}
+
+// segmentSpans locates the timed words of segment in its content, which starts at offset, or
+// returns the whole segment if its words have no times
+func segmentSpans(content string, offset int, segment TranscriptSegment) []timedSpan {
+	spans := make([]timedSpan, 0, len(segment.Words))
+	position := 0
+	for _, word := range segment.Words {
+		w := strings.TrimSpace(word.Word)
+		i := strings.Index(content[position:], w)
+		if w == "" || i < 0 {
+			continue
+		}
+		from := position + i
+		position = from + len(w)
+		if word.Start != nil && word.End != nil {
+			spans = append(spans, timedSpan{offset + from, offset + position, *word.Start, *word.End})
+		}
+	}
+	if len(spans) == 0 {
+		return []timedSpan{{offset, offset + len(content), segment.Start, segment.End}}
+	}
+	return spans
+}
@@ -0,0 +1,56 @@
+package semchunk
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const whisperX = `{"segments": [
+	{"start": 0.0, "end": 2.0, "text": " Welcome to the show.", "speaker": "SPEAKER_00",
+	 "words": [{"word": "Welcome", "start": 0.1, "end": 0.5}, {"word": "to", "start": 0.5, "end": 0.6},
+	           {"word": "the", "start": 0.6, "end": 0.7}, {"word": "show.", "start": 0.7, "end": 1.2}]},
+	{"start": 2.0, "end": 4.0, "text": " Today we talk about rivers.", "speaker": "SPEAKER_00"},
+	{"start": 4.5, "end": 6.0, "text": " Thanks for having me.", "speaker": "SPEAKER_01"},
+	{"start": 6.0, "end": 9.0, "text": " Rivers shape valleys over 1000 years.", "speaker": "SPEAKER_00",
+	 "words": [{"word": "Rivers", "start": 6.2, "end": 6.6}, {"word": "shape", "start": 6.6, "end": 7.0},
+	           {"word": "valleys", "start": 7.0, "end": 7.5}, {"word": "over", "start": 7.5, "end": 7.8},
+	           {"word": "1000"}, {"word": "years.", "start": 8.4, "end": 8.9}]}
+]}`
+
+func TestSplitTranscript(t *testing.T) {
+	transcript, err := ReadWhisperX(strings.NewReader(whisperX))
+	assert.NoError(t, err)
+	assert.Len(t, transcript.Segments, 4)
+
+	chunks, err := newWordSplitter(t, 13, 0).SplitTranscript(context.Background(), transcript)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"SPEAKER_00: Welcome to the show. Today we talk about rivers.",
+		"SPEAKER_01: Thanks for having me.\n\nSPEAKER_00: Rivers shape valleys over 1000 years.",
+	}, chunkTexts(chunks))
+	assert.Equal(t, 0.1, chunks[0].Metadata.StartTime)
+	assert.Equal(t, 4.0, chunks[0].Metadata.EndTime)
+	assert.Equal(t, []string{"SPEAKER_00"}, chunks[0].Metadata.Speakers)
+	assert.Equal(t, 4.5, chunks[1].Metadata.StartTime)
+	assert.Equal(t, 8.9, chunks[1].Metadata.EndTime)
+	assert.Equal(t, []string{"SPEAKER_01", "SPEAKER_00"}, chunks[1].Metadata.Speakers)
+
+	// a turn too long for a chunk is split inside
+	chunks, err = newWordSplitter(t, 7, 0).SplitTranscript(context.Background(), transcript)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"SPEAKER_00: Welcome to the show.",
+		"Today we talk about rivers.",
+		"SPEAKER_01: Thanks for having me.",
+		"SPEAKER_00: Rivers shape valleys over 1000 years.",
+	}, chunkTexts(chunks))
+	assert.Equal(t, 2.0, chunks[1].Metadata.StartTime)
+	assert.Equal(t, 4.0, chunks[1].Metadata.EndTime)
+
+	viaFormat, err := newWordSplitter(t, 7, 0).SplitFormat(context.Background(), FormatWhisperX, whisperX)
+	assert.NoError(t, err)
+	assert.Equal(t, chunks, viaFormat)
+}
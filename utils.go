@@ -2,11 +2,14 @@ package semchunk
 
 import (
 	"regexp"
+	"sort"
+	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // LookbehindSplit splits a string at a given splitter, but only if it is preceded by a given string
-// This is a helper function to emulate lookbehind in regex
+// This is a helper function to emulate lookbehind in regex; SplitAfterAny takes several preceders
 func LookbehindSplit(text string, precededBy string, splitter string) []string {
 	escapedPreceder := regexp.QuoteMeta(precededBy)
 	escapedSplitter := regexp.QuoteMeta(splitter)
@@ -22,6 +25,51 @@ func LookbehindSplit(text string, precededBy string, splitter string) []string {
 	return parts
 }
 
+// SplitAfterAny splits text at every occurrence of splitter that directly follows any of
+// preceders, keeping the preceder at the end of the part before it, in one pass over text.
+// Where several preceders end at the same position, the longest one that does not reach into
+// the previous part is taken. An empty splitter splits right after every preceder.
+func SplitAfterAny(text string, preceders []string, splitter string) []string {
+	ordered := make([]string, 0, len(preceders))
+	for _, preceder := range preceders {
+		if preceder != "" {
+			ordered = append(ordered, preceder)
+		}
+	}
+	sort.SliceStable(ordered, func(a, b int) bool { return len(ordered[a]) > len(ordered[b]) })
+
+	parts := make([]string, 0)
+	lastIndex := 0
+	for i := 0; i <= len(text); {
+		j := i
+		if splitter != "" {
+			k := strings.Index(text[i:], splitter)
+			if k < 0 {
+				break
+			}
+			j += k
+		}
+		for _, preceder := range ordered {
+			if j-len(preceder) >= lastIndex && strings.HasSuffix(text[:j], preceder) {
+				parts = append(parts, text[lastIndex:j])
+				lastIndex = j + len(splitter)
+				break
+			}
+		}
+		if splitter != "" {
+			i = j + len(splitter)
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[j:])
+		if size == 0 {
+			break
+		}
+		i = j + size
+	}
+	parts = append(parts, text[lastIndex:])
+	return parts
+}
+
 // IsChinese checks if a string is Chinese
 func IsChinese(text string) bool {
 	if len(text) == 0 {
@@ -67,11 +115,18 @@ func GuessIsChinese(text string, n int) bool {
 	return IsChinese(text[:n])
 }
 
+// ContainsSpace reports whether text contains whitespace, including Unicode space separators
+// such as U+00A0 and U+3000
 func ContainsSpace(text string) bool {
 	for _, r := range text {
-		if unicode.IsSpace(r) {
+		if isSpaceSeparator(r) {
 			return true
 		}
 	}
 	return false
 }
+
+// lineBreaks counts the line breaks in text, treating "\r\n" as a single break
+func lineBreaks(text string) int {
+	return strings.Count(text, "\n") + strings.Count(text, "\r") - strings.Count(text, "\r\n")
+}
@@ -0,0 +1,32 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitAfterAny(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		preceders []string
+		splitter  string
+		want      []string
+	}{
+		{"one preceder", "One. Two. Three", []string{"."}, " ", []string{"One.", "Two.", "Three"}},
+		{"several preceders", "Done? Yes. Great! Ship", []string{".", "?", "!"}, " ", []string{"Done?", "Yes.", "Great!", "Ship"}},
+		{"other spaces are kept", "Mr Smith went home. Then", []string{"."}, " ", []string{"Mr Smith went home.", "Then"}},
+		{"longest preceder", "Wait... what?! No", []string{".", "...", "?!"}, " ", []string{"Wait...", "what?!", "No"}},
+		{"empty splitter", "a;b;c", []string{";"}, "", []string{"a;", "b;", "c"}},
+		{"no match", "no punctuation here", []string{"."}, " ", []string{"no punctuation here"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, SplitAfterAny(tt.text, tt.preceders, tt.splitter))
+			if len(tt.preceders) == 1 {
+				assert.Equal(t, LookbehindSplit(tt.text, tt.preceders[0], tt.splitter), SplitAfterAny(tt.text, tt.preceders, tt.splitter))
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package semchunk
+
+import "fmt"
+
+// validate returns every setting of opts that is out of range or contradicts another one
+func (opts *TextSplitterOption) validate() []error {
+	errs := make([]error, 0)
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	for _, n := range []struct {
+		name  string
+		value int
+	}{
+		{"repair window", opts.RepairWindow},
+		{"sentence snap tolerance", opts.SnapTolerance},
+		{"minimum overlap", opts.MinOverlap},
+		{"annotator concurrency", opts.AnnotatorConcurrency},
+		{"split concurrency", opts.SplitConcurrency},
+		{"context excerpt", opts.ContextExcerptBytes},
+		{"context budget", opts.ContextBudget},
+		{"oversized match threshold", opts.OversizedThreshold},
+		{"maximum split operations", opts.MaxSplitOps},
+		{"maximum chunk bytes", opts.MaxChunkBytes},
+		{"minimum content runes", opts.MinContentRunes},
+		{"minimum blob length", opts.BlobMinLength},
+	} {
+		check(n.value >= 0, "%s must not be negative, got %d", n.name, n.value)
+	}
+	check(opts.Timeout >= 0, "timeout must not be negative, got %v", opts.Timeout)
+	check(opts.RelativeOverlap >= 0 && opts.RelativeOverlap < 1,
+		"relative overlap must be between 0 and 1, got %g", opts.RelativeOverlap)
+	check(opts.EstimationTolerance >= 0 && opts.EstimationTolerance < 1,
+		"estimation tolerance must be between 0 and 1, got %g", opts.EstimationTolerance)
+
+	check(opts.SizeUnit >= SizeTokens && opts.SizeUnit <= SizeReadingMillis, "unknown size unit %d", opts.SizeUnit)
+	check(opts.TOC >= TOCKeep && opts.TOC <= TOCDrop, "unknown table of contents mode %d", opts.TOC)
+	check(opts.InvisibleChars >= InvisibleKeep && opts.InvisibleChars <= InvisibleSplit,
+		"unknown invisible character mode %d", opts.InvisibleChars)
+	check(opts.PatternResolution >= ResolveFirstPattern && opts.PatternResolution <= ResolveLongest,
+		"unknown pattern resolution %d", opts.PatternResolution)
+	check(opts.OversizedMatches >= OversizedEmitWhole && opts.OversizedMatches <= OversizedRelease,
+		"unknown oversized match policy %d", opts.OversizedMatches)
+	check(opts.WholeMatch >= WholeMatchDefault && opts.WholeMatch <= WholeMatchError,
+		"unknown whole match policy %d", opts.WholeMatch)
+	check(opts.WholeMatch != WholeMatchWrap || opts.WholeMatchWrap > 0,
+		"whole match wrap must be positive, got %d", opts.WholeMatchWrap)
+	check(opts.Blobs >= BlobKeep && opts.Blobs <= BlobIsolate, "unknown blob mode %d", opts.Blobs)
+	check(opts.Blobs != BlobTruncate || opts.BlobMinLength == 0 || opts.BlobMinLength > blobTruncateLength,
+		"minimum blob length must be greater than %d to truncate blobs, got %d", blobTruncateLength, opts.BlobMinLength)
+	check(opts.MaxChunkBytes == 0 || opts.MaxChunkBytes > len(opts.ChunkSuffix),
+		"maximum chunk bytes must be greater than the %d bytes of the chunk suffix, got %d", len(opts.ChunkSuffix), opts.MaxChunkBytes)
+	check(isAlgorithmVersion(opts.AlgorithmVersion), "unknown algorithm version %q", opts.AlgorithmVersion)
+	check(opts.DenylistMode >= DenylistMark && opts.DenylistMode <= DenylistDrop,
+		"unknown denylist mode %d", opts.DenylistMode)
+
+	check(opts.NewlineTiers == nil || opts.NewlineTiers.Paragraphs || opts.NewlineTiers.Lines,
+		"newline tiers enable neither paragraphs nor lines")
+	check(opts.LangChain == nil || len(opts.LangChain.Separators) > 0, "LangChain separators are empty")
+	if opts.LangChain != nil && opts.LangChain.err != nil {
+		errs = append(errs, opts.LangChain.err)
+	}
+	check(opts.LangChain == nil || !opts.SemchunkCompat, "LangChain and semchunk compatibility exclude each other")
+	for _, terminator := range opts.SentenceTerminators {
+		check(terminator != "", "sentence terminators must not be empty")
+	}
+	for _, separator := range opts.ClauseSeparators {
+		check(separator != "", "clause separators must not be empty")
+	}
+	for _, separator := range opts.HardBoundaries {
+		check(separator != "", "hard boundaries must not be empty")
+	}
+	for _, marker := range opts.PageMarkers {
+		check(marker != "", "page markers must not be empty")
+	}
+	return errs
+}
@@ -0,0 +1,48 @@
+package semchunk
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidation(t *testing.T) {
+	countWords := func(text string) int { return len(strings.Fields(text)) }
+	tests := []struct {
+		name      string
+		chunkSize int
+		overlap   int
+		opts      []func(*TextSplitterOption)
+		wantErrs  []string
+	}{
+		{"valid", 10, 2, nil, nil},
+		{"negative chunk size", -1, 0, nil, []string{"chunk size must be positive"}},
+		{"overlap equal to chunk size", 10, 10, nil, []string{"overlap must be between 0 and chunkSize"}},
+		{"estimation tolerance set directly", 10, 0, []func(*TextSplitterOption){func(opts *TextSplitterOption) { opts.EstimationTolerance = 1.5 }},
+			[]string{"estimation tolerance must be between 0 and 1, got 1.5"}},
+		{"empty newline tiers", 10, 0, []func(*TextSplitterOption){WithNewlineTiers(false, false)},
+			[]string{"newline tiers"}},
+		{"conflicting modes", 10, 0, []func(*TextSplitterOption){WithLangChainCompat(LangChainOptions{}), WithSemchunkCompat()},
+			[]string{"exclude each other"}},
+		{"all violations", 0, -1, []func(*TextSplitterOption){WithBoundaryRepair(-2), WithTOCDetection(TOCMode(7)), WithPageMarkers("")},
+			[]string{"chunk size must be positive", "overlap must be between", "repair window must not be negative",
+				"unknown table of contents mode 7", "page markers must not be empty"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTextSplitter(tt.chunkSize, tt.overlap, countWords, tt.opts...)
+			if len(tt.wantErrs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			for _, want := range tt.wantErrs {
+				assert.ErrorContains(t, err, want)
+			}
+			assert.Len(t, strings.Split(err.Error(), "\n"), len(tt.wantErrs))
+		})
+	}
+
+	_, err := NewTextSplitter(10, float32(1), countWords)
+	assert.ErrorContains(t, err, "overlap must be between 0 and 1")
+}
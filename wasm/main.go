@@ -0,0 +1,121 @@
+//go:build js && wasm
+
+// Command wasm exposes the splitter to JavaScript. Build it with
+//
+//	GOOS=js GOARCH=wasm go build -o semchunk.wasm ./wasm
+//
+// and load it with wasm_exec.js from the Go distribution, see semchunk.js. It registers a
+// global function semchunkSplit(text, opts) returning the chunks as plain objects, so a web
+// frontend can preview chunk boundaries exactly as the server computes them.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"syscall/js"
+	"unicode/utf8"
+
+	semchunk "github.com/sanbaiw/semtxtsplitter"
+)
+
+func main() {
+	js.Global().Set("semchunkSplit", js.FuncOf(split))
+	// keep the exported function alive
+	select {}
+}
+
+// split implements semchunkSplit(text, opts). opts may set chunkSize (default 100),
+// overlap (a ratio, default 0), preserveURLs, format ("plain", "markdown", ... or "auto") and
+// countTokens, a function returning the token count of a string; without it words are counted,
+// or characters if opts.counter is "chars". Errors are returned as JavaScript
+// Error objects, which loadSemchunk in semchunk.js throws.
+func split(this js.Value, args []js.Value) (result any) {
+	defer func() {
+		if r := recover(); r != nil {
+			result = js.Global().Get("Error").New(toString(r))
+		}
+	}()
+	if len(args) < 1 || args[0].Type() != js.TypeString {
+		panic("semchunkSplit: text must be a string")
+	}
+	text := args[0].String()
+	opts := js.Undefined()
+	if len(args) > 1 {
+		opts = args[1]
+	}
+
+	chunkSize := intOption(opts, "chunkSize", 100)
+	overlap := float32(floatOption(opts, "overlap", 0))
+	countTokens := func(text string) int { return len(strings.Fields(text)) }
+	if stringOption(opts, "counter", "words") == "chars" {
+		countTokens = utf8.RuneCountInString
+	}
+	if fn := option(opts, "countTokens"); fn.Type() == js.TypeFunction {
+		countTokens = func(text string) int { return fn.Invoke(text).Int() }
+	}
+
+	var splitOpts []func(*semchunk.TextSplitterOption)
+	if boolOption(opts, "preserveURLs", false) {
+		splitOpts = append(splitOpts, semchunk.WithPreserveURLs(true))
+	}
+	splitter, err := semchunk.NewTextSplitter(chunkSize, overlap, countTokens, splitOpts...)
+	if err != nil {
+		panic(err)
+	}
+	chunks, err := splitter.SplitFormat(context.Background(), stringOption(opts, "format", semchunk.FormatPlain), text)
+	if err != nil {
+		panic(err)
+	}
+
+	data, err := json.Marshal(chunks)
+	if err != nil {
+		panic(err)
+	}
+	return js.Global().Get("JSON").Call("parse", string(data))
+}
+
+func option(opts js.Value, name string) js.Value {
+	if opts.Type() != js.TypeObject {
+		return js.Undefined()
+	}
+	return opts.Get(name)
+}
+
+func intOption(opts js.Value, name string, def int) int {
+	if v := option(opts, name); v.Type() == js.TypeNumber {
+		return v.Int()
+	}
+	return def
+}
+
+func floatOption(opts js.Value, name string, def float64) float64 {
+	if v := option(opts, name); v.Type() == js.TypeNumber {
+		return v.Float()
+	}
+	return def
+}
+
+func boolOption(opts js.Value, name string, def bool) bool {
+	if v := option(opts, name); v.Type() == js.TypeBoolean {
+		return v.Bool()
+	}
+	return def
+}
+
+func stringOption(opts js.Value, name string, def string) string {
+	if v := option(opts, name); v.Type() == js.TypeString {
+		return v.String()
+	}
+	return def
+}
+
+func toString(v any) string {
+	if err, ok := v.(error); ok {
+		return err.Error()
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return "semchunkSplit failed"
+}
@@ -0,0 +1,14 @@
+// Loads semchunk.wasm and resolves to the split function it exports.
+// wasm_exec.js from "$(go env GOROOT)/misc/wasm" (or lib/wasm) must be loaded first.
+export async function loadSemchunk(url = "semchunk.wasm") {
+  const go = new Go();
+  const { instance } = await WebAssembly.instantiateStreaming(fetch(url), go.importObject);
+  go.run(instance);
+  return (text, opts = {}) => {
+    const result = globalThis.semchunkSplit(text, opts);
+    if (result instanceof Error) {
+      throw result;
+    }
+    return result;
+  };
+}
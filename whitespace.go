@@ -0,0 +1,215 @@
+package semchunk
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// whitespaceClass matches ASCII whitespace and every Unicode space separator, such as the
+// non-breaking space U+00A0 and the ideographic space U+3000 common in PDF and CJK text
+const whitespaceClass = `[\s\p{Z}\x{85}]`
+
+var whitespaceRegex = regexp.MustCompile(whitespaceClass + `+`)
+
+// WithWhitespace replaces the characters treated as whitespace when splitting between words,
+// which by default are ASCII whitespace and all Unicode space separators
+func WithWhitespace(chars string) func(*TextSplitterOption) {
+	return func(opts *TextSplitterOption) {
+		if opts == nil {
+			opts = &TextSplitterOption{}
+		}
+		opts.Whitespace = chars
+		opts.compileWhitespace()
+	}
+}
+
+// compileWhitespace prepares the whitespace definition used by semanticSplit from the
+// Whitespace and InvisibleChars options
+func (opts *TextSplitterOption) compileWhitespace() {
+	class, isSpace := whitespaceClass, isSpaceSeparator
+	if opts.AlgorithmVersion == AlgorithmV1 {
+		class, isSpace = v1WhitespaceClass, v1IsSpace
+	}
+	if chars := opts.Whitespace; chars != "" {
+		class = runeClass(chars)
+		isSpace = func(r rune) bool { return strings.ContainsRune(chars, r) }
+	}
+	if opts.InvisibleChars == InvisibleSplit {
+		class = strings.TrimSuffix(class, "]") + `\x{200b}]`
+		inner := isSpace
+		isSpace = func(r rune) bool { return r == zeroWidthSpace || inner(r) }
+	}
+	opts.whitespaceClass = class
+	opts.whitespaceRegex = regexp.MustCompile(class + `+`)
+	opts.isSpace = isSpace
+}
+
+// runeClass returns a regular expression character class matching any rune of chars
+func runeClass(chars string) string {
+	var b strings.Builder
+	b.WriteString("[")
+	for _, r := range chars {
+		fmt.Fprintf(&b, `\x{%x}`, r)
+	}
+	b.WriteString("]")
+	return b.String()
+}
+
+// splitRules holds the configurable parts of semanticSplit
+type splitRules struct {
+	preservePatterns []*regexp.Regexp
+	pageMarkers      []string
+	// newlineTiers replaces the newline tier, see WithNewlineTiers
+	newlineTiers *NewlineTiers
+	// detector and minScore configure the custom boundary tier
+	detector BoundaryDetector
+	minScore float64
+	// patternResolution decides between overlapping matches of preservePatterns
+	patternResolution PatternResolution
+	// blobMinLength enables the blob tier if positive, see WithBlobs
+	blobMinLength int
+	// numericTables enables the table tier, see WithNumericTables
+	numericTables bool
+	// sentenceTerminators and clauseSeparators replace the default punctuation when not nil,
+	// see WithSentenceTerminators and WithClauseSeparators
+	sentenceTerminators []string
+	clauseSeparators    []string
+	// noClauses disables splitting at clause separators, see WithClauseSplitting
+	noClauses bool
+	// dividers enables the divider tier, see WithDividers
+	dividers bool
+	// scriptSegmentation enables the script tier, see WithScriptSegmentation
+	scriptSegmentation bool
+	// v1 splits at punctuation as AlgorithmV1 does, see WithAlgorithmVersion
+	v1 bool
+	// whitespaceClass, whitespace and isSpace override the default whitespace definition when set
+	whitespaceClass string
+	whitespace      *regexp.Regexp
+	isSpace         func(rune) bool
+}
+
+func (c *TextSplitter) splitRules() splitRules {
+	blobMinLength := 0
+	if c.opts.Blobs == BlobIsolate {
+		blobMinLength = c.opts.BlobMinLength
+		if blobMinLength <= 0 {
+			blobMinLength = defaultBlobMinLength
+		}
+	}
+	return splitRules{
+		blobMinLength:       blobMinLength,
+		preservePatterns:    c.opts.PreservePatterns,
+		pageMarkers:         c.opts.PageMarkers,
+		newlineTiers:        c.opts.NewlineTiers,
+		patternResolution:   c.opts.PatternResolution,
+		detector:            c.opts.BoundaryDetector,
+		minScore:            c.opts.BoundaryMinScore,
+		dividers:            c.opts.Dividers,
+		numericTables:       c.opts.NumericTables,
+		sentenceTerminators: c.opts.SentenceTerminators,
+		clauseSeparators:    c.opts.ClauseSeparators,
+		noClauses:           c.opts.NoClauseSplitting,
+		scriptSegmentation:  c.opts.ScriptSegmentation,
+		v1:                  c.opts.AlgorithmVersion == AlgorithmV1,
+		whitespaceClass:     c.opts.whitespaceClass,
+		whitespace:          c.opts.whitespaceRegex,
+		isSpace:             c.opts.isSpace,
+	}
+}
+
+// terminators returns the sentence terminators
+func (r splitRules) terminators() []string {
+	if r.sentenceTerminators != nil {
+		return r.sentenceTerminators
+	}
+	return sentenceTerminators
+}
+
+// separators returns the clause separators, none if clause splitting is disabled
+func (r splitRules) separators() []string {
+	if r.noClauses {
+		return []string{}
+	}
+	if r.clauseSeparators != nil {
+		return r.clauseSeparators
+	}
+	return clauseSeparators
+}
+
+// precederTiers returns the sets of punctuation whitespace is split after, see precederTiers
+func (r splitRules) precederTiers() [][]string {
+	if r.v1 {
+		return v1PrecederTiers(r.nonWhitespaceSplitters())
+	}
+	if r.sentenceTerminators == nil && r.clauseSeparators == nil && !r.noClauses {
+		return precederTiers
+	}
+	return newPrecederTiers(r.terminators(), r.separators())
+}
+
+// nonWhitespaceSplitters returns the punctuation split at in text without whitespace, in order
+// of preference
+func (r splitRules) nonWhitespaceSplitters() []string {
+	if r.sentenceTerminators == nil && r.clauseSeparators == nil && !r.noClauses {
+		return nonWhitespaceSemanticSplitters
+	}
+	return append(append([]string(nil), r.terminators()...), r.separators()...)
+}
+
+// fullWidthSplitters returns the full-width punctuation split at, in order of preference
+func (r splitRules) fullWidthSplitters() []string {
+	if r.noClauses {
+		return fullWidthSentenceTerminators
+	}
+	return fullWidthNonWhitespaceSemanticSpliters
+}
+
+// splitPunctuation splits text at a punctuation splitter, see splitPunctuation
+func (r splitRules) splitPunctuation(text string, splitter string) textSplit {
+	if r.v1 {
+		return v1SplitPunctuation(text, splitter, r.punctuationLevel(splitter))
+	}
+	return splitPunctuation(text, splitter, r.punctuationLevel(splitter))
+}
+
+// attachMarkers joins list item markers to their items, see attachMarkers
+func (r splitRules) attachMarkers(parts []string, splitter string) []string {
+	if r.v1 {
+		return parts
+	}
+	return attachMarkers(parts, splitter)
+}
+
+func (r splitRules) whitespaceRegex() *regexp.Regexp {
+	if r.whitespace != nil {
+		return r.whitespace
+	}
+	return whitespaceRegex
+}
+
+// precededWhitespace returns a regular expression matching any of preceders followed by one
+// whitespace rune
+func (r splitRules) precededWhitespace(preceders ...string) *regexp.Regexp {
+	class := whitespaceClass
+	if r.whitespaceClass != "" {
+		class = r.whitespaceClass
+	}
+	quoted := make([]string, len(preceders))
+	for i, preceder := range preceders {
+		quoted[i] = regexp.QuoteMeta(preceder)
+	}
+	return regexp.MustCompile(`(?:` + strings.Join(quoted, "|") + `)(` + class + `)`)
+}
+
+func (r splitRules) containsSpace(text string) bool {
+	if r.isSpace != nil {
+		return strings.IndexFunc(text, r.isSpace) >= 0
+	}
+	return ContainsSpace(text)
+}
+
+func isSpaceSeparator(r rune) bool {
+	return unicode.IsSpace(r) || unicode.Is(unicode.Z, r)
+}
@@ -0,0 +1,36 @@
+package semchunk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnicodeWhitespace(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		splitter string
+		splits   []string
+	}{
+		{"no-break space", "one two three", " ", []string{"one", "two", "three"}},
+		{"ideographic space", "一二　三四", "　", []string{"一二", "三四"}},
+		{"after sentence end", "One two. Three four.", " ", []string{"One two.", "Three four."}},
+	}
+	for _, tt := range tests {
+		splitter, isWhitespace, splits := innerSplit(tt.text, nil)
+		assert.Equal(t, tt.splitter, splitter, tt.name)
+		assert.True(t, isWhitespace, tt.name)
+		assert.Equal(t, tt.splits, splits, tt.name)
+	}
+	assert.True(t, ContainsSpace("a b"))
+}
+
+func TestWithWhitespace(t *testing.T) {
+	splitter, err := NewTextSplitter(1, 0, func(text string) int { return 1 }, WithWhitespace("_"))
+	assert.NoError(t, err)
+
+	ts := semanticSplit("one_two three", splitter.splitRules())
+	assert.Equal(t, "_", ts.splitter)
+	assert.Equal(t, []string{"one", "two three"}, ts.splits)
+}